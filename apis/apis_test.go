@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/daos"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/dbtest"
@@ -162,3 +163,11 @@ func checkHooksResponse(t *testing.T, w http.ResponseWriter, r *http.Request, ho
 		}
 	}
 }
+
+// runMigrations applies the db upgrade steps to the loaded fixtures
+// (some of them are stored in their legacy format, eg. the "col2" entity data).
+func runMigrations(t *testing.T) {
+	if _, err := daos.NewMigrationDAO(TestSession).Run(); err != nil {
+		t.Fatal("Failed to apply the db migrations", err)
+	}
+}
@@ -1,6 +1,9 @@
 package apis
 
 import (
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -24,6 +27,9 @@ const (
 
 	// TokenIdentityUser specify "key" token idenity model.
 	TokenIdentityKey = "key"
+
+	// keyLastUsedInterval specify the min interval (in seconds) between two key `last_used` updates.
+	keyLastUsedInterval = 60
 )
 
 // AuthApi defines auth api services
@@ -31,6 +37,7 @@ type AuthApi struct {
 	router       *routing.Router
 	mongoSession *mgo.Session
 	dao          *daos.UserDAO
+	limiter      utils.RateLimiter
 }
 
 // InitAuthApi sets up the routing of auth endpoints and the corresponding handlers.
@@ -39,9 +46,17 @@ func InitAuthApi(rg *routing.Router, session *mgo.Session) {
 		router:       rg,
 		mongoSession: session,
 		dao:          daos.NewUserDAO(session),
+		limiter:      newRateLimiter(session),
 	}
 
 	rg.Post("/auth", api.auth)
+	rg.Post("/auth/refresh", api.refresh)
+	rg.Post("/auth/logout", authenticateToken(session), usersOnly, api.logout)
+	rg.Post("/auth/2fa", api.verifyTwoFactor)
+	rg.Post("/auth/2fa/enroll", authenticateToken(session), usersOnly, api.enrollTwoFactor)
+	rg.Post("/auth/2fa/enable", authenticateToken(session), usersOnly, api.enableTwoFactor)
+	rg.Post("/auth/2fa/disable", authenticateToken(session), usersOnly, api.disableTwoFactor)
+	rg.Post("/auth/2fa/recovery-codes", authenticateToken(session), usersOnly, api.renewRecoveryCodes)
 	rg.Post("/forgotten-password", api.sendResetEmail)
 	rg.Post("/reset-password/<hash>", api.resetPassword)
 }
@@ -60,23 +75,95 @@ func (api *AuthApi) auth(c *routing.Context) error {
 		return utils.NewBadRequestError("Oops, something went wrong while creating the auth token.", readErr)
 	}
 
+	ipKey := "auth_ip:" + clientIP(c)
+	userKey := "auth_user:" + strings.ToLower(data.Username)
+
+	if limitErr := api.checkRateLimit(ipKey, userKey); limitErr != nil {
+		return limitErr
+	}
+
 	user, userErr := api.dao.Authenticate(data.Username, data.Password)
 	if userErr != nil {
+		if limitErr := api.hitRateLimit(ipKey, userKey); limitErr != nil {
+			return limitErr
+		}
+
 		return utils.NewBadRequestError("Invalid username or password.", nil)
 	}
 
-	exp := time.Now().Add(time.Hour * time.Duration(app.Config.GetInt64("userTokenExpire"))).Unix()
+	// the auth tokens are issued after a successful `/auth/2fa` verification
+	// (the attempts are reset there, so that the codes couldn't be brute-forced by re-authenticating)
+	if user.NeedsTwoFactor() {
+		return api.writeTwoFactorChallenge(c, user)
+	}
 
-	token, err := user.NewAuthToken(exp)
-	if err != nil {
-		return utils.NewBadRequestError("Oops, something went wrong while creating the auth token.", err)
+	// the ip attempts are not reset to prevent bypassing the limit with a known account
+	api.resetRateLimit(userKey)
+
+	return api.writeNewSession(c, user)
+}
+
+// refresh api endpoint handler for exchanging a refresh token with new access and refresh tokens.
+func (api *AuthApi) refresh(c *routing.Context) error {
+	data := &struct {
+		RefreshToken string `json:"refresh_token" form:"refresh_token"`
+	}{}
+	if readErr := c.Read(&data); readErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while refreshing the auth token.", readErr)
 	}
 
-	return c.Write(map[string]interface{}{
-		"token":  token,
-		"expire": exp,
-		"user":   user,
-	})
+	userSession, refreshToken, sessionErr := daos.NewUserSessionDAO(api.mongoSession).Refresh(data.RefreshToken)
+	if sessionErr != nil {
+		return utils.NewBadRequestError("Invalid or expired refresh token.", nil)
+	}
+
+	user, userErr := api.dao.GetByID(userSession.UserID.Hex(), bson.M{"status": models.UserStatusActive})
+	if userErr != nil {
+		return utils.NewBadRequestError("Invalid or expired refresh token.", nil)
+	}
+
+	return api.writeAuthTokens(c, user, userSession, refreshToken)
+}
+
+// logout api endpoint handler for revoking the current user session
+// (or all user sessions and tokens if `all` is set).
+func (api *AuthApi) logout(c *routing.Context) error {
+	data := &struct {
+		All bool `json:"all" form:"all"`
+	}{}
+	// the request body is optional
+	if readErr := c.Read(&data); readErr != nil && readErr != io.EOF {
+		return utils.NewBadRequestError("Oops, something went wrong while revoking the auth token.", readErr)
+	}
+
+	identityID, _ := c.Get("identityID").(string)
+	sessionID, _ := c.Get("identitySession").(string)
+
+	if data.All {
+		user, userErr := api.dao.GetByID(identityID)
+		if userErr != nil {
+			return utils.NewNotFoundError("Inactive or missing user.")
+		}
+
+		if revokeErr := api.dao.RevokeTokens(user); revokeErr != nil {
+			return utils.NewBadRequestError("Oops, something went wrong while revoking the auth token.", revokeErr)
+		}
+	} else {
+		sessionDAO := daos.NewUserSessionDAO(api.mongoSession)
+
+		userSession, sessionErr := sessionDAO.GetByID(sessionID)
+		if sessionErr != nil {
+			return utils.NewBadRequestError("The auth token is not bound to a session. Set `all` to revoke all user tokens.", nil)
+		}
+
+		if revokeErr := sessionDAO.Revoke(userSession); revokeErr != nil {
+			return utils.NewBadRequestError("Oops, something went wrong while revoking the auth token.", revokeErr)
+		}
+	}
+
+	c.Response.WriteHeader(http.StatusNoContent)
+
+	return nil
 }
 
 // sendResetEmail api endpoint handler for sending a reset user password email.
@@ -88,6 +175,15 @@ func (api *AuthApi) sendResetEmail(c *routing.Context) error {
 		return utils.NewBadRequestError("Oops, an error occurred while generating new reset password key.", readErr)
 	}
 
+	// limit all reset requests (not only the failed ones) to prevent email bombing
+	limitErr := api.hitRateLimit(
+		"reset_ip:"+clientIP(c),
+		"reset_user:"+strings.ToLower(data.Username),
+	)
+	if limitErr != nil {
+		return limitErr
+	}
+
 	user, err := api.dao.GetByUsername(data.Username, bson.M{"status": models.UserStatusActive})
 	if err != nil {
 		return utils.NewNotFoundError("Inactive or missing user.")
@@ -168,6 +264,155 @@ func (api *AuthApi) resetPassword(c *routing.Context) error {
 	return c.Write(updatedUser)
 }
 
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// writeNewSession creates a new user session and writes its auth tokens response
+// (see `writeAuthTokens()`).
+func (api *AuthApi) writeNewSession(c *routing.Context, user *models.User, recoveryCodes ...string) error {
+	sessionExp := time.Now().Add(time.Hour * time.Duration(app.Config.GetInt64("userTokenExpire"))).Unix()
+
+	userSession, refreshToken, sessionErr := daos.NewUserSessionDAO(api.mongoSession).Create(user, sessionExp)
+	if sessionErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while creating the auth token.", sessionErr)
+	}
+
+	return api.writeAuthTokens(c, user, userSession, refreshToken, recoveryCodes...)
+}
+
+// writeAuthTokens writes the response of a new short-lived user access token
+// bound to the provided session and the session refresh token.
+// The optional recovery codes are included only when the two-factor authentication was just enabled.
+func (api *AuthApi) writeAuthTokens(c *routing.Context, user *models.User, userSession *models.UserSession, refreshToken string, recoveryCodes ...string) error {
+	exp := time.Now().Add(time.Minute * time.Duration(app.Config.GetInt64("userAccessTokenExpire"))).Unix()
+
+	// the access token shouldn't outlive its session
+	if exp > userSession.Expires {
+		exp = userSession.Expires
+	}
+
+	token, err := user.NewAuthToken(exp, userSession)
+	if err != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while creating the auth token.", err)
+	}
+
+	data := map[string]interface{}{
+		"token":          token,
+		"expire":         exp,
+		"refresh_token":  refreshToken,
+		"refresh_expire": userSession.Expires,
+		"user":           user,
+	}
+
+	if len(recoveryCodes) > 0 {
+		data["recovery_codes"] = recoveryCodes
+	}
+
+	return c.Write(data)
+}
+
+// newRateLimiter creates the sign in and reset password requests limiter
+// based on the app config (returns nil if the rate limit is disabled).
+func newRateLimiter(session *mgo.Session) utils.RateLimiter {
+	options := utils.RateLimitOptions{
+		MaxAttempts: app.Config.GetInt("rateLimit.maxAttempts"),
+		Window:      time.Minute * time.Duration(app.Config.GetInt64("rateLimit.window")),
+		Lockout:     time.Minute * time.Duration(app.Config.GetInt64("rateLimit.lockout")),
+	}
+
+	switch app.Config.GetString("rateLimit.driver") {
+	case "memory":
+		return utils.NewMemoryRateLimiter(options)
+	case "mongo":
+		return daos.NewRateLimitDAO(session, options)
+	}
+
+	return nil
+}
+
+// checkRateLimit returns a 429 api error if any of the provided limiter keys is locked.
+func (api *AuthApi) checkRateLimit(keys ...string) error {
+	if api.limiter == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		retryAfter, err := api.limiter.Check(key)
+		if err != nil {
+			// don't block the requests on a limiter storage failure
+			log.Printf("Rate limit check error: %v", err)
+			continue
+		}
+
+		if retryAfter > 0 {
+			return utils.NewTooManyRequestsError("", retryAfter)
+		}
+	}
+
+	return nil
+}
+
+// hitRateLimit registers a new attempt for each of the provided limiter keys
+// and returns a 429 api error if any of them gets locked.
+func (api *AuthApi) hitRateLimit(keys ...string) error {
+	if api.limiter == nil {
+		return nil
+	}
+
+	var maxRetryAfter time.Duration
+
+	for _, key := range keys {
+		retryAfter, err := api.limiter.Hit(key)
+		if err != nil {
+			// don't block the requests on a limiter storage failure
+			log.Printf("Rate limit hit error: %v", err)
+			continue
+		}
+
+		if retryAfter > maxRetryAfter {
+			maxRetryAfter = retryAfter
+		}
+	}
+
+	if maxRetryAfter > 0 {
+		return utils.NewTooManyRequestsError("", maxRetryAfter)
+	}
+
+	return nil
+}
+
+// resetRateLimit clears the registered attempts of the provided limiter keys.
+func (api *AuthApi) resetRateLimit(keys ...string) {
+	if api.limiter == nil {
+		return
+	}
+
+	for _, key := range keys {
+		if err := api.limiter.Reset(key); err != nil {
+			log.Printf("Rate limit reset error: %v", err)
+		}
+	}
+}
+
+// clientIP returns the request client ip address
+// (read from the `rateLimit.ipHeader` request header, if set).
+func clientIP(c *routing.Context) string {
+	if header := app.Config.GetString("rateLimit.ipHeader"); header != "" {
+		// the first address is the original client one
+		if ip := strings.TrimSpace(strings.Split(c.Request.Header.Get(header), ",")[0]); ip != "" {
+			return ip
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return c.Request.RemoteAddr
+	}
+
+	return ip
+}
+
 // -------------------------------------------------------------------
 // • Routing handlers
 // -------------------------------------------------------------------
@@ -217,7 +462,7 @@ func tokenHandler(session *mgo.Session) auth.JWTTokenHandler {
 	return func(c *routing.Context, j *jwt.Token) error {
 		claims := j.Claims.(jwt.MapClaims)
 
-		var id, model string
+		var id, model, sessionID string
 
 		if claims["id"] != nil {
 			id, _ = claims["id"].(string)
@@ -227,6 +472,11 @@ func tokenHandler(session *mgo.Session) auth.JWTTokenHandler {
 			model, _ = claims["model"].(string)
 		}
 
+		// the challenge tokens are valid only for the `/auth/2fa` verification
+		if model == models.UserTwoFactorChallengeModel {
+			return utils.NewApiError(http.StatusUnauthorized, "Invalid auth token.", nil)
+		}
+
 		var accessData map[string][]string
 
 		if model == TokenIdentityKey { // key
@@ -237,7 +487,24 @@ func tokenHandler(session *mgo.Session) auth.JWTTokenHandler {
 				return utils.NewApiError(http.StatusForbidden, "Access rules can not be fetched.", nil)
 			}
 
-			accessData = key.Access
+			// tokens that were rotated out (after their grace period)
+			if !key.IsValidToken(j.Raw) {
+				return utils.NewApiError(http.StatusUnauthorized, "The auth token has been revoked.", nil)
+			}
+
+			if key.IsExpired() {
+				return utils.NewApiError(http.StatusUnauthorized, "The API key has expired.", nil)
+			}
+
+			// throttle the last used db writes
+			if time.Now().Unix()-key.LastUsed >= keyLastUsedInterval {
+				keyDAO.UpdateLastUsed(key)
+			}
+
+			accessData, err = daos.NewRoleDAO(session).EffectiveAccess(key.Roles, key.Access)
+			if err != nil {
+				return utils.NewApiError(http.StatusForbidden, "Access rules can not be fetched.", nil)
+			}
 		} else { // user
 			model = TokenIdentityUser // reset
 
@@ -248,18 +515,40 @@ func tokenHandler(session *mgo.Session) auth.JWTTokenHandler {
 				return utils.NewApiError(http.StatusForbidden, "Access rules can not be fetched.", nil)
 			}
 
-			accessData = user.Access
+			// --- check the revocation list
+			iat, _ := claims["iat"].(float64)
+			if user.IsTokenRevoked(int64(iat)) {
+				return utils.NewApiError(http.StatusUnauthorized, "The auth token has been revoked.", nil)
+			}
+
+			// tokens issued before the refresh tokens introduction are not bound to a session
+			if claims["jti"] != nil {
+				sessionID, _ = claims["jti"].(string)
+
+				userSession, sessionErr := daos.NewUserSessionDAO(session).GetByID(sessionID, bson.M{"user_id": user.ID})
+				if sessionErr != nil || userSession.Revoked {
+					return utils.NewApiError(http.StatusUnauthorized, "The auth token has been revoked.", nil)
+				}
+			}
+			// ---
+
+			accessData, err = daos.NewRoleDAO(session).EffectiveAccess(user.Roles, user.Access)
+			if err != nil {
+				return utils.NewApiError(http.StatusForbidden, "Access rules can not be fetched.", nil)
+			}
 		}
 
 		c.Set("identityID", id)
 		c.Set("identityModel", model)
 		c.Set("identityAccess", accessData)
+		c.Set("identitySession", sessionID)
 
 		return nil
 	}
 }
 
 // canAccess checks whether the authenticated identity is allowed to access a request group and action.
+// The identity access is the union of its roles and own permissions (see `tokenHandler()`).
 func canAccess(c *routing.Context, group string, action string) error {
 	accessData := map[string][]string{}
 	if c.Get("identityAccess") != nil {
@@ -307,6 +596,21 @@ GROUP_LOOP:
 	return ids
 }
 
+// getRevisionAuthor returns the authenticated request identity as entity revision author.
+func getRevisionAuthor(c *routing.Context) models.RevisionAuthor {
+	author := models.RevisionAuthor{}
+
+	if c.Get("identityID") != nil {
+		author.ID, _ = c.Get("identityID").(string)
+	}
+
+	if c.Get("identityModel") != nil {
+		author.Model, _ = c.Get("identityModel").(string)
+	}
+
+	return author
+}
+
 // isUser checks whether the current request token represents a user or an api key.
 func isUser(c *routing.Context) bool {
 	if c.Get("identityModel") != nil {
@@ -0,0 +1,267 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// twoFactorCodeForm defines the two-factor code request data.
+type twoFactorCodeForm struct {
+	Code string `json:"code" form:"code"`
+}
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// verifyTwoFactor api endpoint handler for completing the two-factor authentication
+// step with a TOTP or recovery code. If the two-factor authentication is required
+// but not enabled yet, the code confirms the enrollment returned with the challenge.
+func (api *AuthApi) verifyTwoFactor(c *routing.Context) error {
+	data := &struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token"`
+		Code           string `json:"code" form:"code"`
+	}{}
+	if readErr := c.Read(&data); readErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while verifying the two-factor authentication code.", readErr)
+	}
+
+	userID, tokenErr := parseTwoFactorChallengeToken(data.ChallengeToken)
+	if tokenErr != nil {
+		return utils.NewBadRequestError("Invalid or expired two-factor authentication challenge.", nil)
+	}
+
+	user, userErr := api.dao.GetByID(userID, bson.M{"status": models.UserStatusActive})
+	if userErr != nil {
+		return utils.NewBadRequestError("Invalid or expired two-factor authentication challenge.", nil)
+	}
+
+	// share the sign in attempts limit to prevent codes brute-forcing
+	ipKey := "auth_ip:" + clientIP(c)
+	userKey := "auth_user:" + strings.ToLower(user.Username)
+
+	if limitErr := api.checkRateLimit(ipKey, userKey); limitErr != nil {
+		return limitErr
+	}
+
+	if user.TwoFactorEnabled {
+		if verifyErr := api.dao.VerifyTwoFactor(user, data.Code); verifyErr != nil {
+			if limitErr := api.hitRateLimit(ipKey, userKey); limitErr != nil {
+				return limitErr
+			}
+
+			return utils.NewBadRequestError("Invalid two-factor authentication code.", nil)
+		}
+
+		api.resetRateLimit(userKey)
+
+		return api.writeNewSession(c, user)
+	}
+
+	if !user.RequireTwoFactor {
+		return utils.NewBadRequestError("Invalid or expired two-factor authentication challenge.", nil)
+	}
+
+	// complete the required enrollment
+	recoveryCodes, enableErr := api.dao.EnableTwoFactor(user, data.Code)
+	if enableErr != nil {
+		if limitErr := api.hitRateLimit(ipKey, userKey); limitErr != nil {
+			return limitErr
+		}
+
+		return utils.NewBadRequestError("Invalid two-factor authentication code.", nil)
+	}
+
+	api.resetRateLimit(userKey)
+
+	return api.writeNewSession(c, user, recoveryCodes...)
+}
+
+// enrollTwoFactor api endpoint handler for generating a new TOTP secret of the authenticated user.
+func (api *AuthApi) enrollTwoFactor(c *routing.Context) error {
+	user, userErr := api.identityUser(c)
+	if userErr != nil {
+		return utils.NewNotFoundError("Inactive or missing user.")
+	}
+
+	if user.TwoFactorEnabled {
+		return utils.NewBadRequestError("Two-factor authentication is already enabled.", nil)
+	}
+
+	secret, enrollErr := api.dao.EnrollTwoFactor(user)
+	if enrollErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while enrolling the two-factor authentication.", enrollErr)
+	}
+
+	return c.Write(map[string]interface{}{
+		"secret": secret,
+		"uri":    user.TwoFactorProvisioningURI(secret),
+	})
+}
+
+// enableTwoFactor api endpoint handler for confirming the authenticated user
+// TOTP enrollment and returning the user recovery codes.
+func (api *AuthApi) enableTwoFactor(c *routing.Context) error {
+	user, userErr := api.identityUser(c)
+	if userErr != nil {
+		return utils.NewNotFoundError("Inactive or missing user.")
+	}
+
+	if user.TwoFactorEnabled {
+		return utils.NewBadRequestError("Two-factor authentication is already enabled.", nil)
+	}
+
+	form := &twoFactorCodeForm{}
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while enabling the two-factor authentication.", readErr)
+	}
+
+	recoveryCodes, enableErr := api.dao.EnableTwoFactor(user, form.Code)
+	if enableErr != nil {
+		return utils.NewBadRequestError(enableErr.Error(), nil)
+	}
+
+	return c.Write(map[string]interface{}{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// disableTwoFactor api endpoint handler for disabling the authenticated user two-factor authentication.
+func (api *AuthApi) disableTwoFactor(c *routing.Context) error {
+	user, userErr := api.identityUser(c)
+	if userErr != nil {
+		return utils.NewNotFoundError("Inactive or missing user.")
+	}
+
+	if !user.TwoFactorEnabled {
+		return utils.NewBadRequestError("Two-factor authentication is not enabled.", nil)
+	}
+
+	if user.RequireTwoFactor {
+		return utils.NewApiError(http.StatusForbidden, "Two-factor authentication is required for your account and can't be disabled.", nil)
+	}
+
+	form := &twoFactorCodeForm{}
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while disabling the two-factor authentication.", readErr)
+	}
+
+	if verifyErr := api.dao.VerifyTwoFactor(user, form.Code); verifyErr != nil {
+		return utils.NewBadRequestError("Invalid two-factor authentication code.", nil)
+	}
+
+	if disableErr := api.dao.DisableTwoFactor(user); disableErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while disabling the two-factor authentication.", disableErr)
+	}
+
+	c.Response.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// renewRecoveryCodes api endpoint handler for replacing the authenticated user recovery codes.
+func (api *AuthApi) renewRecoveryCodes(c *routing.Context) error {
+	user, userErr := api.identityUser(c)
+	if userErr != nil {
+		return utils.NewNotFoundError("Inactive or missing user.")
+	}
+
+	if !user.TwoFactorEnabled {
+		return utils.NewBadRequestError("Two-factor authentication is not enabled.", nil)
+	}
+
+	form := &twoFactorCodeForm{}
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while renewing the recovery codes.", readErr)
+	}
+
+	if verifyErr := api.dao.VerifyTwoFactor(user, form.Code); verifyErr != nil {
+		return utils.NewBadRequestError("Invalid two-factor authentication code.", nil)
+	}
+
+	recoveryCodes, renewErr := api.dao.RenewRecoveryCodes(user)
+	if renewErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while renewing the recovery codes.", renewErr)
+	}
+
+	return c.Write(map[string]interface{}{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// writeTwoFactorChallenge writes the response of a new two-factor authentication challenge token.
+// If the two-factor authentication is required but not enabled yet,
+// the response also contains a new TOTP secret to enroll.
+func (api *AuthApi) writeTwoFactorChallenge(c *routing.Context, user *models.User) error {
+	exp := time.Now().Add(time.Minute * time.Duration(app.Config.GetInt64("twoFactor.challengeExpire"))).Unix()
+
+	token, tokenErr := user.NewTwoFactorChallengeToken(exp)
+	if tokenErr != nil {
+		return utils.NewBadRequestError("Oops, something went wrong while creating the auth token.", tokenErr)
+	}
+
+	data := map[string]interface{}{
+		"challenge_token":  token,
+		"challenge_expire": exp,
+		"enrollment":       nil,
+	}
+
+	if !user.TwoFactorEnabled {
+		secret, enrollErr := api.dao.EnrollTwoFactor(user)
+		if enrollErr != nil {
+			return utils.NewBadRequestError("Oops, something went wrong while enrolling the two-factor authentication.", enrollErr)
+		}
+
+		data["enrollment"] = map[string]interface{}{
+			"secret": secret,
+			"uri":    user.TwoFactorProvisioningURI(secret),
+		}
+	}
+
+	return c.Write(data)
+}
+
+// identityUser returns the active user model of the authenticated request identity.
+func (api *AuthApi) identityUser(c *routing.Context) (*models.User, error) {
+	identityID, _ := c.Get("identityID").(string)
+
+	return api.dao.GetByID(identityID, bson.M{"status": models.UserStatusActive})
+}
+
+// parseTwoFactorChallengeToken validates the provided challenge token and returns its user id.
+func parseTwoFactorChallengeToken(token string) (string, error) {
+	parsedToken, parseErr := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != app.Config.GetString("jwt.signingMethod") {
+			return nil, errors.New("Invalid token signing method.")
+		}
+
+		return []byte(app.Config.GetString("jwt.verificationKey")), nil
+	})
+	if parseErr != nil || !parsedToken.Valid {
+		return "", errors.New("Invalid or expired challenge token.")
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+
+	if model, _ := claims["model"].(string); model != models.UserTwoFactorChallengeModel {
+		return "", errors.New("Invalid challenge token.")
+	}
+
+	id, _ := claims["id"].(string)
+
+	return id, nil
+}
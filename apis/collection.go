@@ -43,6 +43,10 @@ func (api *CollectionApi) index(c *routing.Context) error {
 	// --- fetch search data
 	searchFields := []string{"name", "title", "create_hook", "update_hook", "delete_hook", "created", "modified"}
 	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
 	// ---
 
 	// --- fetch sort data
@@ -94,7 +98,7 @@ func (api *CollectionApi) create(c *routing.Context) error {
 
 	api.setCollectionAccessGroup(model)
 
-	return c.Write(model)
+	return c.Write(&collectionWithSecret{model, model.HookSecret})
 }
 
 // update api handler for updating an existing collection item
@@ -112,6 +116,16 @@ func (api *CollectionApi) update(c *routing.Context) error {
 	}
 	form.Model = model
 
+	// report the entities migration changes without persisting
+	if c.Query("dry_run") == "1" || c.Query("dry_run") == "true" {
+		diff, diffErr := api.dao.DryRunUpdate(form)
+		if diffErr != nil {
+			return utils.NewBadRequestError("Oops, an error occurred while updating Collection model.", diffErr)
+		}
+
+		return c.Write(diff)
+	}
+
 	updatedModel, updateErr := api.dao.Update(form)
 
 	if updateErr != nil {
@@ -120,7 +134,7 @@ func (api *CollectionApi) update(c *routing.Context) error {
 
 	go api.sendUpdateHook(updatedModel)
 
-	return c.Write(updatedModel)
+	return c.Write(&collectionWithSecret{updatedModel, updatedModel.HookSecret})
 }
 
 // delete api handler for deleting an existing collection item
@@ -150,19 +164,31 @@ func (api *CollectionApi) delete(c *routing.Context) error {
 // • Hook helpers
 // -------------------------------------------------------------------
 
+// collectionWithSecret wraps a collection model to include its hooks secret in the response
+// (the secret is returned only by the collection create and update handlers).
+type collectionWithSecret struct {
+	*models.Collection
+	HookSecret string `json:"hook_secret"`
+}
+
 // sendCreateHook sends entity create hook.
 func (api *CollectionApi) sendCreateHook(collection *models.Collection) error {
-	return utils.SendHook(collection.CreateHook, utils.HookTypeCollection, utils.HookActionCreate, collection)
+	return api.sendHook(collection, collection.CreateHook, utils.HookActionCreate)
 }
 
 // sendUpdateHook sends entity update hook.
 func (api *CollectionApi) sendUpdateHook(collection *models.Collection) error {
-	return utils.SendHook(collection.UpdateHook, utils.HookTypeCollection, utils.HookActionUpdate, collection)
+	return api.sendHook(collection, collection.UpdateHook, utils.HookActionUpdate)
 }
 
 // sendDeleteHook sends entity delete hook.
 func (api *CollectionApi) sendDeleteHook(collection *models.Collection) error {
-	return utils.SendHook(collection.DeleteHook, utils.HookTypeCollection, utils.HookActionDelete, collection)
+	return api.sendHook(collection, collection.DeleteHook, utils.HookActionDelete)
+}
+
+// sendHook sends collection hook.
+func (api *CollectionApi) sendHook(collection *models.Collection, url string, action string) error {
+	return daos.NewHookDeliveryDAO(api.mongoSession).Send(collection, url, utils.HookTypeCollection, action, collection)
 }
 
 // -------------------------------------------------------------------
@@ -198,5 +224,11 @@ func (api *CollectionApi) unsetCollectionAccessGroup(collection *models.Collecti
 		return usersErr
 	}
 
+	roleDAO := daos.NewRoleDAO(api.mongoSession)
+	rolesErr := roleDAO.UnsetAccessGroup(collection.ID.Hex())
+	if rolesErr != nil {
+		return rolesErr
+	}
+
 	return nil
 }
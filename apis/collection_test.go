@@ -161,7 +161,7 @@ func TestCollectionApi_create(t *testing.T) {
 		&TestApiScenario{
 			Data:            `{"title": "test", "name": "new_col", "fields": [{"key": "key1", "type": "plain", "label": "Title 1", "meta": {}}, {"key": "key2", "type": "relation", "label": "Title 2", "meta": {"collection_id": "5a8b32d4e13823769a18bc1c"}}]}`,
 			ExpectedCode:    200,
-			ExpectedContent: []string{`"id":`, `"title":"test"`, `"name":"new_col"`, `"fields":[{`, `"key":"key1"`, `"key":"key2"`},
+			ExpectedContent: []string{`"id":`, `"title":"test"`, `"name":"new_col"`, `"fields":[{`, `"key":"key1"`, `"key":"key2"`, `"hook_secret":"`},
 		},
 	}
 
@@ -211,7 +211,7 @@ func TestCollectionApi_update(t *testing.T) {
 			Data:            `{"title": "test", "name": "col1", "fields": [{"key": "key1", "type": "plain", "label": "Title 1", "meta": {}}]}`,
 			Params:          map[string]string{"cidentifier": "5a833090e1382351eaad3732"},
 			ExpectedCode:    200,
-			ExpectedContent: []string{`"id":`, `"title":"test"`, `"name":"col1"`, `"fields":[{`, `"key":"key1"`},
+			ExpectedContent: []string{`"id":`, `"title":"test"`, `"name":"col1"`, `"fields":[{`, `"key":"key1"`, `"hook_secret":"`},
 		},
 	}
 
@@ -222,6 +222,38 @@ func TestCollectionApi_update(t *testing.T) {
 	}
 }
 
+func TestCollectionApi_update_dryRun(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Data:            `{"title": "test", "name": "col1", "fields": [{"key": "name", "type": "plain", "label": "Name", "meta": {}}], "renames": {"missing": "name"}}`,
+			Params:          map[string]string{"cidentifier": "col1"},
+			ExpectedCode:    400,
+			ExpectedContent: []string{`"data":{"renames":"Field with key 'missing' doesn't exist."}`},
+		},
+		&TestApiScenario{
+			Data:            `{"title": "test", "name": "col1", "fields": [{"key": "name", "type": "plain", "label": "Name", "meta": {}}], "renames": {"title": "name"}}`,
+			Params:          map[string]string{"cidentifier": "col1"},
+			ExpectedCode:    400,
+			ExpectedContent: []string{`"message":`, `can't be shared across locales`},
+		},
+		&TestApiScenario{
+			Data:            `{"title": "test", "name": "col1", "fields": [{"key": "name", "type": "plain", "label": "Name", "multilingual": true, "meta": {}}], "renames": {"title": "name"}}`,
+			Params:          map[string]string{"cidentifier": "col1"},
+			ExpectedCode:    200,
+			ExpectedContent: []string{`"removed":["short_description","description"]`, `"renamed":{"title":"name"}`, `"recast":[]`, `"affected":2`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockCollectionApi("PUT", "http://localhost:3000?dry_run=1", strings.NewReader(scenario.Data))
+
+		assertTestApiScenario(t, scenario, c, api.update)
+	}
+}
+
 func TestCollectionApi_delete(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -3,6 +3,8 @@ package apis
 import (
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/gofreta/gofreta-api/daos"
 	"github.com/gofreta/gofreta-api/models"
@@ -13,6 +15,9 @@ import (
 	routing "github.com/go-ozzo/ozzo-routing"
 )
 
+// localeRegex matches a valid projection locale value.
+var localeRegex = regexp.MustCompile(`^\w+$`)
+
 // EntityApi defines entity api services
 type EntityApi struct {
 	router        *routing.Router
@@ -32,9 +37,16 @@ func InitEntityApi(rg *routing.Router, session *mgo.Session) {
 
 	rg.Get("/collections/<cidentifier>/entities", authenticateToken(session), api.index)
 	rg.Post("/collections/<cidentifier>/entities", authenticateToken(session), api.create)
+	rg.Post("/collections/<cidentifier>/entities/bulk", authenticateToken(session), api.bulk)
+	rg.Get("/collections/<cidentifier>/entities/search", authenticateToken(session), api.search)
+	rg.Get("/collections/<cidentifier>/entities/export", authenticateToken(session), api.exportRows)
+	rg.Post("/collections/<cidentifier>/entities/import", authenticateToken(session), api.importRows)
+	rg.Get("/collections/<cidentifier>/entities/by-slug/<slug>", authenticateToken(session), api.viewBySlug)
 	rg.Get("/collections/<cidentifier>/entities/<id>", authenticateToken(session), api.view)
 	rg.Put("/collections/<cidentifier>/entities/<id>", authenticateToken(session), api.update)
 	rg.Delete("/collections/<cidentifier>/entities/<id>", authenticateToken(session), api.delete)
+	rg.Post("/collections/<cidentifier>/entities/<id>/publish", authenticateToken(session), api.publish)
+	rg.Get("/collections/<cidentifier>/entities/<id>/referenced-by", authenticateToken(session), api.referencedBy)
 }
 
 // -------------------------------------------------------------------
@@ -60,47 +72,138 @@ func (api *EntityApi) index(c *routing.Context) error {
 	searchFields := []string{"_id", "status", "created", "modified", `data\.\w+\.\w+`}
 	searchData := utils.GetSearchConditions(c, searchFields)
 
+	// allow filtering by nested group field paths too (eg. data.en.faq.question)
+	filterFields := append(searchFields, `data\.\w+\.\w+(\.\w+)+`)
+	if filterErr := utils.AppendFilterConditions(c, searchData, filterFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
+
 	searchData["collection_id"] = collection.ID
 
 	if !isUser(c) {
 		searchData["status"] = models.EntityStatusActive
 	}
+
+	for key, condition := range utils.GetGeoConditions(c, []string{`data\.\w+\.\w+`}) {
+		searchData[key] = condition
+		searchData[key+".type"] = models.GeoPointType // matches the partial 2dsphere index filter
+	}
+
+	// the non-multilingual field values are stored in the shared data group
+	searchData = utils.RenameConditionKeys(searchData, collection.SharedDataPath)
 	// ---
 
 	// --- fetch sort data
 	sortFields := []string{"_id", "status", "created", "modified", `data\.\w+\.\w+`}
-	sortData := utils.GetSortFields(c, sortFields)
+	sortData := []string{}
+	sortKeys := []string{}
+	for _, field := range utils.GetSortFields(c, sortFields) {
+		key := strings.TrimLeft(field, "+-")
+		sharedKey := collection.SharedDataPath(key)
+
+		// different locale paths of the same shared field are sorted only once
+		if !utils.StringInSlice(sharedKey, sortKeys) {
+			sortKeys = append(sortKeys, sharedKey)
+			sortData = append(sortData, field[:len(field)-len(key)]+sharedKey)
+		}
+	}
 	// ---
 
 	items := []models.Entity{}
 
-	total, _ := api.dao.Count(searchData)
+	projection := api.resolveProjection(c, collection)
 
-	limit, page := utils.GetPaginationSettings(c, total)
+	localeChain, localeErr := api.resolveLocaleChain(c)
+	if localeErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while resolving the locale.", localeErr)
+	}
 
-	utils.SetPaginationHeaders(c, limit, total, page)
+	if localeChain != nil {
+		projection.Locales = localeChain
+	}
 
-	if total > 0 {
-		items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+	if utils.IsCursorPagination(c) {
+		sortData = utils.CursorSortFields(sortData)
 
-		// --- enrich
-		enrichSettings := &daos.EntityEnrichSettings{
-			EnrichMedia:      (canAccess(c, "media", "view") == nil),
-			RelCollectionIds: getAccessColectionIds(c, "view"),
+		// the sort field values are required to build the next cursor
+		for _, field := range sortData {
+			projection.Paths = append(projection.Paths, strings.TrimLeft(field, "+-"))
 		}
-		if !isUser(c) {
-			enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+
+		cursorData, cursorErr := utils.GetCursorConditions(c, sortData)
+		if cursorErr != nil {
+			return utils.NewBadRequestError("Oops, an error occurred while parsing the pagination cursor.", cursorErr)
+		}
+
+		total := -1
+		if utils.IsCountRequested(c) {
+			total, _ = api.dao.Count(searchData)
+		}
+
+		if cursorData != nil {
+			utils.AppendAndCondition(searchData, cursorData)
 		}
+
+		limit, _ := utils.GetPaginationSettings(c, 0)
+
+		// fetch one extra item to check whether there is a next page
+		items, _ = api.dao.GetList(limit+1, 0, searchData, sortData, projection)
+
+		nextCursor := ""
+		if len(items) > limit {
+			items = items[:limit]
+			nextCursor, _ = utils.NewCursor(items[limit-1], sortData)
+		}
+
+		utils.SetCursorHeaders(c, limit, total, nextCursor)
+	} else {
+		total, _ := api.dao.Count(searchData)
+
+		limit, page := utils.GetPaginationSettings(c, total)
+
+		utils.SetPaginationHeaders(c, limit, total, page)
+
+		if total > 0 {
+			items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData, projection)
+		}
+	}
+
+	// --- enrich
+	enrichSettings := &daos.EntityEnrichSettings{
+		EnrichMedia:      (canAccess(c, "media", "view") == nil),
+		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
+		Fields:           projection.Fields,
+		Locales:          projection.Locales,
+	}
+	if !isUser(c) {
+		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
+	}
+
+	if localeChain != nil {
+		localizedItems := make([]models.LocalizedEntity, len(items))
+		for i, item := range items {
+			localizedItems[i] = *item.ResolveLocale(localeChain, collection)
+		}
+
+		if len(localizedItems) > 0 {
+			localizedItems = api.dao.EnrichLocalizedEntities(localizedItems, collection, enrichSettings)
+		}
+
+		return c.Write(localizedItems)
+	}
+
+	if len(items) > 0 {
 		items = api.dao.EnrichEntitiesByCollectionName(items, collection.Name, enrichSettings)
-		// ---
 	}
+	// ---
 
 	return c.Write(items)
 }
 
-// view api handler for fetching single entity item data
-func (api *EntityApi) view(c *routing.Context) error {
-	id := c.Param("id")
+// search api handler for full-text searching the collection entity items
+func (api *EntityApi) search(c *routing.Context) error {
 	cidentifier := c.Param("cidentifier")
 
 	// --- ensure collection access
@@ -109,35 +212,79 @@ func (api *EntityApi) view(c *routing.Context) error {
 		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
 	}
 
-	if accessErr := canAccess(c, collection.ID.Hex(), "view"); accessErr != nil {
+	if accessErr := canAccess(c, collection.ID.Hex(), "index"); accessErr != nil {
 		return accessErr
 	}
 	// ---
 
-	additionalConditions := bson.M{"collection_id": collection.ID}
+	term := strings.TrimSpace(c.Query("term"))
+	if term == "" {
+		return utils.NewBadRequestError("Missing required search term.", nil)
+	}
+
+	searchData := bson.M{"collection_id": collection.ID}
 
 	if !isUser(c) {
-		additionalConditions["status"] = models.EntityStatusActive
+		searchData["status"] = models.EntityStatusActive
 	}
 
-	model, err := api.dao.GetByID(id, additionalConditions)
+	return writeSearchResults(c, api.dao, api.collectionDAO, term, searchData)
+}
 
-	if err != nil {
+// view api handler for fetching single entity item data
+func (api *EntityApi) view(c *routing.Context) error {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "view"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	if !bson.IsObjectIdHex(id) {
 		return utils.NewNotFoundError(fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
 	}
 
-	// --- enrich
-	enrichSettings := &daos.EntityEnrichSettings{
-		EnrichMedia:      (canAccess(c, "media", "view") == nil),
-		RelCollectionIds: getAccessColectionIds(c, "view"),
+	conditions := bson.M{"_id": bson.ObjectIdHex(id), "collection_id": collection.ID}
+
+	return api.writeOne(c, collection, conditions, fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
+}
+
+// viewBySlug api handler for fetching entity item details by the value of any of its collection slug fields
+func (api *EntityApi) viewBySlug(c *routing.Context) error {
+	slug := c.Param("slug")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
 	}
-	if !isUser(c) {
-		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "view"); accessErr != nil {
+		return accessErr
 	}
-	api.dao.EnrichEntity(model, collection, enrichSettings)
 	// ---
 
-	return c.Write(model)
+	notFoundMessage := fmt.Sprintf("Entity item with slug \"%v\" and collection identifier \"%v\" doesn't exist!", slug, cidentifier)
+
+	// match the slug in any locale of any slug field
+	conditions, conditionsErr := api.dao.SlugConditions(collection, slug)
+	if conditionsErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while loading the languages.", conditionsErr)
+	}
+
+	if conditions == nil {
+		return utils.NewNotFoundError(notFoundMessage)
+	}
+
+	return api.writeOne(c, collection, conditions, notFoundMessage)
 }
 
 // create api handler for creating a new entity item
@@ -162,6 +309,7 @@ func (api *EntityApi) create(c *routing.Context) error {
 	}
 
 	form.CollectionID = collection.ID
+	form.Author = getRevisionAuthor(c)
 
 	model, createErr := api.dao.Create(form)
 	if createErr != nil {
@@ -172,11 +320,13 @@ func (api *EntityApi) create(c *routing.Context) error {
 	enrichSettings := &daos.EntityEnrichSettings{
 		EnrichMedia:      (canAccess(c, "media", "view") == nil),
 		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
 	}
 	if !isUser(c) {
 		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
 	}
-	api.dao.EnrichEntity(model, collection, enrichSettings)
+	model = api.dao.EnrichEntity(model, collection, enrichSettings)
 	// ---
 
 	go api.sendCreateHook(model)
@@ -184,6 +334,58 @@ func (api *EntityApi) create(c *routing.Context) error {
 	return c.Write(model)
 }
 
+// bulk api handler for executing a batch of entity create, update, delete and status change operations
+func (api *EntityApi) bulk(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	form := &models.EntityBulkForm{}
+
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while reading the bulk request data.", readErr)
+	}
+
+	if validateErr := form.Validate(); validateErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while validating the bulk request data.", validateErr)
+	}
+
+	result := models.NewEntityBulkResult(form)
+
+	// --- ensure collection access (per operation)
+	for i, op := range form.Operations {
+		if accessErr := canAccess(c, collection.ID.Hex(), op.AccessAction()); accessErr != nil {
+			result.SetError(i, accessErr)
+		}
+
+		// api keys don't work with drafts
+		if !isUser(c) {
+			form.Operations[i].Publish = true
+		}
+	}
+	// ---
+
+	additionalConditions := bson.M{}
+
+	if !isUser(c) {
+		additionalConditions["status"] = models.EntityStatusActive
+	}
+
+	api.dao.Bulk(collection, form, result, getRevisionAuthor(c), additionalConditions)
+
+	go api.sendBulkHooks(collection, result)
+
+	// the batch could still be partially executed if an operation fails during its execution
+	if form.Atomic && result.HasErrors() && !result.HasDone() {
+		return utils.NewBadRequestError("Oops, the bulk operations were not executed because some of them are invalid.", utils.NewDataError(result))
+	}
+
+	return c.Write(result)
+}
+
 // update api handler for updating an existing entity item
 func (api *EntityApi) update(c *routing.Context) error {
 	id := c.Param("id")
@@ -218,6 +420,12 @@ func (api *EntityApi) update(c *routing.Context) error {
 	}
 
 	form.CollectionID = collection.ID
+	form.Author = getRevisionAuthor(c)
+
+	// api keys don't work with drafts
+	if !isUser(c) {
+		form.Publish = true
+	}
 
 	updatedModel, updateErr := api.dao.Update(form)
 	if updateErr != nil {
@@ -228,11 +436,13 @@ func (api *EntityApi) update(c *routing.Context) error {
 	enrichSettings := &daos.EntityEnrichSettings{
 		EnrichMedia:      (canAccess(c, "media", "view") == nil),
 		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
 	}
 	if !isUser(c) {
 		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
 	}
-	api.dao.EnrichEntity(updatedModel, collection, enrichSettings)
+	updatedModel = api.dao.EnrichEntity(updatedModel, collection, enrichSettings)
 	// ---
 
 	go api.sendUpdateHook(updatedModel)
@@ -267,7 +477,7 @@ func (api *EntityApi) delete(c *routing.Context) error {
 		return utils.NewNotFoundError(fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
 	}
 
-	deleteErr := api.dao.Delete(model)
+	deleteErr := api.dao.Delete(model, getRevisionAuthor(c))
 	if deleteErr != nil {
 		return utils.NewBadRequestError("Oops, an error occurred while deleting Entity item.", deleteErr)
 	}
@@ -276,9 +486,11 @@ func (api *EntityApi) delete(c *routing.Context) error {
 	enrichSettings := &daos.EntityEnrichSettings{
 		EnrichMedia:      (canAccess(c, "media", "view") == nil),
 		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
 	}
 	if !isUser(c) {
 		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
 	}
 	api.dao.EnrichEntity(model, collection, enrichSettings)
 	// ---
@@ -290,6 +502,208 @@ func (api *EntityApi) delete(c *routing.Context) error {
 	return nil
 }
 
+// publish api handler for promoting an entity item draft into its live data
+func (api *EntityApi) publish(c *routing.Context) error {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "update"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	model, fetchErr := api.dao.GetByID(id, bson.M{"collection_id": collection.ID})
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
+	}
+
+	publishedModel, publishErr := api.dao.Publish(model, getRevisionAuthor(c))
+	if publishErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while publishing Entity item.", publishErr)
+	}
+
+	// --- enrich
+	enrichSettings := &daos.EntityEnrichSettings{
+		EnrichMedia:      (canAccess(c, "media", "view") == nil),
+		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
+	}
+	if !isUser(c) {
+		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
+	}
+	publishedModel = api.dao.EnrichEntity(publishedModel, collection, enrichSettings)
+	// ---
+
+	go api.sendUpdateHook(publishedModel)
+
+	return c.Write(publishedModel)
+}
+
+// referencedBy api handler for fetching the entity items that reference an entity item
+func (api *EntityApi) referencedBy(c *routing.Context) error {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "view"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	additionalConditions := bson.M{"collection_id": collection.ID}
+
+	if !isUser(c) {
+		additionalConditions["status"] = models.EntityStatusActive
+	}
+
+	model, fetchErr := api.dao.GetByID(id, additionalConditions)
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
+	}
+
+	items, referencesErr := api.dao.GetEntityReferences(model, isUser(c), referenceAccessConditions(c))
+	if referencesErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while fetching the Entity item references.", referencesErr)
+	}
+
+	return c.Write(presentReferences(c, items))
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// writeOne fetches, enriches and writes the response of a single
+// collection entity item matching the provided conditions.
+func (api *EntityApi) writeOne(c *routing.Context, collection *models.Collection, conditions bson.M, notFoundMessage string) error {
+	projection := api.resolveProjection(c, collection)
+
+	localeChain, localeErr := api.resolveLocaleChain(c)
+	if localeErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while resolving the locale.", localeErr)
+	}
+
+	if localeChain != nil {
+		projection.Locales = localeChain
+	}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	model, err := api.dao.GetOne(conditions, projection)
+	if model == nil || err != nil {
+		return utils.NewNotFoundError(notFoundMessage)
+	}
+
+	// --- enrich
+	enrichSettings := &daos.EntityEnrichSettings{
+		EnrichMedia:      (canAccess(c, "media", "view") == nil),
+		RelCollectionIds: getAccessColectionIds(c, "view"),
+		Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
+		Fields:           projection.Fields,
+		Locales:          projection.Locales,
+	}
+	if !isUser(c) {
+		enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+		enrichSettings.PublishedOnly = true
+	}
+
+	if localeChain != nil {
+		localized := api.dao.EnrichLocalizedEntities(
+			[]models.LocalizedEntity{*model.ResolveLocale(localeChain, collection)},
+			collection,
+			enrichSettings,
+		)
+
+		return c.Write(localized[0])
+	}
+
+	model = api.dao.EnrichEntity(model, collection, enrichSettings)
+	// ---
+
+	return c.Write(model)
+}
+
+// referenceAccessConditions returns the conditions that limit the referencing
+// entity items only to the ones that the request identity could view.
+func referenceAccessConditions(c *routing.Context) bson.M {
+	// the slice is always initialized to prevent serializing nil `$in` value
+	collectionIds := append([]bson.ObjectId{}, getAccessColectionIds(c, "view")...)
+
+	conditions := bson.M{"collection_id": bson.M{"$in": collectionIds}}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	return conditions
+}
+
+// presentReferences prepares the referencing entity items for the response
+// (the drafts are visible only for users and the shared field values are presented under each locale).
+func presentReferences(c *routing.Context, items []models.Entity) []models.Entity {
+	for i, _ := range items {
+		if !isUser(c) {
+			items[i].Draft = nil
+		}
+
+		items[i].ExpandSharedData()
+	}
+
+	return items
+}
+
+// resolveProjection builds the entity read projection from the request
+// `fields` and `locale` comma separated query parameters (invalid values are ignored).
+func (api *EntityApi) resolveProjection(c *routing.Context, collection *models.Collection) daos.EntityProjection {
+	projection := daos.EntityProjection{
+		Fields:  []string{},
+		Locales: []string{},
+	}
+
+	for _, key := range utils.GetListParam(c, "fields") {
+		for _, field := range collection.Fields {
+			if field.Key == key {
+				projection.Fields = append(projection.Fields, key)
+
+				break
+			}
+		}
+	}
+
+	for _, locale := range utils.GetListParam(c, "locale") {
+		if localeRegex.MatchString(locale) {
+			projection.Locales = append(projection.Locales, locale)
+		}
+	}
+
+	return projection
+}
+
+// resolveLocaleChain returns the fallback chain of the requested `resolve_locale` language.
+// Returns nil if the locale resolve read mode is not requested.
+func (api *EntityApi) resolveLocaleChain(c *routing.Context) ([]string, error) {
+	locale := strings.TrimSpace(c.Query("resolve_locale"))
+	if locale == "" {
+		return nil, nil
+	}
+
+	return daos.NewLanguageDAO(api.mongoSession).GetFallbackChain(locale)
+}
+
 // -------------------------------------------------------------------
 // • Hook helpers
 // -------------------------------------------------------------------
@@ -301,7 +715,7 @@ func (api *EntityApi) sendCreateHook(entity *models.Entity) error {
 		return err
 	}
 
-	return utils.SendHook(collection.CreateHook, utils.HookTypeEntity, utils.HookActionCreate, entity)
+	return daos.NewHookDeliveryDAO(api.mongoSession).Send(collection, collection.CreateHook, utils.HookTypeEntity, utils.HookActionCreate, entity)
 }
 
 // sendUpdateHook sends entity update hook.
@@ -311,7 +725,7 @@ func (api *EntityApi) sendUpdateHook(entity *models.Entity) error {
 		return err
 	}
 
-	return utils.SendHook(collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, entity)
+	return daos.NewHookDeliveryDAO(api.mongoSession).Send(collection, collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, entity)
 }
 
 // sendDeleteHook sends entity delete hook.
@@ -321,5 +735,36 @@ func (api *EntityApi) sendDeleteHook(entity *models.Entity) error {
 		return err
 	}
 
-	return utils.SendHook(collection.DeleteHook, utils.HookTypeEntity, utils.HookActionDelete, entity)
+	return daos.NewHookDeliveryDAO(api.mongoSession).Send(collection, collection.DeleteHook, utils.HookTypeEntity, utils.HookActionDelete, entity)
+}
+
+// sendBulkHooks sends a single entity bulk hook with the list
+// of the created, updated and deleted entities (if any).
+func (api *EntityApi) sendBulkHooks(collection *models.Collection, result *models.EntityBulkResult) {
+	hookDAO := daos.NewHookDeliveryDAO(api.mongoSession)
+
+	hooks := []struct {
+		Url      string
+		Action   string
+		Entities []models.Entity
+	}{
+		{collection.CreateHook, utils.HookActionCreate, result.Models(models.EntityBulkActionCreate)},
+		{collection.UpdateHook, utils.HookActionUpdate, append(
+			result.Models(models.EntityBulkActionUpdate),
+			result.Models(models.EntityBulkActionStatus)...,
+		)},
+		{collection.DeleteHook, utils.HookActionDelete, result.Models(models.EntityBulkActionDelete)},
+	}
+
+	for _, hook := range hooks {
+		if len(hook.Entities) == 0 {
+			continue
+		}
+
+		for i := range hook.Entities {
+			hook.Entities[i].ExpandSharedData()
+		}
+
+		hookDAO.Send(collection, hook.Url, utils.HookTypeEntityBulk, hook.Action, hook.Entities)
+	}
 }
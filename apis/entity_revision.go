@@ -0,0 +1,207 @@
+package apis
+
+import (
+	"fmt"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// EntityRevisionApi defines entity revision api services
+type EntityRevisionApi struct {
+	router        *routing.Router
+	mongoSession  *mgo.Session
+	dao           *daos.EntityRevisionDAO
+	entityDAO     *daos.EntityDAO
+	collectionDAO *daos.CollectionDAO
+}
+
+// InitEntityRevisionApi sets up the routing of entity revision endpoints and the corresponding handlers.
+func InitEntityRevisionApi(rg *routing.Router, session *mgo.Session) {
+	api := EntityRevisionApi{
+		router:        rg,
+		mongoSession:  session,
+		dao:           daos.NewEntityRevisionDAO(session),
+		entityDAO:     daos.NewEntityDAO(session),
+		collectionDAO: daos.NewCollectionDAO(session),
+	}
+
+	rg.Get("/collections/<cidentifier>/entities/<id>/revisions", authenticateToken(session), usersOnly, api.index)
+	rg.Get("/collections/<cidentifier>/entities/<id>/revisions/<rev>", authenticateToken(session), usersOnly, api.view)
+	rg.Get("/collections/<cidentifier>/entities/<id>/revisions/<rev>/diff/<compareRev>", authenticateToken(session), usersOnly, api.diff)
+	rg.Post("/collections/<cidentifier>/entities/<id>/revisions/<rev>/restore", authenticateToken(session), usersOnly, api.restore)
+}
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// index api handler for fetching paginated entity revisions list
+func (api *EntityRevisionApi) index(c *routing.Context) error {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "view"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	if !bson.IsObjectIdHex(id) {
+		return utils.NewNotFoundError(fmt.Sprintf("Entity item with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
+	}
+
+	// --- fetch search data
+	searchFields := []string{"_id", "action", "status", "author.id", "author.model", "created"}
+	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
+
+	searchData["collection_id"] = collection.ID
+	searchData["entity_id"] = bson.ObjectIdHex(id)
+	// ---
+
+	// --- fetch sort data
+	sortFields := []string{"_id", "action", "status", "created"}
+	sortData := utils.GetSortFields(c, sortFields)
+	if len(sortData) == 0 {
+		sortData = []string{"-created", "-_id"}
+	}
+	// ---
+
+	items := []models.EntityRevision{}
+
+	total, _ := api.dao.Count(searchData)
+
+	limit, page := utils.GetPaginationSettings(c, total)
+
+	utils.SetPaginationHeaders(c, limit, total, page)
+
+	if total > 0 {
+		items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+	}
+
+	// present the shared (non-multilingual) field values under each locale
+	for _, item := range items {
+		models.ExpandSharedData(item.Data)
+	}
+
+	return c.Write(items)
+}
+
+// view api handler for fetching single entity revision data
+func (api *EntityRevisionApi) view(c *routing.Context) error {
+	revision, err := api.findRevision(c, c.Param("rev"), "view")
+	if err != nil {
+		return err
+	}
+
+	return c.Write(revision)
+}
+
+// diff api handler for fetching the per locale field changes between two entity revisions
+func (api *EntityRevisionApi) diff(c *routing.Context) error {
+	revision, err := api.findRevision(c, c.Param("rev"), "view")
+	if err != nil {
+		return err
+	}
+
+	compareRevision, compareErr := api.findRevision(c, c.Param("compareRev"), "view")
+	if compareErr != nil {
+		return compareErr
+	}
+
+	return c.Write(map[string]interface{}{
+		"from": revision.ID,
+		"to":   compareRevision.ID,
+		"diff": revision.Diff(compareRevision),
+	})
+}
+
+// restore api handler for restoring an entity item to the state of a specific revision
+// (restoring a deleted entity recreates it and requires collection create access)
+func (api *EntityRevisionApi) restore(c *routing.Context) error {
+	_, fetchErr := api.entityDAO.GetByID(c.Param("id"))
+	isDeleted := fetchErr != nil
+
+	action := "update"
+	if isDeleted {
+		action = "create"
+	}
+
+	revision, err := api.findRevision(c, c.Param("rev"), action)
+	if err != nil {
+		return err
+	}
+
+	model, restoreErr := api.entityDAO.Restore(revision, getRevisionAuthor(c))
+	if restoreErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while restoring Entity item.", restoreErr)
+	}
+
+	collection, _ := api.entityDAO.GetEntityCollection(model)
+
+	model.ExpandSharedData()
+
+	hookDeliveryDAO := daos.NewHookDeliveryDAO(api.mongoSession)
+	if isDeleted {
+		go hookDeliveryDAO.Send(collection, collection.CreateHook, utils.HookTypeEntity, utils.HookActionCreate, model)
+	} else {
+		go hookDeliveryDAO.Send(collection, collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, model)
+	}
+
+	return c.Write(model)
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// findRevision fetches a single entity revision based on the request route params
+// and checks whether the authenticated identity is allowed to perform `action` on its collection.
+func (api *EntityRevisionApi) findRevision(c *routing.Context, rev string, action string) (*models.EntityRevision, error) {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), action); accessErr != nil {
+		return nil, accessErr
+	}
+	// ---
+
+	notFoundErr := utils.NewNotFoundError(fmt.Sprintf("Revision with id \"%v\" for entity item \"%v\" doesn't exist!", rev, id))
+
+	if !bson.IsObjectIdHex(id) {
+		return nil, notFoundErr
+	}
+
+	revision, err := api.dao.GetByID(rev, bson.M{
+		"collection_id": collection.ID,
+		"entity_id":     bson.ObjectIdHex(id),
+	})
+	if err != nil {
+		return nil, notFoundErr
+	}
+
+	// present the shared (non-multilingual) field values under each locale
+	models.ExpandSharedData(revision.Data)
+
+	return revision, nil
+}
@@ -0,0 +1,295 @@
+package apis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// exportBatchSize specifies the number of entities that are loaded and written at once during an export.
+const exportBatchSize = 100
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// exportRows api handler for streaming the collection entities as flat CSV or NDJSON rows
+// (one column per multilingual `locale.field` and per non-multilingual `field` value).
+func (api *EntityApi) exportRows(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "index"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	format := c.Query("format", models.EntityRowFormatCsv)
+	if format != models.EntityRowFormatCsv && format != models.EntityRowFormatNdjson {
+		return utils.NewBadRequestError("Unsupported export format '"+format+"'.", nil)
+	}
+
+	projection := api.resolveProjection(c, collection)
+
+	columns, columnsErr := api.resolveRowColumns(collection, projection)
+	if columnsErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while loading the languages.", columnsErr)
+	}
+
+	conditions := bson.M{"collection_id": collection.ID}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	// --- stream
+	writeRow := func(entity *models.Entity) error {
+		return json.NewEncoder(c.Response).Encode(models.NewEntityRow(entity, columns))
+	}
+
+	flush := func() error {
+		flushRows(c)
+
+		return nil
+	}
+
+	if format == models.EntityRowFormatNdjson {
+		c.Response.Header().Set("Content-Type", "application/x-ndjson; charset=UTF-8")
+	} else {
+		csvWriter := csv.NewWriter(c.Response)
+
+		writeRow = func(entity *models.Entity) error {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				record[i] = column.Cell(column.Value(entity))
+			}
+
+			return csvWriter.Write(record)
+		}
+
+		flush = func() error {
+			csvWriter.Flush()
+			flushRows(c)
+
+			return csvWriter.Error()
+		}
+
+		c.Response.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+		c.Response.Header().Set("Content-Disposition", "attachment; filename=\""+collection.Name+".csv\"")
+
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.Name
+		}
+
+		if err := csvWriter.Write(header); err != nil {
+			return err
+		}
+	}
+
+	// the entities are loaded in batches to keep the memory usage constant
+	var lastID bson.ObjectId
+	for {
+		batchConditions := bson.M{}
+		for k, v := range conditions {
+			batchConditions[k] = v
+		}
+
+		if lastID != "" {
+			batchConditions["_id"] = bson.M{"$gt": lastID}
+		}
+
+		items, err := api.dao.GetList(exportBatchSize, 0, batchConditions, []string{"_id"}, projection)
+		if err != nil {
+			return err
+		}
+
+		for i := range items {
+			if err := writeRow(&items[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+
+		if len(items) < exportBatchSize {
+			break
+		}
+
+		lastID = items[len(items)-1].ID
+	}
+	// ---
+
+	return nil
+}
+
+// importRows api handler for creating and updating collection entities from CSV or NDJSON rows
+// (the rows with `_id` column value are updated, the others are created).
+func (api *EntityApi) importRows(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "create"); accessErr != nil {
+		return accessErr
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "update"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	format := c.Query("format", models.EntityRowFormatCsv)
+
+	maxRows := app.Config.GetInt("import.maxRows")
+	maxRowsErr := utils.NewBadRequestError(fmt.Sprintf("The import file must not have more than %d rows.", maxRows), nil)
+
+	rows := []models.EntityRow{}
+
+	switch format {
+	case models.EntityRowFormatCsv:
+		reader := csv.NewReader(c.Request.Body)
+
+		// the first record is the columns header
+		header, readErr := reader.Read()
+		if readErr != nil && readErr != io.EOF {
+			return utils.NewBadRequestError("Oops, an error occurred while reading the CSV file.", readErr)
+		}
+
+		for readErr == nil {
+			record, recordErr := reader.Read()
+			if recordErr == io.EOF {
+				break
+			}
+
+			if recordErr != nil {
+				return utils.NewBadRequestError("Oops, an error occurred while reading the CSV file.", recordErr)
+			}
+
+			if len(rows) >= maxRows {
+				return maxRowsErr
+			}
+
+			row := models.EntityRow{}
+			for j, name := range header {
+				if j < len(record) {
+					row[name] = record[j]
+				}
+			}
+
+			rows = append(rows, row)
+		}
+	case models.EntityRowFormatNdjson:
+		decoder := json.NewDecoder(c.Request.Body)
+		for {
+			row := models.EntityRow{}
+
+			readErr := decoder.Decode(&row)
+			if readErr == io.EOF {
+				break
+			}
+
+			if readErr != nil {
+				return utils.NewBadRequestError("Oops, an error occurred while reading the NDJSON file.", readErr)
+			}
+
+			if len(rows) >= maxRows {
+				return maxRowsErr
+			}
+
+			rows = append(rows, row)
+		}
+	default:
+		return utils.NewBadRequestError("Unsupported import format '"+format+"'.", nil)
+	}
+
+	conditions := bson.M{}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	// api keys don't work with drafts
+	publish := !isUser(c) || c.Query("publish") == "1" || c.Query("publish") == "true"
+
+	result := api.dao.ImportRows(collection, rows, publish, getRevisionAuthor(c), conditions)
+
+	go api.sendImportHooks(collection, result)
+
+	return c.Write(result)
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// resolveRowColumns returns the collection entity row columns limited to the projection fields and locales.
+func (api *EntityApi) resolveRowColumns(collection *models.Collection, projection daos.EntityProjection) ([]models.EntityRowColumn, error) {
+	languages, err := daos.NewLanguageDAO(api.mongoSession).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	locales := []string{}
+	for _, lang := range languages {
+		if len(projection.Locales) == 0 || utils.StringInSlice(lang.Locale, projection.Locales) {
+			locales = append(locales, lang.Locale)
+		}
+	}
+
+	result := []models.EntityRowColumn{}
+	for _, column := range models.NewEntityRowColumns(collection, locales) {
+		if column.Field != nil && len(projection.Fields) > 0 && !utils.StringInSlice(column.Field.Key, projection.Fields) {
+			continue
+		}
+
+		result = append(result, column)
+	}
+
+	return result, nil
+}
+
+// sendImportHooks sends entity create and update hook for each of the imported entities.
+func (api *EntityApi) sendImportHooks(collection *models.Collection, result *models.EntityImport) {
+	hookDAO := daos.NewHookDeliveryDAO(api.mongoSession)
+
+	for i := range result.CreatedEntities {
+		result.CreatedEntities[i].ExpandSharedData()
+
+		hookDAO.Send(collection, collection.CreateHook, utils.HookTypeEntity, utils.HookActionCreate, &result.CreatedEntities[i])
+	}
+
+	for i := range result.UpdatedEntities {
+		result.UpdatedEntities[i].ExpandSharedData()
+
+		hookDAO.Send(collection, collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, &result.UpdatedEntities[i])
+	}
+}
+
+// flushRows sends the already written response rows to the client (if supported).
+func flushRows(c *routing.Context) {
+	if flusher, ok := c.Response.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,128 @@
+package apis
+
+import (
+	"fmt"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// HookDeliveryApi defines hook delivery api services
+type HookDeliveryApi struct {
+	router        *routing.Router
+	mongoSession  *mgo.Session
+	dao           *daos.HookDeliveryDAO
+	collectionDAO *daos.CollectionDAO
+}
+
+// InitHookDeliveryApi sets up the routing of hook delivery endpoints and the corresponding handlers.
+func InitHookDeliveryApi(rg *routing.Router, session *mgo.Session) {
+	api := HookDeliveryApi{
+		router:        rg,
+		mongoSession:  session,
+		dao:           daos.NewHookDeliveryDAO(session),
+		collectionDAO: daos.NewCollectionDAO(session),
+	}
+
+	rg.Get("/collections/<cidentifier>/hook-deliveries", authenticateToken(session, "collection", "view"), usersOnly, api.index)
+	rg.Get("/collections/<cidentifier>/hook-deliveries/<id>", authenticateToken(session, "collection", "view"), usersOnly, api.view)
+	rg.Post("/collections/<cidentifier>/hook-deliveries/<id>/redeliver", authenticateToken(session, "collection", "update"), usersOnly, api.redeliver)
+}
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// index api handler for fetching paginated collection hook deliveries list
+func (api *HookDeliveryApi) index(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	// --- fetch search data
+	searchFields := []string{"_id", "url", "type", "action", "status", "next_attempt", "created", "modified"}
+	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
+
+	searchData["collection_id"] = collection.ID
+	// ---
+
+	// --- fetch sort data
+	sortFields := []string{"_id", "url", "type", "action", "status", "next_attempt", "created", "modified"}
+	sortData := utils.GetSortFields(c, sortFields)
+	if len(sortData) == 0 {
+		sortData = []string{"-created", "-_id"}
+	}
+	// ---
+
+	items := []models.HookDelivery{}
+
+	total, _ := api.dao.Count(searchData)
+
+	limit, page := utils.GetPaginationSettings(c, total)
+
+	utils.SetPaginationHeaders(c, limit, total, page)
+
+	if total > 0 {
+		items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+	}
+
+	return c.Write(items)
+}
+
+// view api handler for fetching single hook delivery data
+func (api *HookDeliveryApi) view(c *routing.Context) error {
+	model, err := api.findDelivery(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Write(model)
+}
+
+// redeliver api handler for manually resending a hook delivery
+func (api *HookDeliveryApi) redeliver(c *routing.Context) error {
+	model, err := api.findDelivery(c)
+	if err != nil {
+		return err
+	}
+
+	if deliverErr := api.dao.Deliver(model); deliverErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while redelivering the hook.", deliverErr)
+	}
+
+	return c.Write(model)
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// findDelivery fetches a single collection hook delivery based on the request route params.
+func (api *HookDeliveryApi) findDelivery(c *routing.Context) (*models.HookDelivery, error) {
+	id := c.Param("id")
+	cidentifier := c.Param("cidentifier")
+
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	model, err := api.dao.GetByID(id, bson.M{"collection_id": collection.ID})
+	if err != nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("Hook delivery with id \"%v\" and collection identifier \"%v\" doesn't exist!", id, cidentifier))
+	}
+
+	return model, nil
+}
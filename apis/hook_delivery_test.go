@@ -0,0 +1,167 @@
+package apis
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/fixtures"
+
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+	"github.com/go-ozzo/ozzo-routing/content"
+)
+
+func TestInitHookDeliveryApi(t *testing.T) {
+	router := routing.New()
+
+	InitHookDeliveryApi(router, TestSession)
+
+	expectedRoutes := []string{
+		"GET /collections/<cidentifier>/hook-deliveries",
+		"GET /collections/<cidentifier>/hook-deliveries/<id>",
+		"POST /collections/<cidentifier>/hook-deliveries/<id>/redeliver",
+	}
+
+	routes := router.Routes()
+
+	assertInitApiRoutes(t, routes, expectedRoutes)
+}
+
+func TestHookDeliveryApi_index(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []struct {
+		Url      string
+		Scenario *TestApiScenario
+	}{
+		{
+			"http://localhost:3000",
+			&TestApiScenario{
+				Params:          map[string]string{"cidentifier": "missing"},
+				ExpectedCode:    404,
+				ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+			},
+		},
+		{
+			"http://localhost:3000",
+			&TestApiScenario{
+				Params:          map[string]string{"cidentifier": "col1"},
+				ExpectedCode:    200,
+				ExpectedContent: []string{`[{"id":"5b2a1c3fe138231f4c5d9e02"`, `"id":"5b2a1c3fe138231f4c5d9e01"`, `"code":500`},
+				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "2", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
+			},
+		},
+		{
+			"http://localhost:3000/?q[status]=failed",
+			&TestApiScenario{
+				Params:          map[string]string{"cidentifier": "5a833090e1382351eaad3732"},
+				ExpectedCode:    200,
+				ExpectedContent: []string{`"id":"5b2a1c3fe138231f4c5d9e01"`},
+				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "1"},
+			},
+		},
+	}
+
+	for _, item := range testScenarios {
+		api, c := mockHookDeliveryApi("GET", item.Url, nil)
+
+		assertTestApiScenario(t, item.Scenario, c, api.index)
+	}
+}
+
+func TestHookDeliveryApi_view(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Params:          map[string]string{"cidentifier": "missing", "id": "5b2a1c3fe138231f4c5d9e01"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		// delivery from another collection
+		&TestApiScenario{
+			Params:          map[string]string{"cidentifier": "col1", "id": "5b2a1c3fe138231f4c5d9e03"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"cidentifier": "col1", "id": "5b2a1c3fe138231f4c5d9e01"},
+			ExpectedCode:    200,
+			ExpectedContent: []string{`"id":"5b2a1c3fe138231f4c5d9e01"`, `"status":"failed"`, `"attempts":[{`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockHookDeliveryApi("GET", "http://localhost:3000", nil)
+
+		assertTestApiScenario(t, scenario, c, api.view)
+	}
+}
+
+func TestHookDeliveryApi_redeliver(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	hooksCount := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hooksCount++
+	}))
+	defer ts.Close()
+
+	// change the delivery url with the test server address
+	TestSession.DB("").C("hook_delivery").UpdateId(
+		bson.ObjectIdHex("5b2a1c3fe138231f4c5d9e01"),
+		bson.M{"$set": bson.M{"url": ts.URL}},
+	)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Params:          map[string]string{"cidentifier": "col1", "id": "missing"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"cidentifier": "col1", "id": "5b2a1c3fe138231f4c5d9e01"},
+			ExpectedCode:    200,
+			ExpectedContent: []string{`"id":"5b2a1c3fe138231f4c5d9e01"`, `"status":"success"`, `"code":200`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockHookDeliveryApi("POST", "http://localhost:3000", nil)
+
+		assertTestApiScenario(t, scenario, c, api.redeliver)
+	}
+
+	if hooksCount != 1 {
+		t.Errorf("Expected 1 hook to be sent, got %d", hooksCount)
+	}
+}
+
+// -------------------------------------------------------------------
+// • Hepers
+// -------------------------------------------------------------------
+
+func mockHookDeliveryApi(method, url string, body io.Reader) (*HookDeliveryApi, *routing.Context) {
+	req := httptest.NewRequest(method, url, body)
+
+	w := httptest.NewRecorder()
+
+	c := routing.NewContext(w, req)
+	c.SetDataWriter(&content.JSONDataWriter{})
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	api := HookDeliveryApi{
+		mongoSession:  TestSession,
+		dao:           daos.NewHookDeliveryDAO(TestSession),
+		collectionDAO: daos.NewCollectionDAO(TestSession),
+	}
+
+	return &api, c
+}
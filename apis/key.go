@@ -2,8 +2,10 @@ package apis
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/gofreta/gofreta-api/app"
 	"github.com/gofreta/gofreta-api/daos"
 	"github.com/gofreta/gofreta-api/models"
 	"github.com/gofreta/gofreta-api/utils"
@@ -32,17 +34,22 @@ func InitKeyApi(rg *routing.Router, session *mgo.Session) {
 	rg.Get("/keys/<id>", authenticateToken(session, "key", "view"), usersOnly, api.view)
 	rg.Put("/keys/<id>", authenticateToken(session, "key", "update"), usersOnly, api.update)
 	rg.Delete("/keys/<id>", authenticateToken(session, "key", "delete"), usersOnly, api.delete)
+	rg.Post("/keys/<id>/rotate", authenticateToken(session, "key", "update"), usersOnly, api.rotate)
 }
 
 // index api handler for fetching paginated Key model list.
 func (api *KeyApi) index(c *routing.Context) error {
 	// --- fetch search data
-	searchFields := []string{"title", "token", "created", "modified"}
+	searchFields := []string{"title", "token", "expires", "last_used", "created", "modified"}
 	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
 	// ---
 
 	// --- fetch sort data
-	sortFields := []string{"title", "created", "modified"}
+	sortFields := []string{"title", "expires", "last_used", "created", "modified"}
 	sortData := utils.GetSortFields(c, sortFields)
 	// ---
 
@@ -99,7 +106,7 @@ func (api *KeyApi) update(c *routing.Context) error {
 		return utils.NewNotFoundError(fmt.Sprintf("Key model with id \"%v\" doesn't exist!", id))
 	}
 
-	form := &models.KeyForm{Model: model}
+	form := &models.KeyForm{Model: model, Roles: model.Roles, Expires: model.Expires}
 
 	if readErr := c.Read(form); readErr != nil {
 		return utils.NewBadRequestError("Oops, an error occurred while updating Key model.", readErr)
@@ -131,3 +138,39 @@ func (api *KeyApi) delete(c *routing.Context) error {
 
 	return nil
 }
+
+// rotate api handler for issuing a new token for an existing Key model
+// (the old token remains valid for the configured or the provided `grace` period).
+func (api *KeyApi) rotate(c *routing.Context) error {
+	id := c.Param("id")
+
+	model, fetchErr := api.dao.GetByID(id)
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Key model with id \"%v\" doesn't exist!", id))
+	}
+
+	data := &struct {
+		Grace *int64 `json:"grace" form:"grace"`
+	}{}
+	// the request body is optional
+	if readErr := c.Read(&data); readErr != nil && readErr != io.EOF {
+		return utils.NewBadRequestError("Oops, an error occurred while rotating Key model token.", readErr)
+	}
+
+	grace := app.Config.GetInt64("keys.rotationGracePeriod")
+	if data.Grace != nil {
+		grace = *data.Grace
+	}
+
+	maxGrace := app.Config.GetInt64("keys.maxRotationGracePeriod")
+	if grace < 0 || grace > maxGrace {
+		return utils.NewBadRequestError(fmt.Sprintf("The grace period must be between 0 and %d hours.", maxGrace), nil)
+	}
+
+	rotatedModel, rotateErr := api.dao.Rotate(model, grace*60*60)
+	if rotateErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while rotating Key model token.", rotateErr)
+	}
+
+	return c.Write(rotatedModel)
+}
@@ -39,6 +39,10 @@ func (api *LanguageApi) index(c *routing.Context) error {
 	// --- fetch search data
 	searchFields := []string{"locale", "title", "created", "modified"}
 	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
 	// ---
 
 	// --- fetch sort data
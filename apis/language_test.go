@@ -51,7 +51,7 @@ func TestLanguageApi_index(t *testing.T) {
 			"http://localhost:3000/?q[title]=English",
 			&TestApiScenario{
 				ExpectedCode:    200,
-				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","created":1518946878,"modified":1518946878}]`},
+				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","fallback":[],"created":1518946878,"modified":1518946878}]`},
 				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "1", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
 			},
 		},
@@ -67,7 +67,7 @@ func TestLanguageApi_index(t *testing.T) {
 			"http://localhost:3000",
 			&TestApiScenario{
 				ExpectedCode:    200,
-				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","created":1518946878,"modified":1518946878},{"id":"5a89601d35eca6cea28f09c8","locale":"bg","title":"Bulgarian","created":1518946878,"modified":1518946878},{"id":"5a8be9dfe1382310bec8076a","locale":"de","title":"German","created":1518946878,"modified":1518946878}]`},
+				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","fallback":[],"created":1518946878,"modified":1518946878},{"id":"5a89601d35eca6cea28f09c8","locale":"bg","title":"Bulgarian","fallback":["en"],"created":1518946878,"modified":1518946878},{"id":"5a8be9dfe1382310bec8076a","locale":"de","title":"German","fallback":["en"],"created":1518946878,"modified":1518946878}]`},
 				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "3", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
 			},
 		},
@@ -75,7 +75,7 @@ func TestLanguageApi_index(t *testing.T) {
 			"http://localhost:3000/?q[title]=English&q[locale]=en",
 			&TestApiScenario{
 				ExpectedCode:    200,
-				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","created":1518946878,"modified":1518946878}]`},
+				ExpectedContent: []string{`[{"id":"5a894a3ee138237565d4f7ce","locale":"en","title":"English","fallback":[],"created":1518946878,"modified":1518946878}]`},
 				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "1", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
 			},
 		},
@@ -83,7 +83,7 @@ func TestLanguageApi_index(t *testing.T) {
 			"http://localhost:3000/?sort=-locale&limit=1&page=2",
 			&TestApiScenario{
 				ExpectedCode:    200,
-				ExpectedContent: []string{`[{"id":"5a8be9dfe1382310bec8076a","locale":"de","title":"German","created":1518946878,"modified":1518946878}]`},
+				ExpectedContent: []string{`[{"id":"5a8be9dfe1382310bec8076a","locale":"de","title":"German","fallback":["en"],"created":1518946878,"modified":1518946878}]`},
 				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "3", "X-Pagination-Page-Count": "3", "X-Pagination-Per-Page": "1", "X-Pagination-Current-Page": "2"},
 			},
 		},
@@ -44,6 +44,7 @@ func InitMediaApi(rg *routing.Router, session *mgo.Session) {
 	rg.Put("/media/<id>", authenticateToken(session, "media", "update"), api.update)
 	rg.Post("/media/<id>", authenticateToken(session, "media", "update"), api.replace)
 	rg.Delete("/media/<id>", authenticateToken(session, "media", "delete"), api.delete)
+	rg.Get("/media/<id>/referenced-by", authenticateToken(session, "media", "view"), api.referencedBy)
 }
 
 // index api handler for fetching paginated media items list
@@ -51,6 +52,10 @@ func (api *MediaApi) index(c *routing.Context) error {
 	// --- fetch search data
 	searchFields := []string{"title", "type", "path", "created", "modified"}
 	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
 	// ---
 
 	// --- fetch sort data
@@ -58,20 +63,51 @@ func (api *MediaApi) index(c *routing.Context) error {
 	sortData := utils.GetSortFields(c, sortFields)
 	// ---
 
-	total, _ := api.dao.Count(searchData)
+	items := []models.Media{}
 
-	limit, page := utils.GetPaginationSettings(c, total)
+	if utils.IsCursorPagination(c) {
+		sortData = utils.CursorSortFields(sortData)
 
-	utils.SetPaginationHeaders(c, limit, total, page)
+		cursorData, cursorErr := utils.GetCursorConditions(c, sortData)
+		if cursorErr != nil {
+			return utils.NewBadRequestError("Oops, an error occurred while parsing the pagination cursor.", cursorErr)
+		}
 
-	items := []models.Media{}
+		total := -1
+		if utils.IsCountRequested(c) {
+			total, _ = api.dao.Count(searchData)
+		}
+
+		if cursorData != nil {
+			utils.AppendAndCondition(searchData, cursorData)
+		}
+
+		limit, _ := utils.GetPaginationSettings(c, 0)
+
+		// fetch one extra item to check whether there is a next page
+		items, _ = api.dao.GetList(limit+1, 0, searchData, sortData)
+
+		nextCursor := ""
+		if len(items) > limit {
+			items = items[:limit]
+			nextCursor, _ = utils.NewCursor(items[limit-1], sortData)
+		}
+
+		utils.SetCursorHeaders(c, limit, total, nextCursor)
+	} else {
+		total, _ := api.dao.Count(searchData)
+
+		limit, page := utils.GetPaginationSettings(c, total)
 
-	if total > 0 {
-		items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+		utils.SetPaginationHeaders(c, limit, total, page)
 
-		items = daos.ToAbsMediaPaths(items)
+		if total > 0 {
+			items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+		}
 	}
 
+	items = daos.ToAbsMediaPaths(items)
+
 	return c.Write(items)
 }
 
@@ -136,6 +172,23 @@ func (api *MediaApi) delete(c *routing.Context) error {
 	return nil
 }
 
+// referencedBy api handler for fetching the entity items that reference a media item
+func (api *MediaApi) referencedBy(c *routing.Context) error {
+	id := c.Param("id")
+
+	model, fetchErr := api.dao.GetByID(id)
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Media item with id \"%v\" doesn't exist!", id))
+	}
+
+	items, referencesErr := daos.NewEntityDAO(api.mongoSession).GetMediaReferences(model, isUser(c), referenceAccessConditions(c))
+	if referencesErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while fetching the Media item references.", referencesErr)
+	}
+
+	return c.Write(presentReferences(c, items))
+}
+
 // upload api handler for uploading and creating multiple media items
 func (api *MediaApi) upload(c *routing.Context) error {
 	items := []*models.Media{}
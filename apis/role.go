@@ -0,0 +1,138 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// RoleApi defines Role api services
+type RoleApi struct {
+	router       *routing.Router
+	mongoSession *mgo.Session
+	dao          *daos.RoleDAO
+}
+
+// InitRoleApi sets up the routing of Role endpoints and the corresponding handlers.
+func InitRoleApi(rg *routing.Router, session *mgo.Session) {
+	api := RoleApi{
+		router:       rg,
+		mongoSession: session,
+		dao:          daos.NewRoleDAO(session),
+	}
+
+	rg.Get("/roles", authenticateToken(session, "role", "index"), usersOnly, api.index)
+	rg.Post("/roles", authenticateToken(session, "role", "create"), usersOnly, api.create)
+	rg.Get("/roles/<id>", authenticateToken(session, "role", "view"), usersOnly, api.view)
+	rg.Put("/roles/<id>", authenticateToken(session, "role", "update"), usersOnly, api.update)
+	rg.Delete("/roles/<id>", authenticateToken(session, "role", "delete"), usersOnly, api.delete)
+}
+
+// index api handler for fetching paginated Role model list.
+func (api *RoleApi) index(c *routing.Context) error {
+	// --- fetch search data
+	searchFields := []string{"title", "created", "modified"}
+	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
+	// ---
+
+	// --- fetch sort data
+	sortFields := []string{"title", "created", "modified"}
+	sortData := utils.GetSortFields(c, sortFields)
+	// ---
+
+	total, _ := api.dao.Count(searchData)
+
+	limit, page := utils.GetPaginationSettings(c, total)
+
+	utils.SetPaginationHeaders(c, limit, total, page)
+
+	items := []models.Role{}
+
+	if total > 0 {
+		items, _ = api.dao.GetList(limit, limit*(page-1), searchData, sortData)
+	}
+
+	return c.Write(items)
+}
+
+// view api handler for fetching single Role model data.
+func (api *RoleApi) view(c *routing.Context) error {
+	id := c.Param("id")
+
+	model, err := api.dao.GetByID(id)
+
+	if err != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Role model with id \"%v\" doesn't exist!", id))
+	}
+
+	return c.Write(model)
+}
+
+// create api handler for creating a new Role model.
+func (api *RoleApi) create(c *routing.Context) error {
+	form := &models.RoleForm{}
+
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while creating Role model.", readErr)
+	}
+
+	model, createErr := api.dao.Create(form)
+	if createErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while creating Role model.", createErr)
+	}
+
+	return c.Write(model)
+}
+
+// update api handler for updating an existing Role model.
+// The changes take effect for all role users and keys.
+func (api *RoleApi) update(c *routing.Context) error {
+	id := c.Param("id")
+
+	model, fetchErr := api.dao.GetByID(id)
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Role model with id \"%v\" doesn't exist!", id))
+	}
+
+	form := &models.RoleForm{Model: model}
+
+	if readErr := c.Read(form); readErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while updating Role model.", readErr)
+	}
+
+	updatedModel, updateErr := api.dao.Update(form)
+	if updateErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while updating Role model.", updateErr)
+	}
+
+	return c.Write(updatedModel)
+}
+
+// delete api handler for deleting an existing Role model.
+func (api *RoleApi) delete(c *routing.Context) error {
+	id := c.Param("id")
+
+	model, fetchErr := api.dao.GetByID(id)
+	if fetchErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Role model with id \"%v\" doesn't exist!", id))
+	}
+
+	deleteErr := api.dao.Delete(model)
+	if deleteErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while deleting Role model.", deleteErr)
+	}
+
+	c.Response.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
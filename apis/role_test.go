@@ -0,0 +1,264 @@
+package apis
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+	"github.com/go-ozzo/ozzo-routing/content"
+)
+
+func TestInitRoleApi(t *testing.T) {
+	router := routing.New()
+
+	InitRoleApi(router, TestSession)
+
+	expectedRoutes := []string{
+		"GET /roles",
+		"POST /roles",
+		"GET /roles/<id>",
+		"PUT /roles/<id>",
+		"DELETE /roles/<id>",
+	}
+
+	routes := router.Routes()
+
+	assertInitApiRoutes(t, routes, expectedRoutes)
+}
+
+func TestRoleApi_index(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []struct {
+		Url      string
+		Scenario *TestApiScenario
+	}{
+		{
+			"http://localhost:3000/?q[title]=missing",
+			&TestApiScenario{
+				ExpectedCode:    200,
+				ExpectedContent: []string{`[]`},
+				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "0", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
+			},
+		},
+		{
+			"http://localhost:3000/?q[title]=Viewer",
+			&TestApiScenario{
+				ExpectedCode:    200,
+				ExpectedContent: []string{`[{"id":"5b1a3c4de138234cd5a2e002","title":"Viewer","access":{"language":["index","view"],"media":["index","view"]},"created":1528446030,"modified":1528446030}]`},
+				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "1", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
+			},
+		},
+		{
+			"http://localhost:3000/?sort=-title",
+			&TestApiScenario{
+				ExpectedCode:    200,
+				ExpectedContent: []string{`[{"id":"5b1a3c4de138234cd5a2e002",`, `{"id":"5b1a3c4de138234cd5a2e001",`},
+				ExpectedHeaders: map[string]string{"X-Pagination-Total-Count": "2", "X-Pagination-Page-Count": "1", "X-Pagination-Per-Page": "15", "X-Pagination-Current-Page": "1"},
+			},
+		},
+	}
+
+	for _, item := range testScenarios {
+		api, c := mockRoleApi("GET", item.Url, nil)
+
+		assertTestApiScenario(t, item.Scenario, c, api.index)
+	}
+}
+
+func TestRoleApi_view(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Params:          map[string]string{"id": ""},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"id": "5a75ee63e1382336728c2add"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"id": "5b1a3c4de138234cd5a2e001"},
+			ExpectedCode:    200,
+			ExpectedContent: []string{`{"id":"5b1a3c4de138234cd5a2e001","title":"Editor",`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockRoleApi("GET", "http://localhost:3000", nil)
+
+		assertTestApiScenario(t, scenario, c, api.view)
+	}
+}
+
+func TestRoleApi_create(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Data:            `{}`,
+			ExpectedCode:    400,
+			ExpectedContent: []string{`"data":{"access":"cannot be blank","title":"cannot be blank"}`},
+		},
+		&TestApiScenario{
+			Data:            `{"title": "Test title", "access": {"media": ["index"]}}`,
+			ExpectedCode:    200,
+			ExpectedContent: []string{`"title":"Test title"`, `"access":{"media":["index"]}`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockRoleApi("POST", "http://localhost:3000", strings.NewReader(scenario.Data))
+
+		assertTestApiScenario(t, scenario, c, api.create)
+	}
+}
+
+func TestRoleApi_update(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Data:            `{}`,
+			Params:          map[string]string{"id": "5a75ee63e1382336728c2add"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Data:            `{}`,
+			Params:          map[string]string{"id": "5b1a3c4de138234cd5a2e001"},
+			ExpectedCode:    400,
+			ExpectedContent: []string{`"data":{"access":"cannot be blank","title":"cannot be blank"}`},
+		},
+		&TestApiScenario{
+			Data:            `{"title": "Test title", "access": {"media": ["index"]}}`,
+			Params:          map[string]string{"id": "5b1a3c4de138234cd5a2e001"},
+			ExpectedCode:    200,
+			ExpectedContent: []string{`"id":"5b1a3c4de138234cd5a2e001"`, `"title":"Test title"`, `"access":{"media":["index"]}`},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockRoleApi("PUT", "http://localhost:3000", strings.NewReader(scenario.Data))
+
+		assertTestApiScenario(t, scenario, c, api.update)
+	}
+}
+
+func TestRoleApi_delete(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	testScenarios := []*TestApiScenario{
+		&TestApiScenario{
+			Params:          map[string]string{"id": ""},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"id": "5a75ee63e1382336728c2add"},
+			ExpectedCode:    404,
+			ExpectedContent: []string{`"status":404`, `"data":null`, `"message":`},
+		},
+		&TestApiScenario{
+			Params:          map[string]string{"id": "5b1a3c4de138234cd5a2e001"},
+			ExpectedCode:    204,
+			ExpectedContent: nil,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		api, c := mockRoleApi("DELETE", "http://localhost:3000", nil)
+
+		assertTestApiScenario(t, scenario, c, api.delete)
+	}
+}
+
+func TestRoleApi_updateEffectiveAccess(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	user := models.User{ID: bson.ObjectIdHex("5a7c9017e138234e16e3dee6")}
+
+	// user2 holding the "Viewer" role
+	TestSession.DB("").C("user").UpdateId(user.ID, bson.M{"$set": bson.M{
+		"access": map[string][]string{"user": []string{"index"}},
+		"roles":  []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")},
+	}})
+
+	token, _ := user.NewAuthToken(time.Now().Add(time.Hour).Unix())
+
+	checkAccess := func(group, action string) error {
+		req := httptest.NewRequest("GET", "http://localhost:3000", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		c := routing.NewContext(httptest.NewRecorder(), req)
+
+		return authenticateToken(TestSession, group, action)(c)
+	}
+
+	// role permission
+	if err := checkAccess("language", "view"); err != nil {
+		t.Fatal("Expected the role language access to be granted, got error ", err)
+	}
+
+	// own permission
+	if err := checkAccess("user", "index"); err != nil {
+		t.Fatal("Expected the own user access to be granted, got error ", err)
+	}
+
+	// missing permission
+	if err := checkAccess("entity", "view"); err == nil {
+		t.Fatal("Expected the entity access to be forbidden, got nil")
+	}
+
+	api, c := mockRoleApi("PUT", "http://localhost:3000", strings.NewReader(`{"title": "Viewer", "access": {"entity": ["view"]}}`))
+
+	assertTestApiScenario(t, &TestApiScenario{
+		Params:          map[string]string{"id": "5b1a3c4de138234cd5a2e002"},
+		ExpectedCode:    200,
+		ExpectedContent: []string{`"access":{"entity":["view"]}`},
+	}, c, api.update)
+
+	// the role change should take effect immediately
+	if err := checkAccess("entity", "view"); err != nil {
+		t.Fatal("Expected the updated role entity access to be granted, got error ", err)
+	}
+
+	if err := checkAccess("language", "view"); err == nil {
+		t.Fatal("Expected the removed role language access to be forbidden, got nil")
+	}
+}
+
+// -------------------------------------------------------------------
+// • Hepers
+// -------------------------------------------------------------------
+
+func mockRoleApi(method, url string, body io.Reader) (*RoleApi, *routing.Context) {
+	req := httptest.NewRequest(method, url, body)
+
+	w := httptest.NewRecorder()
+
+	c := routing.NewContext(w, req)
+	c.SetDataWriter(&content.JSONDataWriter{})
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	api := RoleApi{mongoSession: TestSession, dao: daos.NewRoleDAO(TestSession)}
+
+	return &api, c
+}
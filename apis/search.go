@@ -0,0 +1,126 @@
+package apis
+
+import (
+	"strings"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// SearchApi defines global full-text search api services
+type SearchApi struct {
+	router        *routing.Router
+	mongoSession  *mgo.Session
+	entityDAO     *daos.EntityDAO
+	collectionDAO *daos.CollectionDAO
+}
+
+// InitSearchApi sets up the routing of search endpoints and the corresponding handlers.
+func InitSearchApi(rg *routing.Router, session *mgo.Session) {
+	api := SearchApi{
+		router:        rg,
+		mongoSession:  session,
+		entityDAO:     daos.NewEntityDAO(session),
+		collectionDAO: daos.NewCollectionDAO(session),
+	}
+
+	rg.Get("/search", authenticateToken(session), api.search)
+}
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// search api handler for full-text searching the entity items of all accessible collections
+func (api *SearchApi) search(c *routing.Context) error {
+	term := strings.TrimSpace(c.Query("term"))
+	if term == "" {
+		return utils.NewBadRequestError("Missing required search term.", nil)
+	}
+
+	// the slice is always initialized to prevent serializing nil `$in` value
+	collectionIds := append([]bson.ObjectId{}, getAccessColectionIds(c, "index")...)
+
+	searchData := bson.M{"collection_id": bson.M{"$in": collectionIds}}
+
+	if !isUser(c) {
+		searchData["status"] = models.EntityStatusActive
+	}
+
+	return writeSearchResults(c, api.entityDAO, api.collectionDAO, term, searchData)
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// writeSearchResults fetches, enriches and writes the paginated full-text search results.
+func writeSearchResults(
+	c *routing.Context,
+	entityDAO *daos.EntityDAO,
+	collectionDAO *daos.CollectionDAO,
+	term string,
+	searchData bson.M,
+) error {
+	items := []models.SearchResult{}
+
+	total, _ := entityDAO.CountSearch(term, searchData)
+
+	limit, page := utils.GetPaginationSettings(c, total)
+
+	utils.SetPaginationHeaders(c, limit, total, page)
+
+	if total > 0 {
+		items, _ = entityDAO.Search(term, limit, limit*(page-1), searchData)
+
+		// --- enrich
+		enrichSettings := &daos.EntityEnrichSettings{
+			EnrichMedia:      (canAccess(c, "media", "view") == nil),
+			RelCollectionIds: getAccessColectionIds(c, "view"),
+			Expand:           daos.NewEntityExpand(utils.GetListParam(c, "expand")),
+		}
+		if !isUser(c) {
+			enrichSettings.RelConditions = bson.M{"status": models.EntityStatusActive}
+			enrichSettings.PublishedOnly = true
+		}
+
+		// group the results by collection, so that each group is enriched at once
+		groups := map[bson.ObjectId][]int{}
+		collectionIds := []bson.ObjectId{}
+
+		for i, item := range items {
+			if _, ok := groups[item.CollectionID]; !ok {
+				collectionIds = append(collectionIds, item.CollectionID)
+			}
+
+			groups[item.CollectionID] = append(groups[item.CollectionID], i)
+		}
+
+		for _, collectionID := range collectionIds {
+			collection, collectionErr := collectionDAO.GetByID(collectionID.Hex())
+			if collectionErr != nil {
+				continue
+			}
+
+			entities := []models.Entity{}
+			for _, i := range groups[collectionID] {
+				entities = append(entities, items[i].Entity)
+			}
+
+			entities = entityDAO.EnrichEntities(entities, collection, enrichSettings)
+
+			for j, i := range groups[collectionID] {
+				items[i].Entity = entities[j]
+				items[i].ResolveSnippets(collection, term)
+			}
+		}
+		// ---
+	}
+
+	return c.Write(items)
+}
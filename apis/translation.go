@@ -0,0 +1,227 @@
+package apis
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// TranslationApi defines collection translation export/import api services
+type TranslationApi struct {
+	router        *routing.Router
+	mongoSession  *mgo.Session
+	entityDAO     *daos.EntityDAO
+	collectionDAO *daos.CollectionDAO
+	languageDAO   *daos.LanguageDAO
+}
+
+// InitTranslationApi sets up the routing of translation endpoints and the corresponding handlers.
+func InitTranslationApi(rg *routing.Router, session *mgo.Session) {
+	api := TranslationApi{
+		router:        rg,
+		mongoSession:  session,
+		entityDAO:     daos.NewEntityDAO(session),
+		collectionDAO: daos.NewCollectionDAO(session),
+		languageDAO:   daos.NewLanguageDAO(session),
+	}
+
+	rg.Get("/collections/<cidentifier>/translations", authenticateToken(session), api.exportFile)
+	rg.Post("/collections/<cidentifier>/translations", authenticateToken(session), api.importFile)
+}
+
+// -------------------------------------------------------------------
+// • API endpoint handlers
+// -------------------------------------------------------------------
+
+// exportFile api handler for exporting the collection entities translatable
+// field values for a source/target locale pair (as XLIFF 1.2 or flat JSON).
+func (api *TranslationApi) exportFile(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "index"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	source := c.Query("source")
+	target := c.Query("target")
+	format := c.Query("format", models.TranslationFormatJson)
+
+	if localesErr := api.checkLocales(source, target); localesErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while exporting the translations.", localesErr)
+	}
+
+	if format != models.TranslationFormatJson && format != models.TranslationFormatXliff {
+		return utils.NewBadRequestError("Unsupported translation format '"+format+"'.", nil)
+	}
+
+	// --- fetch entities
+	conditions := bson.M{"collection_id": collection.ID}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	projection := daos.EntityProjection{
+		Fields:  []string{},
+		Locales: []string{source, target},
+	}
+	for _, field := range collection.TranslatableFields() {
+		projection.Fields = append(projection.Fields, field.Key)
+	}
+
+	entities := []models.Entity{}
+	if len(projection.Fields) > 0 {
+		entities, _ = api.entityDAO.GetList(0, 0, conditions, []string{"_id"}, projection)
+	}
+	// ---
+
+	pendingOnly := c.Query("pending") == "1" || c.Query("pending") == "true"
+
+	units := models.NewTranslationUnits(entities, collection, source, target, pendingOnly)
+
+	if format == models.TranslationFormatXliff {
+		return writeXliff(c, fmt.Sprintf("%s.%s-%s.xlf", collection.Name, source, target), models.NewXliff(collection.Name, source, target, units))
+	}
+
+	result := map[string]string{}
+	for _, unit := range units {
+		result[unit.ID] = unit.Source
+	}
+
+	return c.Write(result)
+}
+
+// importFile api handler for importing translated XLIFF 1.2 or flat JSON collection entity values.
+func (api *TranslationApi) importFile(c *routing.Context) error {
+	cidentifier := c.Param("cidentifier")
+
+	// --- ensure collection access
+	collection, collectionErr := api.collectionDAO.GetByNameOrID(cidentifier)
+	if collectionErr != nil {
+		return utils.NewNotFoundError(fmt.Sprintf("Collection item with identifier \"%v\" doesn't exist!", cidentifier))
+	}
+
+	if accessErr := canAccess(c, collection.ID.Hex(), "update"); accessErr != nil {
+		return accessErr
+	}
+	// ---
+
+	target := c.Query("target")
+	format := c.Query("format", models.TranslationFormatJson)
+
+	units := []models.TranslationUnit{}
+
+	switch format {
+	case models.TranslationFormatXliff:
+		doc := models.Xliff{}
+		if readErr := xml.NewDecoder(c.Request.Body).Decode(&doc); readErr != nil {
+			return utils.NewBadRequestError("Oops, an error occurred while reading the XLIFF file.", readErr)
+		}
+
+		if target == "" {
+			target = doc.TargetLanguage()
+		}
+
+		units = doc.TranslationUnits()
+	case models.TranslationFormatJson:
+		data := map[string]string{}
+		if readErr := json.NewDecoder(c.Request.Body).Decode(&data); readErr != nil {
+			return utils.NewBadRequestError("Oops, an error occurred while reading the JSON file.", readErr)
+		}
+
+		// sort the unit ids to keep the import order consistent
+		ids := []string{}
+		for id, _ := range data {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			units = append(units, models.TranslationUnit{ID: id, Target: data[id]})
+		}
+	default:
+		return utils.NewBadRequestError("Unsupported translation format '"+format+"'.", nil)
+	}
+
+	if _, localeErr := api.languageDAO.GetByLocale(target); localeErr != nil {
+		return utils.NewBadRequestError("Missing or invalid target locale.", nil)
+	}
+
+	conditions := bson.M{}
+
+	if !isUser(c) {
+		conditions["status"] = models.EntityStatusActive
+	}
+
+	// api keys don't work with drafts
+	publish := !isUser(c)
+
+	result := api.entityDAO.ImportTranslations(collection, target, units, publish, getRevisionAuthor(c), conditions)
+
+	go api.sendUpdateHooks(collection, result.Entities)
+
+	return c.Write(result)
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// sendUpdateHooks sends entity update hook for each of the provided entities.
+func (api *TranslationApi) sendUpdateHooks(collection *models.Collection, entities []models.Entity) {
+	hookDAO := daos.NewHookDeliveryDAO(api.mongoSession)
+
+	for i := range entities {
+		entities[i].ExpandSharedData()
+
+		hookDAO.Send(collection, collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, &entities[i])
+	}
+}
+
+// checkLocales checks whether the source and target locales are different existing languages.
+func (api *TranslationApi) checkLocales(source string, target string) error {
+	if _, err := api.languageDAO.GetByLocale(source); err != nil {
+		return utils.NewDataError(map[string]string{"source": "Missing or invalid source locale."})
+	}
+
+	if _, err := api.languageDAO.GetByLocale(target); err != nil {
+		return utils.NewDataError(map[string]string{"target": "Missing or invalid target locale."})
+	}
+
+	if source == target {
+		return utils.NewDataError(map[string]string{"target": "The target locale must be different from the source one."})
+	}
+
+	return nil
+}
+
+// writeXliff writes the provided XLIFF document as a downloadable response.
+func writeXliff(c *routing.Context, filename string, doc *models.Xliff) error {
+	c.Response.Header().Set("Content-Type", "application/x-xliff+xml; charset=UTF-8")
+	c.Response.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+
+	if _, err := c.Response.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(c.Response)
+	encoder.Indent("", "\t")
+
+	return encoder.Encode(doc)
+}
@@ -39,6 +39,10 @@ func (api *UserApi) index(c *routing.Context) error {
 	// --- fetch search data
 	searchFields := []string{"username", "email", "status", "created", "modified"}
 	searchData := utils.GetSearchConditions(c, searchFields)
+
+	if filterErr := utils.AppendFilterConditions(c, searchData, searchFields); filterErr != nil {
+		return utils.NewBadRequestError("Oops, an error occurred while parsing the filter expression.", filterErr)
+	}
 	// ---
 
 	// --- fetch sort data
@@ -97,7 +101,7 @@ func (api *UserApi) update(c *routing.Context) error {
 		return utils.NewNotFoundError(fmt.Sprintf("User with id \"%v\" is inactive or doesn't exist!", id))
 	}
 
-	form := &models.UserUpdateForm{}
+	form := &models.UserUpdateForm{Roles: user.Roles, RequireTwoFactor: user.RequireTwoFactor}
 	if readErr := c.Read(form); readErr != nil {
 		return utils.NewBadRequestError("Oops, an error occurred while updating user model.", readErr)
 	}
@@ -69,9 +69,41 @@ func initDefaultConfig(v *viper.Viper) {
 	v.SetDefault("jwt.signingKey", "__your_key__")
 	v.SetDefault("jwt.signingMethod", "HS256")
 
-	// user auth token session duration (in hours)
+	// user auth session (aka. refresh token) duration (in hours)
 	v.SetDefault("userTokenExpire", 72)
 
+	// user access token duration (in minutes)
+	v.SetDefault("userAccessTokenExpire", 15)
+
+	// how long the old api key token remains valid after a rotation (in hours)
+	v.SetDefault("keys.rotationGracePeriod", 24)
+	// max grace period that could be requested for a single rotation (in hours)
+	v.SetDefault("keys.maxRotationGracePeriod", 720)
+
+	// two-factor authentication settings
+	// --- the issuer name displayed in the authenticator apps
+	v.SetDefault("twoFactor.issuer", "Gofreta")
+	// --- the two-factor authentication challenge token duration (in minutes)
+	v.SetDefault("twoFactor.challengeExpire", 5)
+
+	// roles settings
+	// --- how long the roles access permissions are cached by each app instance (in seconds, 0 to disable)
+	v.SetDefault("roles.cacheExpire", 60)
+	// --- how often each app instance checks for role changes made by other instances (in seconds)
+	v.SetDefault("roles.stampCheckInterval", 5)
+
+	// sign in and reset password requests rate limit settings
+	// --- the attempts state storage ("memory", "mongo" for multi-instance deployments or empty to disable)
+	v.SetDefault("rateLimit.driver", "memory")
+	// --- max failed sign in attempts (or reset password requests) per ip and username within a single window
+	v.SetDefault("rateLimit.maxAttempts", 5)
+	// --- attempts counting window (in minutes)
+	v.SetDefault("rateLimit.window", 15)
+	// --- lockout duration after reaching the max attempts (in minutes)
+	v.SetDefault("rateLimit.lockout", 15)
+	// --- if not empty, the client ip is read from this request header (eg. "X-Real-IP" when behind a trusted proxy)
+	v.SetDefault("rateLimit.ipHeader", "")
+
 	// reset password settings
 	v.SetDefault("resetPassword.secret", "__your_secret__")
 	v.SetDefault("resetPassword.expire", 2)
@@ -83,12 +115,31 @@ func initDefaultConfig(v *viper.Viper) {
 	v.SetDefault("pagination.defaultLimit", 15)
 	v.SetDefault("pagination.maxLimit", 100)
 
+	// entity relation and media enrich settings
+	v.SetDefault("enrich.maxLevel", 3)
+	v.SetDefault("enrich.batchSize", 100)
+
+	// max entity operations in a single bulk request
+	v.SetDefault("bulk.maxOperations", 1000)
+
+	// max entity rows in a single import file
+	v.SetDefault("import.maxRows", 1000)
+
 	// upload settings
 	v.SetDefault("upload.maxSize", 5)
 	v.SetDefault("upload.thumbs", []string{"100x100", "300x300"})
 	v.SetDefault("upload.dir", "./uploads")
 	v.SetDefault("upload.url", "http://localhost:8090/upload")
 
+	// hook deliveries settings
+	v.SetDefault("hooks.timeout", 10)
+	v.SetDefault("hooks.maxAttempts", 5)
+	v.SetDefault("hooks.retryDelay", 30)
+	v.SetDefault("hooks.interval", 15)
+
+	// entity publish scheduler check interval (in seconds, 0 to disable)
+	v.SetDefault("publishScheduler.interval", 60)
+
 	// system email addresses
 	v.SetDefault("emails.noreply", "noreply@example.com")
 	v.SetDefault("emails.support", "support@example.com")
@@ -116,12 +167,12 @@ func InitRouter(mediaDir string) {
 		// all these handlers are shared by every route
 		access.Logger(log.Printf),
 		slash.Remover(http.StatusMovedPermanently),
-		fault.Recovery(log.Printf),
+		fault.Recovery(log.Printf, setErrorHeaders),
 		cors.Handler(cors.Options{
 			AllowOrigins:  "*",
 			AllowHeaders:  "*",
 			AllowMethods:  "*",
-			ExposeHeaders: "X-Pagination-Total-Count,X-Pagination-Page-Count,X-Pagination-Per-Page,X-Pagination-Current-Page",
+			ExposeHeaders: "X-Pagination-Total-Count,X-Pagination-Page-Count,X-Pagination-Per-Page,X-Pagination-Current-Page,X-Pagination-Next-Cursor,Link,Retry-After",
 		}),
 	)
 
@@ -148,6 +199,20 @@ func InitRouter(mediaDir string) {
 	)
 }
 
+// setErrorHeaders sets the additional response headers of the errors
+// that provide them (eg. `Retry-After` of the 429 api errors).
+func setErrorHeaders(c *routing.Context, err error) error {
+	if headersErr, ok := err.(interface {
+		ResponseHeaders() map[string]string
+	}); ok {
+		for key, value := range headersErr.ResponseHeaders() {
+			c.Response.Header().Set(key, value)
+		}
+	}
+
+	return err
+}
+
 // InitApp initializes all main app components.
 func InitApp() error {
 	// parse command line flags
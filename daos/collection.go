@@ -2,8 +2,10 @@ package daos
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
@@ -158,6 +160,10 @@ func (dao *CollectionDAO) Create(form *models.CollectionForm) (*models.Collectio
 	// db write
 	dbErr := session.DB("").C(dao.Collection).Insert(model)
 
+	if dbErr == nil {
+		NewEntityDAO(session).EnsureGeoIndexes()
+	}
+
 	return model, dbErr
 }
 
@@ -172,14 +178,62 @@ func (dao *CollectionDAO) Update(form *models.CollectionForm) (*models.Collectio
 		return &models.Collection{}, validateErr
 	}
 
+	diff := form.SchemaDiff()
+
+	if conflictsErr := dao.checkSharedDataConflicts(form, diff); conflictsErr != nil {
+		return &models.Collection{}, conflictsErr
+	}
+
+	oldSearchableKeys := form.Model.SearchableFieldKeys()
+
 	model := form.ResolveModel()
 
 	// db write
 	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
 
+	// @todo add some sort of transaction support
+	// migrate the existing entities data
+	if dbErr == nil {
+		entityDAO := NewEntityDAO(session)
+		entityDAO.EnsureGeoIndexes()
+
+		if migrateErr := entityDAO.MigrateSchema(model, diff); migrateErr != nil {
+			return model, migrateErr
+		}
+
+		searchableKeys := model.SearchableFieldKeys()
+		if !diff.IsEmpty() || strings.Join(oldSearchableKeys, ",") != strings.Join(searchableKeys, ",") {
+			if refreshErr := entityDAO.RefreshSearchTexts(model); refreshErr != nil {
+				return model, refreshErr
+			}
+		}
+	}
+
 	return model, dbErr
 }
 
+// DryRunUpdate validates the provided form and returns the fields schema diff
+// with the number of affected collection entities without persisting any changes.
+func (dao *CollectionDAO) DryRunUpdate(form *models.CollectionForm) (*models.SchemaDiff, error) {
+	// validate
+	validateErr := form.Validate()
+	if validateErr != nil {
+		return nil, validateErr
+	}
+
+	diff := form.SchemaDiff()
+
+	if conflictsErr := dao.checkSharedDataConflicts(form, diff); conflictsErr != nil {
+		return nil, conflictsErr
+	}
+
+	affected, countErr := NewEntityDAO(dao.Session).CountSchemaAffected(form.Model.ID, diff)
+
+	diff.Affected = affected
+
+	return diff, countErr
+}
+
 // Delete deletes single collection model by its id.
 func (dao *CollectionDAO) Delete(model *models.Collection) error {
 	session := dao.Session.Copy()
@@ -188,11 +242,56 @@ func (dao *CollectionDAO) Delete(model *models.Collection) error {
 	deleteErr := session.DB("").C(dao.Collection).RemoveId(model.ID)
 
 	// @todo add some sort of transaction support
-	// deletes all related entities
+	// deletes all related entities, their revisions and the collection hook deliveries
 	if deleteErr == nil {
 		entityDAO := NewEntityDAO(session)
 		entityDAO.DeleteAll(bson.M{"collection_id": model.ID})
+
+		revisionDAO := NewEntityRevisionDAO(session)
+		revisionDAO.DeleteAll(bson.M{"collection_id": model.ID})
+
+		hookDeliveryDAO := NewHookDeliveryDAO(session)
+		hookDeliveryDAO.DeleteAll(bson.M{"collection_id": model.ID})
 	}
 
 	return deleteErr
 }
+
+// -------------------------------------------------------------------
+// • Helpers and filters
+// -------------------------------------------------------------------
+
+// checkSharedDataConflicts returns an error if any of the form fields
+// that are changed from multilingual to non-multilingual has different
+// locale values in the existing collection entities (they can't be stored once without losing data).
+func (dao *CollectionDAO) checkSharedDataConflicts(form *models.CollectionForm, diff *models.SchemaDiff) error {
+	fields := []models.CollectionField{}
+
+	for _, field := range form.Fields {
+		if field.Multilingual || !utils.StringInSlice(field.Key, diff.Multilingual) {
+			continue
+		}
+
+		// the entities still hold the not renamed field key
+		for oldKey, newKey := range diff.Renamed {
+			if newKey == field.Key {
+				field.Key = oldKey
+
+				break
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	conflicts, err := NewEntityDAO(dao.Session).SharedDataConflicts(form.Model.ID, fields)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return errors.New("Field(s) '" + strings.Join(conflicts, "', '") + "' can't be shared across locales because some entities have different locale values.")
+	}
+
+	return nil
+}
@@ -352,6 +352,201 @@ func TestCollectionDAO_Update(t *testing.T) {
 	}
 }
 
+func TestCollectionDAO_Update_Migration(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewCollectionDAO(TestSession)
+	entityDAO := NewEntityDAO(TestSession)
+
+	originalModel, _ := dao.GetByName("col1")
+
+	form := &models.CollectionForm{
+		Model: originalModel,
+		Name:  "col1",
+		Title: "Collection 1",
+		Fields: []models.CollectionField{
+			{Key: "name", Type: models.FieldTypePlain, Label: "Name", Multilingual: true},
+			{Key: "description", Type: models.FieldTypeNumber, Label: "Description", Multilingual: true},
+		},
+		Renames: map[string]string{"title": "name"},
+	}
+
+	if _, err := dao.Update(form); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	entity, _ := entityDAO.GetByID("5a8bea7ee1382310bec8076c")
+
+	for _, locale := range []string{"en", "bg", "de"} {
+		data := entity.Data[locale]
+		draft := entity.Draft[locale]
+
+		if _, exist := data["title"]; exist {
+			t.Errorf("Expected %s title key to be renamed, got %v", locale, data)
+		}
+
+		if data["name"] != "Test 2 title "+locale || draft["name"] != "Test 2 draft title "+locale {
+			t.Errorf("Expected %s name key to be set, got %v and %v", locale, data, draft)
+		}
+
+		if _, exist := data["short_description"]; exist {
+			t.Errorf("Expected %s short_description key to be removed, got %v", locale, data)
+		}
+
+		if data["description"] != nil || draft["description"] != nil {
+			t.Errorf("Expected %s description to be recasted to nil, got %v and %v", locale, data, draft)
+		}
+	}
+}
+
+func TestCollectionDAO_Update_MultilingualMigration(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewCollectionDAO(TestSession)
+	entityDAO := NewEntityDAO(TestSession)
+
+	originalModel, _ := dao.GetByName("col1")
+
+	fields := []models.CollectionField{
+		{Key: "title", Type: models.FieldTypePlain, Label: "Title", Multilingual: true},
+		{Key: "short_description", Type: models.FieldTypeEditor, Label: "Short description"},
+		{Key: "description", Type: models.FieldTypeEditor, Label: "Description", Multilingual: true},
+	}
+
+	// multilingual -> shared with different locale values
+	conflictForm := &models.CollectionForm{Model: originalModel, Name: "col1", Title: "Collection 1", Fields: fields}
+
+	if _, err := dao.Update(conflictForm); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	unchanged, _ := entityDAO.GetByID("5a8bea7ee1382310bec8076c")
+	if unchanged.Data["de"]["short_description"] != "Test 2 short description de" {
+		t.Fatalf("Expected the entity data to be unchanged, got %v", unchanged.Data)
+	}
+
+	// multilingual -> shared with the same locale values
+	for _, id := range []string{"5a8bea3ae1382310bec8076b", "5a8bea7ee1382310bec8076c"} {
+		set := bson.M{}
+		for _, locale := range []string{"en", "bg", "de"} {
+			set["data."+locale+".short_description"] = "Short description"
+			if id == "5a8bea7ee1382310bec8076c" {
+				set["draft."+locale+".short_description"] = "Short description"
+			}
+		}
+
+		if err := TestSession.DB("").C(entityDAO.Collection).UpdateId(bson.ObjectIdHex(id), bson.M{"$set": set}); err != nil {
+			t.Fatal("Failed to update test entity", err)
+		}
+	}
+
+	sharedForm := &models.CollectionForm{Model: originalModel, Name: "col1", Title: "Collection 1", Fields: fields}
+
+	sharedModel, sharedErr := dao.Update(sharedForm)
+	if sharedErr != nil {
+		t.Fatal("Expected nil, got error", sharedErr)
+	}
+
+	entity, _ := entityDAO.GetByID("5a8bea7ee1382310bec8076c")
+
+	for _, data := range []map[string]map[string]interface{}{entity.Data, entity.Draft} {
+		if _, exist := data[models.EntitySharedDataKey]["short_description"]; !exist {
+			t.Errorf("Expected short_description to be moved in the shared group, got %v", data)
+		}
+
+		for _, locale := range []string{"en", "bg", "de"} {
+			if _, exist := data[locale]["short_description"]; exist {
+				t.Errorf("Expected %s short_description key to be removed, got %v", locale, data[locale])
+			}
+
+			if data[locale]["title"] == nil || data[locale]["description"] == nil {
+				t.Errorf("Expected %s multilingual fields to be unchanged, got %v", locale, data[locale])
+			}
+		}
+	}
+
+	shared := entity.Data[models.EntitySharedDataKey]["short_description"]
+
+	// shared -> multilingual
+	fields[1].Multilingual = true
+	multilingualForm := &models.CollectionForm{Model: sharedModel, Name: "col1", Title: "Collection 1", Fields: fields}
+
+	if _, err := dao.Update(multilingualForm); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	entity, _ = entityDAO.GetByID("5a8bea7ee1382310bec8076c")
+
+	if _, exist := entity.Data[models.EntitySharedDataKey]; exist {
+		t.Errorf("Expected the shared group to be removed, got %v", entity.Data)
+	}
+
+	for _, locale := range []string{"en", "bg", "de"} {
+		if entity.Data[locale]["short_description"] != shared {
+			t.Errorf("Expected %s short_description to be %v, got %v", locale, shared, entity.Data[locale])
+		}
+	}
+}
+
+func TestCollectionDAO_DryRunUpdate(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewCollectionDAO(TestSession)
+	entityDAO := NewEntityDAO(TestSession)
+
+	originalModel, _ := dao.GetByName("col1")
+
+	// invalid form
+	invalidForm := &models.CollectionForm{Model: originalModel}
+	if _, err := dao.DryRunUpdate(invalidForm); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// valid form
+	form := &models.CollectionForm{
+		Model: originalModel,
+		Name:  "col1",
+		Title: "Collection 1",
+		Fields: []models.CollectionField{
+			{Key: "name", Type: models.FieldTypePlain, Label: "Name", Multilingual: true},
+		},
+		Renames: map[string]string{"title": "name"},
+	}
+
+	diff, err := dao.DryRunUpdate(form)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if diff.Affected != 2 {
+		t.Error("Expected 2 affected entities, got", diff.Affected)
+	}
+
+	if diff.Renamed["title"] != "name" || len(diff.Removed) != 2 {
+		t.Error("Unexpected schema diff", diff)
+	}
+
+	// renamed field with different locale values that can't be shared
+	form.Fields[0].Multilingual = false
+	if _, err := dao.DryRunUpdate(form); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// nothing should be changed
+	collection, _ := dao.GetByName("col1")
+	if len(collection.Fields) != len(originalModel.Fields) {
+		t.Error("Expected the collection fields to be unchanged, got", collection.Fields)
+	}
+
+	entity, _ := entityDAO.GetByID("5a8bea3ae1382310bec8076b")
+	if entity.Data["en"]["title"] != "Test 1 title en" {
+		t.Error("Expected the entity data to be unchanged, got", entity.Data)
+	}
+}
+
 func TestCollectionDAO_Delete(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -386,5 +581,11 @@ func TestCollectionDAO_Delete(t *testing.T) {
 		if getErr == nil {
 			t.Error("Expected the record to be deleted")
 		}
+
+		// ensure that the collection hook deliveries have been deleted
+		deliveries, _ := NewHookDeliveryDAO(TestSession).Count(bson.M{"collection_id": scenario.model.ID})
+		if deliveries != 0 {
+			t.Error("Expected the collection hook deliveries to be deleted, got", deliveries)
+		}
 	}
 }
@@ -42,3 +42,11 @@ func TestMain(m *testing.M) {
 	// call with result of m.Run()
 	os.Exit(retCode)
 }
+
+// runMigrations applies the db upgrade steps to the loaded fixtures
+// (some of them are stored in their legacy format, eg. the "col2" entity data).
+func runMigrations(t *testing.T) {
+	if _, err := NewMigrationDAO(TestSession).Run(); err != nil {
+		t.Fatal("Failed to apply the db migrations", err)
+	}
+}
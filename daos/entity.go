@@ -2,7 +2,11 @@ package daos
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gofreta/gofreta-api/app"
 	"github.com/gofreta/gofreta-api/models"
 	"github.com/gofreta/gofreta-api/utils"
 
@@ -10,23 +14,126 @@ import (
 	"github.com/globalsign/mgo/bson"
 )
 
-const maxEnrichLevel = 3
-const maxEnrichRels = 100
-
 type EntityEnrichSettings struct {
 	Level            int
 	EnrichMedia      bool
+	PublishedOnly    bool
 	RelCollectionIds []bson.ObjectId
 	RelConditions    bson.M
 	MediaConditions  bson.M
+	Fields           []string     // enrich only the specified data fields (empty means all)
+	Locales          []string     // fetch only the specified relation locales (empty means all)
+	Expand           EntityExpand // enrich only the specified media and relation field paths (nil means all)
+}
+
+// EntityExpand defines a tree of data field keys that should be enriched.
+// A nil tree means all fields, while an empty one - none.
+type EntityExpand map[string]EntityExpand
+
+// NewEntityExpand creates a new expand tree from a list of dot separated field paths
+// (eg. `[]string{"author", "author.avatar", "tags"}`).
+// Returns nil (aka. enrich all fields) if no paths are provided.
+func NewEntityExpand(paths []string) EntityExpand {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	result := EntityExpand{}
+
+	for _, path := range paths {
+		node := result
+
+		for _, key := range strings.Split(path, ".") {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				break
+			}
+
+			if _, ok := node[key]; !ok {
+				node[key] = EntityExpand{}
+			}
+
+			node = node[key]
+		}
+	}
+
+	return result
+}
+
+// Child returns the expand subtree of a single field key
+// and whether the field should be enriched at all.
+func (e EntityExpand) Child(key string) (EntityExpand, bool) {
+	if e == nil {
+		return nil, true
+	}
+
+	sub, ok := e[key]
+
+	return sub, ok
+}
+
+// Merge returns a new expand tree with the fields of both trees.
+func (e EntityExpand) Merge(other EntityExpand) EntityExpand {
+	if e == nil || other == nil {
+		return nil
+	}
+
+	result := EntityExpand{}
+
+	for _, tree := range []EntityExpand{e, other} {
+		for key, sub := range tree {
+			if existing, ok := result[key]; ok {
+				result[key] = existing.Merge(sub)
+			} else {
+				result[key] = sub
+			}
+		}
+	}
+
+	return result
+}
+
+// EntityProjection defines the entity data fields and locales that should be fetched.
+// Empty Fields/Locales means all fields/locales.
+type EntityProjection struct {
+	Fields  []string
+	Locales []string
+	Paths   []string // additional document paths to include (eg. sort fields)
 }
 
+// IsEmpty checks whether the projection has any data fields or locales restrictions.
+func (p EntityProjection) IsEmpty() bool {
+	return len(p.Fields) == 0 && len(p.Locales) == 0
+}
+
+// entityMetaFields lists the entity document fields that are always included in a projection.
+var entityMetaFields = []string{"_id", "collection_id", "status", "publish_at", "unpublish_at", "published", "created", "modified", "needs_translation"}
+
 // EntityDAO gets and persists entity data in database.
 type EntityDAO struct {
 	Session    *mgo.Session
 	Collection string
 }
 
+// ensureIndexes creates the entity collection indexes (if not already).
+func (dao *EntityDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	// the entities data is multilingual, so language specific stemming is disabled
+	searchIndex := mgo.Index{
+		Key:             []string{"$text:search_text"},
+		DefaultLanguage: "none",
+		Background:      true,
+	}
+
+	if err := c.EnsureIndex(searchIndex); err != nil {
+		panic(err)
+	}
+}
+
 // NewEntityDAO creates a new EntityDAO.
 func NewEntityDAO(session *mgo.Session) *EntityDAO {
 	dao := &EntityDAO{
@@ -34,9 +141,62 @@ func NewEntityDAO(session *mgo.Session) *EntityDAO {
 		Collection: "entity",
 	}
 
+	dao.ensureIndexes()
+
 	return dao
 }
 
+// EnsureGeoIndexes makes sure that all collections geopoint fields
+// have a 2dsphere index for each data locale (and the shared data group).
+func (dao *EntityDAO) EnsureGeoIndexes() error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	languages, languagesErr := NewLanguageDAO(session).GetAll()
+	if languagesErr != nil {
+		return languagesErr
+	}
+
+	collections, collectionsErr := NewCollectionDAO(session).GetList(0, 0, bson.M{"fields.type": models.FieldTypeGeoPoint}, nil)
+	if collectionsErr != nil {
+		return collectionsErr
+	}
+
+	// the non-multilingual field values are stored in the shared data group
+	locales := []string{models.EntitySharedDataKey}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	c := session.DB("").C(dao.Collection)
+
+	for _, collection := range collections {
+		for _, field := range collection.Fields {
+			if field.Type != models.FieldTypeGeoPoint {
+				continue
+			}
+
+			for _, locale := range locales {
+				key := "data." + locale + "." + field.Key
+
+				// index only the geo points to prevent insert errors
+				// for other collections with the same non geopoint field key
+				index := mgo.Index{
+					Key:           []string{"$2dsphere:" + key},
+					Background:    true,
+					PartialFilter: bson.M{key + ".type": models.GeoPointType},
+				}
+
+				if err := c.EnsureIndex(index); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------
 // • Query methods
 // -------------------------------------------------------------------
@@ -54,7 +214,8 @@ func (dao *EntityDAO) Count(conditions bson.M) (int, error) {
 }
 
 // GetList returns list with Entity models.
-func (dao *EntityDAO) GetList(limit int, offset int, conditions bson.M, sortData []string) ([]models.Entity, error) {
+// Optionally you could specify a projection to fetch only specific data fields and locales.
+func (dao *EntityDAO) GetList(limit int, offset int, conditions bson.M, sortData []string, projection ...EntityProjection) ([]models.Entity, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
 
@@ -68,6 +229,7 @@ func (dao *EntityDAO) GetList(limit int, offset int, conditions bson.M, sortData
 
 	err := session.DB("").C(dao.Collection).
 		Find(conditions).
+		Select(dao.projectionSelector(projection...)).
 		Collation(collation).
 		Sort(sortData...).
 		Skip(offset).
@@ -78,7 +240,8 @@ func (dao *EntityDAO) GetList(limit int, offset int, conditions bson.M, sortData
 }
 
 // GetOne returns single entity based on the provided conditions.
-func (dao *EntityDAO) GetOne(conditions bson.M) (*models.Entity, error) {
+// Optionally you could specify a projection to fetch only specific data fields and locales.
+func (dao *EntityDAO) GetOne(conditions bson.M, projection ...EntityProjection) (*models.Entity, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
 
@@ -86,6 +249,7 @@ func (dao *EntityDAO) GetOne(conditions bson.M) (*models.Entity, error) {
 
 	err := session.DB("").C(dao.Collection).
 		Find(conditions).
+		Select(dao.projectionSelector(projection...)).
 		One(model)
 
 	return model, err
@@ -126,6 +290,39 @@ func (dao *EntityDAO) GetByIdAndCollection(id string, collectionProp string, add
 	return dao.GetByID(id, conditions)
 }
 
+// SlugConditions returns the find conditions that match the collection entities
+// having `slug` in any locale of any of the collection slug fields.
+// Returns nil if the collection doesn't have slug fields.
+func (dao *EntityDAO) SlugConditions(collection *models.Collection, slug string) (bson.M, error) {
+	languages, err := NewLanguageDAO(dao.Session).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	or := []bson.M{}
+	for _, field := range collection.Fields {
+		if field.Type != models.FieldTypeSlug {
+			continue
+		}
+
+		if !field.Multilingual {
+			or = append(or, bson.M{"data." + models.EntitySharedDataKey + "." + field.Key: slug})
+
+			continue
+		}
+
+		for _, lang := range languages {
+			or = append(or, bson.M{"data." + lang.Locale + "." + field.Key: slug})
+		}
+	}
+
+	if len(or) == 0 {
+		return nil, nil
+	}
+
+	return bson.M{"collection_id": collection.ID, "$or": or}, nil
+}
+
 // GetEntityCollection returns the collection model related to an entity.
 func (dao *EntityDAO) GetEntityCollection(entity *models.Entity) (*models.Collection, error) {
 	collectionDAO := NewCollectionDAO(dao.Session)
@@ -133,6 +330,30 @@ func (dao *EntityDAO) GetEntityCollection(entity *models.Entity) (*models.Collec
 	return collectionDAO.GetByID(entity.CollectionID.Hex())
 }
 
+// CountSearch returns the total number of entities matching the full-text search `term` and `conditions`.
+func (dao *EntityDAO) CountSearch(term string, conditions bson.M) (int, error) {
+	return dao.Count(searchConditions(term, conditions))
+}
+
+// Search performs full-text search over the entities searchable data fields
+// and returns the matching items ranked by their text score.
+func (dao *EntityDAO) Search(term string, limit int, offset int, conditions bson.M) ([]models.SearchResult, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.SearchResult{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(searchConditions(term, conditions)).
+		Select(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		Sort("$textScore:score", "-_id").
+		Skip(offset).
+		Limit(limit).
+		All(&items)
+
+	return items, err
+}
+
 // -------------------------------------------------------------------
 // • DB persists methods
 // -------------------------------------------------------------------
@@ -142,119 +363,991 @@ func (dao *EntityDAO) Create(form *models.EntityForm) (*models.Entity, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
 
-	// validate form fields
-	if err := form.Validate(); err != nil {
-		return &models.Entity{}, err
-	}
-
-	model := form.ResolveModel()
-
-	// validate entity data fields based on its collection requirements
-	if err := dao.validateAndNormalizeData(model); err != nil {
+	model, err := dao.prepareCreate(form)
+	if err != nil {
 		return model, err
 	}
 
 	// db write
 	dbErr := session.DB("").C(dao.Collection).Insert(model)
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionCreate, form.Author)
 
-	return model, dbErr
+	return model, revisionErr
 }
 
 // Update updates and returns existing entity model.
+// The form data is stored only as entity draft, unless `form.Publish` is set.
 func (dao *EntityDAO) Update(form *models.EntityForm) (*models.Entity, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
 
-	// validate form fields
-	if err := form.Validate(); err != nil {
-		return &models.Entity{}, err
+	model, err := dao.prepareUpdate(form)
+	if err != nil {
+		return model, err
 	}
 
-	model := form.ResolveModel()
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionUpdate, form.Author)
+
+	return model, revisionErr
+}
+
+// ImportTranslations updates the `target` locale values of the collection entities
+// with the provided translation units. Each entity is updated through `Update()`,
+// so the imported data is validated the same way as a regular entity update.
+func (dao *EntityDAO) ImportTranslations(
+	collection *models.Collection,
+	target string,
+	units []models.TranslationUnit,
+	publish bool,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) *models.TranslationImport {
+	result := &models.TranslationImport{
+		Updated:  []bson.ObjectId{},
+		Errors:   map[string]interface{}{},
+		Entities: []models.Entity{},
+	}
+
+	translatableKeys := []string{}
+	for _, field := range collection.TranslatableFields() {
+		translatableKeys = append(translatableKeys, field.Key)
+	}
+
+	// group the translations by entity (preserving the units order)
+	entityIds := []bson.ObjectId{}
+	translations := map[bson.ObjectId]map[string]string{}
+
+	for _, unit := range units {
+		id, key, parseErr := models.ParseTranslationUnitID(unit.ID)
+		if parseErr != nil {
+			result.Errors[unit.ID] = parseErr.Error()
+
+			continue
+		}
+
+		if !utils.StringInSlice(key, translatableKeys) {
+			result.Errors[unit.ID] = "Field '" + key + "' is not translatable."
+
+			continue
+		}
+
+		// not translated
+		if unit.Target == "" {
+			continue
+		}
+
+		if _, ok := translations[id]; !ok {
+			entityIds = append(entityIds, id)
+			translations[id] = map[string]string{}
+		}
+
+		translations[id][key] = unit.Target
+	}
+
+	for _, id := range entityIds {
+		conditions := bson.M{"collection_id": collection.ID}
+		if len(additionalConditions) > 0 {
+			for k, v := range additionalConditions[0] {
+				conditions[k] = v
+			}
+		}
+
+		entity, fetchErr := dao.GetByID(id.Hex(), conditions)
+		if fetchErr != nil {
+			result.Errors[id.Hex()] = "Entity item doesn't exist."
+
+			continue
+		}
+
+		// copy the latest entity data
+		oldData := entity.Draft
+		if len(oldData) == 0 {
+			oldData = entity.Data
+		}
+
+		data := map[string]map[string]interface{}{}
+		for locale, values := range oldData {
+			data[locale] = map[string]interface{}{}
+
+			for k, v := range values {
+				data[locale][k] = v
+			}
+		}
+
+		if _, ok := data[target]; !ok {
+			data[target] = map[string]interface{}{}
+		}
+
+		for key, value := range translations[id] {
+			data[target][key] = value
+		}
+
+		form := &models.EntityForm{
+			Model:        entity,
+			CollectionID: entity.CollectionID,
+			Status:       entity.Status,
+			Data:         data,
+			PublishAt:    entity.PublishAt,
+			UnpublishAt:  entity.UnpublishAt,
+			Publish:      publish,
+			Author:       author,
+		}
+
+		model, updateErr := dao.Update(form)
+		if updateErr != nil {
+			if dataErr, ok := updateErr.(*utils.DataError); ok {
+				result.Errors[id.Hex()] = dataErr.Data
+			} else {
+				result.Errors[id.Hex()] = updateErr.Error()
+			}
+
+			continue
+		}
+
+		result.Updated = append(result.Updated, id)
+		result.Entities = append(result.Entities, *model)
+	}
+
+	return result
+}
+
+// Restore validates and restores the entity model state stored in the provided revision.
+// The revision data is restored as entity draft, except when the revision entity was deleted -
+// in that case the entity will be recreated (and published) with its original id and create time.
+func (dao *EntityDAO) Restore(revision *models.EntityRevision, author models.RevisionAuthor) (*models.Entity, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	now := time.Now().Unix()
+
+	model, fetchErr := dao.GetByID(revision.EntityID.Hex())
+	isNew := fetchErr != nil
+	if isNew {
+		model = &models.Entity{
+			ID:      revision.EntityID,
+			Created: dao.revisionEntityCreated(revision),
+		}
+
+		if model.Created <= 0 {
+			model.Created = now
+		}
+	}
+
+	oldData := model.Draft
+	if len(oldData) == 0 {
+		oldData = model.Data
+	}
+
+	model.CollectionID = revision.CollectionID
+	model.Status = revision.Status
+	model.Draft = revision.Data
+	model.Modified = now
 
 	// validate entity data fields based on its collection requirements
+	if err := dao.validateAndNormalizeDraft(model, oldData); err != nil {
+		return model, err
+	}
+
+	if isNew {
+		model.Publish()
+	}
+
+	if err := dao.refreshSearchText(model); err != nil {
+		return model, err
+	}
+
+	// db write
+	_, dbErr := session.DB("").C(dao.Collection).UpsertId(model.ID, model)
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionRestore, author)
+
+	return model, revisionErr
+}
+
+// Publish promotes the provided entity model draft into its live data and activates the entity.
+func (dao *EntityDAO) Publish(model *models.Entity, author models.RevisionAuthor) (*models.Entity, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.Publish()
+	model.Status = models.EntityStatusActive
+	model.Modified = time.Now().Unix()
+
+	// the collection fields may have changed since the draft was saved
 	if err := dao.validateAndNormalizeData(model); err != nil {
 		return model, err
 	}
 
-	// db write
-	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
-
-	return model, dbErr
-}
+	if err := dao.refreshSearchText(model); err != nil {
+		return model, err
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionPublish, author)
+
+	return model, revisionErr
+}
+
+// Unpublish deactivates the provided entity model and clears its unpublish schedule.
+func (dao *EntityDAO) Unpublish(model *models.Entity, author models.RevisionAuthor) (*models.Entity, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.Status = models.EntityStatusInactive
+	model.UnpublishAt = 0
+	model.Modified = time.Now().Unix()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionUnpublish, author)
+
+	return model, revisionErr
+}
+
+// UpdateStatus changes only the status of the provided entity model.
+func (dao *EntityDAO) UpdateStatus(model *models.Entity, status string, author models.RevisionAuthor) (*models.Entity, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	if status != models.EntityStatusActive && status != models.EntityStatusInactive {
+		return model, utils.NewDataError(map[string]interface{}{
+			"status": "Must be a valid value.",
+		})
+	}
+
+	model.Status = status
+	model.Modified = time.Now().Unix()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"status":   model.Status,
+		"modified": model.Modified,
+	}})
+	if dbErr != nil {
+		return model, dbErr
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionUpdate, author)
+
+	return model, revisionErr
+}
+
+// PublishScheduled publishes and unpublishes all entities with elapsed `publish_at` and `unpublish_at` times.
+// Each entity is claimed atomically (by clearing its schedule time) before being changed, so that
+// multiple app instances could run the scheduler without processing the same entity twice.
+// Entities that fail to publish (eg. invalid draft) are not retried.
+// Returns the list with the successfully changed entity models and the last occurred error (if any).
+func (dao *EntityDAO) PublishScheduled(author models.RevisionAuthor) ([]models.Entity, error) {
+	var lastErr error
+
+	now := time.Now().Unix()
+
+	result := []models.Entity{}
+
+	// --- publish
+	for {
+		item, claimErr := dao.claimScheduled("publish_at", now)
+		if claimErr == mgo.ErrNotFound {
+			break
+		}
+
+		if claimErr != nil {
+			return result, claimErr
+		}
+
+		model, err := dao.Publish(item, author)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		result = append(result, *model)
+	}
+	// ---
+
+	// --- unpublish
+	for {
+		item, claimErr := dao.claimScheduled("unpublish_at", now)
+		if claimErr == mgo.ErrNotFound {
+			break
+		}
+
+		if claimErr != nil {
+			return result, claimErr
+		}
+
+		model, err := dao.Unpublish(item, author)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		result = append(result, *model)
+	}
+	// ---
+
+	return result, lastErr
+}
+
+// claimScheduled atomically clears the `field` schedule time of a single entity
+// with elapsed schedule and returns the claimed entity model.
+// Returns `mgo.ErrNotFound` if there are no more entities to claim.
+func (dao *EntityDAO) claimScheduled(field string, now int64) (*models.Entity, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := &models.Entity{}
+
+	change := mgo.Change{
+		Update:    bson.M{"$set": bson.M{field: 0}},
+		ReturnNew: true,
+	}
+
+	_, err := session.DB("").C(dao.Collection).
+		Find(bson.M{field: bson.M{"$gt": 0, "$lte": now}}).
+		Apply(change, model)
+
+	return model, err
+}
+
+// Delete deletes the provided entity model.
+// Optionally you could specify the author identity that will be stored in the delete revision.
+func (dao *EntityDAO) Delete(model *models.Entity, author ...models.RevisionAuthor) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	refs, refsErr := dao.findReferenceFields(models.FieldTypeRelation, model.CollectionID)
+	if refsErr != nil {
+		return refsErr
+	}
+
+	if err := dao.checkRestrictedReferences(refs, model.ID); err != nil {
+		return err
+	}
+
+	err := session.DB("").C(dao.Collection).RemoveId(model.ID)
+	if err != nil {
+		return err
+	}
+
+	revisionAuthor := models.RevisionAuthor{}
+	if len(author) > 0 {
+		revisionAuthor = author[0]
+	}
+
+	// apply the referencing relation fields on delete policies
+	if err := dao.releaseReferences(refs, model.ID, revisionAuthor); err != nil {
+		return err
+	}
+
+	_, revisionErr := NewEntityRevisionDAO(session).Create(model, models.EntityRevisionActionDelete, revisionAuthor)
+
+	return revisionErr
+}
+
+// DeleteAll deletes all entities by the provided conditions.
+func (dao *EntityDAO) DeleteAll(conditions bson.M) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).RemoveAll(conditions)
+
+	return err
+}
+
+// InitDataLocale creates new data locale group entry by copying the default locale group fields.
+func (dao *EntityDAO) InitDataLocale(locale string, defaultLocale string) error {
+	if locale == defaultLocale {
+		return nil
+	}
+
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.Entity{}
+
+	cloneStage := bson.M{"$addFields": bson.M{
+		("data." + locale):  ("$data." + defaultLocale),
+		("draft." + locale): ("$draft." + defaultLocale),
+	}}
+
+	outputStage := bson.M{"$out": dao.Collection}
+
+	err := session.DB("").C(dao.Collection).
+		Pipe([]bson.M{cloneStage, outputStage}).
+		All(&items) // check if nill will work
+
+	return err
+}
+
+// RenameDataLocale renames data locale group of multiple entity models.
+func (dao *EntityDAO) RenameDataLocale(oldLocale string, newLocale string) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).
+		UpdateAll(bson.M{}, bson.M{"$rename": bson.M{
+			"data." + oldLocale:  "data." + newLocale,
+			"draft." + oldLocale: "draft." + newLocale,
+		}})
+
+	return err
+}
+
+// RemoveDataLocale removes data locale group of multiple entity model.
+func (dao *EntityDAO) RemoveDataLocale(locale string) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).
+		UpdateAll(bson.M{}, bson.M{"$unset": bson.M{"data." + locale: 1, "draft." + locale: 1}})
+
+	return err
+}
+
+// CountSchemaAffected returns the number of collection entities
+// that have data for any of the changed schema diff fields.
+func (dao *EntityDAO) CountSchemaAffected(collectionID bson.ObjectId, diff *models.SchemaDiff) (int, error) {
+	if diff.IsEmpty() {
+		return 0, nil
+	}
+
+	languages, languagesErr := NewLanguageDAO(dao.Session).GetAll()
+	if languagesErr != nil {
+		return 0, languagesErr
+	}
+
+	keys := append([]string{}, diff.Removed...)
+	keys = append(keys, diff.Recast...)
+	for oldKey, _ := range diff.Renamed {
+		keys = append(keys, oldKey)
+	}
+
+	// the multilingual diff keys are the new field keys, so resolve their not renamed yet ones
+	for _, key := range diff.Multilingual {
+		for oldKey, newKey := range diff.Renamed {
+			if newKey == key {
+				key = oldKey
+
+				break
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	locales := []string{models.EntitySharedDataKey}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	or := []bson.M{}
+	for _, locale := range locales {
+		for _, key := range keys {
+			or = append(or,
+				bson.M{"data." + locale + "." + key: bson.M{"$exists": true}},
+				bson.M{"draft." + locale + "." + key: bson.M{"$exists": true}},
+			)
+		}
+	}
+
+	if len(or) == 0 {
+		return 0, nil
+	}
+
+	return dao.Count(bson.M{"collection_id": collectionID, "$or": or})
+}
+
+// SharedDataConflicts returns the keys of the provided fields whose values differ
+// between the locales of any collection entity data or draft
+// (aka. the fields that cannot be stored in the shared data group without losing data).
+func (dao *EntityDAO) SharedDataConflicts(collectionID bson.ObjectId, fields []models.CollectionField) ([]string, error) {
+	result := []string{}
+
+	if len(fields) == 0 {
+		return result, nil
+	}
+
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	languages, languagesErr := NewLanguageDAO(session).GetAll()
+	if languagesErr != nil {
+		return result, languagesErr
+	}
+
+	locales := []string{}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	entity := models.Entity{}
+	iter := session.DB("").C(dao.Collection).Find(bson.M{"collection_id": collectionID}).
+		Select(bson.M{"data": 1, "draft": 1}).Iter()
+
+	for iter.Next(&entity) {
+		for _, field := range fields {
+			if utils.StringInSlice(field.Key, result) {
+				continue
+			}
+
+			if models.HasDifferentLocaleValues(entity.Data, field, locales) ||
+				models.HasDifferentLocaleValues(entity.Draft, field, locales) {
+				result = append(result, field.Key)
+			}
+		}
+
+		entity = models.Entity{}
+	}
+
+	return result, iter.Close()
+}
+
+// MigrateSchema applies the schema diff changes to all collection entities
+// (removes the deleted field keys, moves the renamed ones, recasts the changed field values
+// and moves the field values with changed multilingual flag from/to the shared data group).
+func (dao *EntityDAO) MigrateSchema(collection *models.Collection, diff *models.SchemaDiff) error {
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	languages, languagesErr := NewLanguageDAO(session).GetAll()
+	if languagesErr != nil {
+		return languagesErr
+	}
+
+	affected, countErr := dao.CountSchemaAffected(collection.ID, diff)
+	if countErr != nil {
+		return countErr
+	}
+	diff.Affected = affected
+
+	locales := []string{}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	// data groups (including the shared one) that may hold the changed field values
+	groupLocales := append([]string{models.EntitySharedDataKey}, locales...)
+
+	c := session.DB("").C(dao.Collection)
+	conditions := bson.M{"collection_id": collection.ID}
+
+	// remove deleted keys
+	if len(diff.Removed) > 0 {
+		unset := bson.M{}
+		for _, locale := range groupLocales {
+			for _, key := range diff.Removed {
+				unset["data."+locale+"."+key] = 1
+				unset["draft."+locale+"."+key] = 1
+			}
+		}
+
+		if _, err := c.UpdateAll(conditions, bson.M{"$unset": unset}); err != nil {
+			return err
+		}
+	}
+
+	// move renamed keys
+	// (through temporary keys to prevent conflicts in case of swapped field keys)
+	if len(diff.Renamed) > 0 {
+		toTmp := bson.M{}
+		fromTmp := bson.M{}
+		for _, locale := range groupLocales {
+			for _, group := range []string{"data", "draft"} {
+				for oldKey, newKey := range diff.Renamed {
+					tmpKey := group + "." + locale + ".__rename_" + newKey
+
+					toTmp[group+"."+locale+"."+oldKey] = tmpKey
+					fromTmp[tmpKey] = group + "." + locale + "." + newKey
+				}
+			}
+		}
+
+		if _, err := c.UpdateAll(conditions, bson.M{"$rename": toTmp}); err != nil {
+			return err
+		}
+
+		if _, err := c.UpdateAll(conditions, bson.M{"$rename": fromTmp}); err != nil {
+			return err
+		}
+	}
+
+	// recast changed field values
+	if len(diff.Recast) > 0 {
+		fields := []models.CollectionField{}
+		for _, field := range collection.Fields {
+			if utils.StringInSlice(field.Key, diff.Recast) {
+				fields = append(fields, field)
+			}
+		}
+
+		entity := models.Entity{}
+		iter := c.Find(conditions).Iter()
+
+		for iter.Next(&entity) {
+			set := bson.M{}
+
+			for locale, data := range entity.Data {
+				for _, field := range fields {
+					if val, ok := data[field.Key]; ok {
+						set["data."+locale+"."+field.Key] = field.CastValue(val)
+					}
+				}
+			}
+
+			for locale, data := range entity.Draft {
+				for _, field := range fields {
+					if val, ok := data[field.Key]; ok {
+						set["draft."+locale+"."+field.Key] = field.CastValue(val)
+					}
+				}
+			}
+
+			if len(set) > 0 {
+				if err := c.UpdateId(entity.ID, bson.M{"$set": set}); err != nil {
+					iter.Close()
+
+					return err
+				}
+			}
+
+			entity = models.Entity{}
+		}
+
+		if err := iter.Close(); err != nil {
+			return err
+		}
+	}
+
+	// move the field values with changed multilingual flag
+	if len(diff.Multilingual) > 0 {
+		entity := models.Entity{}
+		iter := c.Find(conditions).Iter()
+
+		for iter.Next(&entity) {
+			set := bson.M{}
+
+			if entity.Data != nil {
+				models.CollapseSharedData(entity.Data, nil, collection, locales)
+				set["data"] = entity.Data
+			}
+
+			if entity.Draft != nil {
+				models.CollapseSharedData(entity.Draft, nil, collection, locales)
+				set["draft"] = entity.Draft
+			}
+
+			if len(set) > 0 {
+				if err := c.UpdateId(entity.ID, bson.M{"$set": set}); err != nil {
+					iter.Close()
+
+					return err
+				}
+			}
+
+			entity = models.Entity{}
+		}
+
+		if err := iter.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RefreshSearchTexts rebuilds the full-text search values of all collection entities
+// (eg. after changing the collection searchable fields).
+func (dao *EntityDAO) RefreshSearchTexts(collection *models.Collection) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	entity := models.Entity{}
+	iter := c.Find(bson.M{"collection_id": collection.ID}).Iter()
+
+	for iter.Next(&entity) {
+		entity.RefreshSearchText(collection)
+
+		var update bson.M
+		if len(entity.SearchText) > 0 {
+			update = bson.M{"$set": bson.M{"search_text": entity.SearchText}}
+		} else {
+			update = bson.M{"$unset": bson.M{"search_text": 1}}
+		}
+
+		if err := c.UpdateId(entity.ID, update); err != nil {
+			iter.Close()
+
+			return err
+		}
+
+		entity = models.Entity{}
+	}
+
+	return iter.Close()
+}
+
+// -------------------------------------------------------------------
+// • Helpers and filters
+// -------------------------------------------------------------------
+
+// prepareCreate validates the provided create form and resolves
+// the new entity model ready for insert (without writing it).
+func (dao *EntityDAO) prepareCreate(form *models.EntityForm) (*models.Entity, error) {
+	// validate form fields
+	if err := form.Validate(); err != nil {
+		return &models.Entity{}, err
+	}
+
+	model := form.ResolveModel()
+
+	// fill the missing data field values with their defaults
+	if err := dao.applyFieldDefaults(model, form.Author); err != nil {
+		return model, err
+	}
+
+	// validate entity data fields based on its collection requirements
+	if err := dao.validateAndNormalizeDraft(model); err != nil {
+		return model, err
+	}
+
+	// new entities are always published
+	model.Publish()
+
+	if err := dao.refreshSearchText(model); err != nil {
+		return model, err
+	}
+
+	return model, nil
+}
+
+// prepareUpdate validates the provided update form and resolves
+// the updated entity model ready for save (without writing it).
+func (dao *EntityDAO) prepareUpdate(form *models.EntityForm) (*models.Entity, error) {
+	// validate form fields
+	if err := form.Validate(); err != nil {
+		return &models.Entity{}, err
+	}
+
+	model := form.ResolveModel()
+
+	// the latest stored data (used to resolve the changed shared field values)
+	var oldData map[string]map[string]interface{}
+	if form.Model != nil {
+		oldData = form.Model.Draft
+		if len(oldData) == 0 {
+			oldData = form.Model.Data
+		}
+	}
+
+	// validate entity data fields based on its collection requirements
+	if err := dao.validateAndNormalizeDraft(model, oldData); err != nil {
+		return model, err
+	}
+
+	if err := dao.refreshTranslationFlags(model, form.Model); err != nil {
+		return model, err
+	}
+
+	if form.Publish {
+		model.Publish()
+	}
+
+	if err := dao.refreshSearchText(model); err != nil {
+		return model, err
+	}
+
+	return model, nil
+}
+
+// refreshSearchText updates the entity full-text search values based on its collection searchable fields.
+func (dao *EntityDAO) refreshSearchText(entity *models.Entity) error {
+	collection, err := dao.GetEntityCollection(entity)
+	if err != nil {
+		return err
+	}
+
+	entity.RefreshSearchText(collection)
+
+	return nil
+}
+
+// refreshTranslationFlags updates the entity "needs translation" flags based on its `old` state.
+func (dao *EntityDAO) refreshTranslationFlags(entity *models.Entity, old *models.Entity) error {
+	if old == nil {
+		return nil
+	}
+
+	collection, collectionErr := dao.GetEntityCollection(entity)
+	if collectionErr != nil {
+		return collectionErr
+	}
+
+	languages, languagesErr := NewLanguageDAO(dao.Session).GetAll()
+	if languagesErr != nil {
+		return languagesErr
+	}
+
+	oldData := old.Draft
+	if len(oldData) == 0 {
+		oldData = old.Data
+	}
+
+	entity.RefreshTranslationFlags(oldData, collection, languages)
+
+	return nil
+}
+
+// revisionEntityCreated returns the create time of the revision entity.
+// For older revisions without stored entity create time, the time of
+// the first entity revision is returned (or 0 if there are no revisions).
+func (dao *EntityDAO) revisionEntityCreated(revision *models.EntityRevision) int64 {
+	if revision.EntityCreated > 0 {
+		return revision.EntityCreated
+	}
+
+	items, _ := NewEntityRevisionDAO(dao.Session).GetList(1, 0, bson.M{"entity_id": revision.EntityID}, []string{"created", "_id"})
+	if len(items) > 0 {
+		return items[0].Created
+	}
+
+	return 0
+}
+
+// applyFieldDefaults fills the entity draft missing field values with their collection field defaults.
+func (dao *EntityDAO) applyFieldDefaults(entity *models.Entity, author models.RevisionAuthor) error {
+	collection, collectionErr := dao.GetEntityCollection(entity)
+	if collectionErr != nil {
+		return collectionErr
+	}
+
+	languages, languagesErr := NewLanguageDAO(dao.Session).GetAll()
+	if languagesErr != nil {
+		return languagesErr
+	}
+
+	locales := []string{}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
 
-// Delete deletes the provided entity model.
-func (dao *EntityDAO) Delete(model *models.Entity) error {
-	session := dao.Session.Copy()
-	defer session.Close()
+	if entity.Draft == nil {
+		entity.Draft = map[string]map[string]interface{}{}
+	}
 
-	err := session.DB("").C(dao.Collection).RemoveId(model.ID)
+	models.ApplyFieldDefaults(entity.Draft, collection, locales, author)
 
-	return err
+	return nil
 }
 
-// DeleteAll deletes all entities by the provided conditions.
-func (dao *EntityDAO) DeleteAll(conditions bson.M) error {
-	session := dao.Session.Copy()
-	defer session.Close()
+// projectionSelector converts the first provided projection to a mongo fields selector.
+// Returns nil (aka. select all fields) if there is no projection or it is empty.
+func (dao *EntityDAO) projectionSelector(projection ...EntityProjection) bson.M {
+	if len(projection) == 0 || projection[0].IsEmpty() {
+		return nil
+	}
 
-	_, err := session.DB("").C(dao.Collection).RemoveAll(conditions)
+	p := projection[0]
 
-	return err
-}
+	selector := bson.M{}
 
-// InitDataLocale creates new data locale group entry by copying the default locale group fields.
-func (dao *EntityDAO) InitDataLocale(locale string, defaultLocale string) error {
-	if locale == defaultLocale {
-		return nil
+	for _, field := range entityMetaFields {
+		selector[field] = 1
 	}
 
-	session := dao.Session.Copy()
-	defer session.Close()
+	for _, path := range p.Paths {
+		selector[path] = 1
+	}
 
-	items := []models.Entity{}
+	locales := p.Locales
+	if len(locales) == 0 {
+		languages, _ := NewLanguageDAO(dao.Session).GetAll()
+		for _, lang := range languages {
+			locales = append(locales, lang.Locale)
+		}
+	}
 
-	cloneStage := bson.M{"$addFields": bson.M{
-		("data." + locale): ("$data." + defaultLocale),
-	}}
+	// the shared data group holds the non-multilingual field values of all locales
+	locales = append([]string{models.EntitySharedDataKey}, locales...)
 
-	outputStage := bson.M{"$out": dao.Collection}
+	for _, group := range []string{"data", "draft"} {
+		for _, locale := range locales {
+			if len(p.Fields) == 0 {
+				selector[group+"."+locale] = 1
 
-	err := session.DB("").C(dao.Collection).
-		Pipe([]bson.M{cloneStage, outputStage}).
-		All(&items) // check if nill will work
+				continue
+			}
 
-	return err
+			for _, field := range p.Fields {
+				selector[group+"."+locale+"."+field] = 1
+			}
+		}
+	}
+
+	return selector
 }
 
-// RenameDataLocale renames data locale group of multiple entity models.
-func (dao *EntityDAO) RenameDataLocale(oldLocale string, newLocale string) error {
-	session := dao.Session.Copy()
-	defer session.Close()
+// searchConditions returns a copy of the provided conditions extended with `$text` search condition.
+func searchConditions(term string, conditions bson.M) bson.M {
+	result := bson.M{}
 
-	_, err := session.DB("").C(dao.Collection).
-		UpdateAll(bson.M{}, bson.M{"$rename": bson.M{"data." + oldLocale: "data." + newLocale}})
+	for key, val := range conditions {
+		result[key] = val
+	}
 
-	return err
+	result["$text"] = bson.M{"$search": term}
+
+	return result
 }
 
-// RemoveDataLocale removes data locale group of multiple entity model.
-func (dao *EntityDAO) RemoveDataLocale(locale string) error {
-	session := dao.Session.Copy()
-	defer session.Close()
+// validateAndNormalizeDraft validates entity draft data by collection fields settings.
+// Optionally you could specify the previous entity data (see `validateAndNormalizeData()`).
+func (dao *EntityDAO) validateAndNormalizeDraft(entity *models.Entity, oldData ...map[string]map[string]interface{}) error {
+	draft := *entity
+	draft.Data = entity.Draft
 
-	_, err := session.DB("").C(dao.Collection).
-		UpdateAll(bson.M{}, bson.M{"$unset": bson.M{"data." + locale: 1}})
+	err := dao.validateAndNormalizeData(&draft, oldData...)
+
+	entity.Draft = draft.Data
 
 	return err
 }
 
-// -------------------------------------------------------------------
-// • Helpers and filters
-// -------------------------------------------------------------------
-
 // validateAndNormalizeData validates entity data by collection fields settings.
-func (dao *EntityDAO) validateAndNormalizeData(entity *models.Entity) error {
+// The non-multilingual field values are stored (and validated) only once in the shared data group.
+// Optionally you could specify the previous entity data to detect in which locale
+// a shared field value was changed (see `models.CollapseSharedData()`).
+func (dao *EntityDAO) validateAndNormalizeData(entity *models.Entity, oldData ...map[string]map[string]interface{}) error {
 	languageDAO := NewLanguageDAO(dao.Session)
 	languages, languagesErr := languageDAO.GetAll()
 	if languagesErr != nil {
@@ -275,6 +1368,44 @@ func (dao *EntityDAO) validateAndNormalizeData(entity *models.Entity) error {
 	// remove unexisting locales and field keys
 	filterEntityData(entity, collection, languages)
 
+	locales := []string{}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	var old map[string]map[string]interface{}
+	if len(oldData) > 0 {
+		old = oldData[0]
+	}
+
+	// move the non-multilingual field values into the shared data group
+	models.CollapseSharedData(entity.Data, old, collection, locales)
+
+	// normalize and validate the shared data entries
+	// (the errors are reported under each locale for consistency)
+	sharedErrors := map[string]string{}
+	for _, field := range collection.Fields {
+		if field.Multilingual {
+			continue
+		}
+
+		if _, ok := entity.Data[models.EntitySharedDataKey]; !ok {
+			entity.Data[models.EntitySharedDataKey] = map[string]interface{}{}
+		}
+
+		v := field.CastValue(entity.Data[models.EntitySharedDataKey][field.Key])
+		if field.Type == models.FieldTypeSlug {
+			slug, slugErr := dao.resolveSlug(entity, collection, field, v, models.EntitySharedDataKey, locales)
+			if slugErr != nil {
+				return slugErr
+			}
+			v = slug
+		}
+		entity.Data[models.EntitySharedDataKey][field.Key] = v
+
+		dao.validateField(entity, field, v, models.EntitySharedDataKey+"."+field.Key, field.Key, sharedErrors)
+	}
+
 	// normalize and validate data entries
 	for _, lang := range languages {
 		if _, ok := entity.Data[lang.Locale]; !ok {
@@ -282,73 +1413,211 @@ func (dao *EntityDAO) validateAndNormalizeData(entity *models.Entity) error {
 		}
 
 		localeErrors := map[string]string{}
+		for key, err := range sharedErrors {
+			localeErrors[key] = err
+		}
 
 		for _, field := range collection.Fields {
+			if !field.Multilingual {
+				continue
+			}
+
 			// normalize/cast field value
 			v := field.CastValue(nil)
 			if _, ok := entity.Data[lang.Locale][field.Key]; ok {
 				v = field.CastValue(entity.Data[lang.Locale][field.Key])
 			}
+			if field.Type == models.FieldTypeSlug {
+				slug, slugErr := dao.resolveSlug(entity, collection, field, v, lang.Locale, locales)
+				if slugErr != nil {
+					return slugErr
+				}
+				v = slug
+			}
 			entity.Data[lang.Locale][field.Key] = v
 
-			// required check
-			if field.Required == true && field.IsEmptyValue(v) {
-				localeErrors[field.Key] = "This field is required."
+			dao.validateField(entity, field, v, lang.Locale+"."+field.Key, field.Key, localeErrors)
+		}
+
+		if len(localeErrors) > 0 {
+			errorsMap[lang.Locale] = localeErrors
+		}
+	}
+
+	if len(errorsMap) > 0 {
+		return utils.NewDataError(errorsMap)
+	}
+
+	return nil
+}
 
+// resolveSlug returns an unique (within the entity collection data and drafts `locale` group) slug field value.
+// Empty slugs are generated from the field meta source value.
+// If the slug is already taken, a numeric suffix is appended to it (eg. "my-slug-2").
+func (dao *EntityDAO) resolveSlug(
+	entity *models.Entity,
+	collection *models.Collection,
+	field models.CollectionField,
+	value interface{},
+	locale string,
+	locales []string,
+) (interface{}, error) {
+	slug, _ := value.(string)
+
+	if slug == "" {
+		meta, _ := models.NewMetaSlug(field.Meta)
+
+		for _, source := range collection.Fields {
+			if source.Key != meta.Source {
 				continue
 			}
 
-			// max check
-			if field.Type == models.FieldTypeMedia {
-				meta, metaErr := models.NewMetaMedia(field.Meta)
-
-				ids := utils.InterfaceToObjectIds(v)
+			sourceLocales := []string{locale}
+			if !source.Multilingual {
+				sourceLocales = []string{models.EntitySharedDataKey}
+			} else if locale == models.EntitySharedDataKey {
+				// shared slug of a multilingual source - use the first non-empty locale value
+				sourceLocales = locales
+			}
 
-				if metaErr != nil || (meta.Max != 0 && uint8(len(ids)) > meta.Max) {
-					localeErrors[field.Key] = "The field is invalid or doesn't match the minimum requirements."
+			for _, sourceLocale := range sourceLocales {
+				sourceValue, _ := source.CastValue(entity.Data[sourceLocale][source.Key]).(string)
 
-					continue
+				if slug = utils.Slugify(sourceValue); slug != "" {
+					break
 				}
-			} else if field.Type == models.FieldTypeRelation {
-				meta, metaErr := models.NewMetaRelation(field.Meta)
+			}
+		}
+	}
 
-				ids := utils.InterfaceToObjectIds(v)
+	if slug == "" {
+		return slug, nil
+	}
 
-				if metaErr != nil || (meta.Max != 0 && uint8(len(ids)) > meta.Max) {
-					localeErrors[field.Key] = "The field is invalid or doesn't match the minimum requirements."
+	conditions := bson.M{"collection_id": entity.CollectionID}
+	if entity.ID.Valid() {
+		conditions["_id"] = bson.M{"$ne": entity.ID}
+	}
 
-					continue
-				}
-			}
+	path := locale + "." + field.Key
 
-			// unique check
-			if field.Unique == true {
-				conditions := bson.M{"collection_id": collection.ID}
-				conditions["data."+lang.Locale+"."+field.Key] = v
+	result := slug
+	for i := 2; ; i++ {
+		conditions["$or"] = []bson.M{
+			bson.M{"data." + path: result},
+			bson.M{"draft." + path: result},
+		}
 
-				listItem, _ := dao.GetList(1, 0, conditions, nil)
+		total, err := dao.Count(conditions)
+		if err != nil {
+			return nil, err
+		}
 
-				if len(listItem) > 0 {
-					localeErrors[field.Key] = "The field value must be unique."
+		if total == 0 {
+			break
+		}
 
-					continue
-				}
-			}
+		result = slug + "-" + strconv.Itoa(i)
+	}
+
+	return result, nil
+}
+
+// validateField validates single casted (!!!) entity field value and registers the found error(s) in `errs`.
+// `path` is the field value path within the entity data (used for the unique check against
+// both the live and the draft data of the other entities) and `errKey` - its errors map key.
+// Group fields are validated recursively (eg. "faq.0.question").
+func (dao *EntityDAO) validateField(
+	entity *models.Entity,
+	field models.CollectionField,
+	value interface{},
+	path string,
+	errKey string,
+	errs map[string]string,
+) {
+	// required check
+	if field.Required == true && field.IsEmptyValue(value) {
+		errs[errKey] = "This field is required."
+
+		return
+	}
+
+	// meta rules check
+	if err := field.ValidateValue(value); err != nil {
+		errs[errKey] = err.Error()
+
+		return
+	}
+
+	// max check
+	if field.Type == models.FieldTypeMedia {
+		meta, metaErr := models.NewMetaMedia(field.Meta)
+
+		ids := utils.InterfaceToObjectIds(value)
+
+		if metaErr != nil || (meta.Max != 0 && uint8(len(ids)) > meta.Max) {
+			errs[errKey] = "The field is invalid or doesn't match the minimum requirements."
+
+			return
 		}
+	} else if field.Type == models.FieldTypeRelation {
+		meta, metaErr := models.NewMetaRelation(field.Meta)
 
-		if len(localeErrors) > 0 {
-			errorsMap[lang.Locale] = localeErrors
+		ids := utils.InterfaceToObjectIds(value)
+
+		if metaErr != nil || (meta.Max != 0 && uint8(len(ids)) > meta.Max) {
+			errs[errKey] = "The field is invalid or doesn't match the minimum requirements."
+
+			return
 		}
 	}
 
-	if len(errorsMap) > 0 {
-		return utils.NewDataError(errorsMap)
+	// unique check
+	if field.Unique == true {
+		conditions := bson.M{
+			"collection_id": entity.CollectionID,
+			"$or": []bson.M{
+				bson.M{"data." + path: value},
+				bson.M{"draft." + path: value},
+			},
+		}
+		if entity.ID.Valid() {
+			conditions["_id"] = bson.M{"$ne": entity.ID}
+		}
+
+		listItem, _ := dao.GetList(1, 0, conditions, nil)
+
+		if len(listItem) > 0 {
+			errs[errKey] = "The field value must be unique."
+
+			return
+		}
 	}
 
-	return nil
+	// nested group fields check
+	if field.Type == models.FieldTypeGroup && !field.IsEmptyValue(value) {
+		meta, metaErr := models.NewMetaGroup(field.Meta)
+		if metaErr != nil {
+			errs[errKey] = "The field is invalid or doesn't match the minimum requirements."
+
+			return
+		}
+
+		for i, item := range models.GroupItems(value) {
+			itemKey := errKey
+			if meta.Repeat {
+				itemKey += "." + strconv.Itoa(i)
+			}
+
+			for _, subField := range meta.Fields {
+				dao.validateField(entity, subField, item[subField.Key], path+"."+subField.Key, itemKey+"."+subField.Key, errs)
+			}
+		}
+	}
 }
 
-// filterEntityData normalizes and removes unexisting data locale entries and collectio field keys.
+// filterEntityData normalizes and removes unexisting data locale entries and collectio field keys
+// (the shared data group is kept).
 func filterEntityData(entity *models.Entity, collection *models.Collection, languages []models.Language) {
 	for locale, localeProps := range entity.Data {
 		// --- remove unregistered fields
@@ -370,7 +1639,7 @@ func filterEntityData(entity *models.Entity, collection *models.Collection, lang
 		// ---
 
 		// --- remove unregistered locales
-		isValidLocale := false
+		isValidLocale := locale == models.EntitySharedDataKey
 		for _, lang := range languages {
 			if locale == lang.Locale {
 				isValidLocale = true
@@ -427,11 +1696,39 @@ func (dao *EntityDAO) EnrichEntities(
 	collection *models.Collection,
 	settings *EntityEnrichSettings,
 ) []models.Entity {
-	if settings.Level > maxEnrichLevel {
+	// present the shared (non-multilingual) field values under each locale
+	// after the enrichment, so that they are enriched only once
+	defer func() {
+		for i, _ := range entities {
+			entities[i].ExpandSharedData()
+		}
+	}()
+
+	if settings.PublishedOnly {
+		for i, _ := range entities {
+			entities[i].Draft = nil
+		}
+	}
+
+	if settings.Level > app.Config.GetInt("enrich.maxLevel") {
 		return entities
 	}
 
-	mediaIds, relationIds := extractMediaAndRelationIds(entities, collection)
+	// skip the not requested data fields
+	if len(settings.Fields) > 0 {
+		filtered := *collection
+		filtered.Fields = []models.CollectionField{}
+
+		for _, field := range collection.Fields {
+			if utils.StringInSlice(field.Key, settings.Fields) {
+				filtered.Fields = append(filtered.Fields, field)
+			}
+		}
+
+		collection = &filtered
+	}
+
+	mediaIds, relationIds := extractMediaAndRelationIds(entities, collection, settings.Expand)
 
 	if len(mediaIds) > 0 || len(relationIds) > 0 {
 		// --- medias
@@ -440,8 +1737,8 @@ func (dao *EntityDAO) EnrichEntities(
 			mediaDAO := NewMediaDAO(dao.Session)
 
 			mConditions := bson.M{}
-			if settings.MediaConditions != nil {
-				mConditions = settings.MediaConditions
+			for key, val := range settings.MediaConditions {
+				mConditions[key] = val
 			}
 			mConditions["_id"] = bson.M{"$in": mediaIds}
 
@@ -451,45 +1748,24 @@ func (dao *EntityDAO) EnrichEntities(
 		// ---
 
 		// --- relations
-		rConditions := bson.M{}
-		if settings.RelConditions != nil {
-			rConditions = settings.RelConditions
-		}
-		rConditions["_id"] = bson.M{"$in": relationIds}
-		rConditions["collection_id"] = bson.M{"$in": settings.RelCollectionIds}
-
-		relations, _ := dao.GetList(maxEnrichRels, 0, rConditions, nil)
+		relations := dao.getEnrichRelations(relationIds, settings)
 
 		subSettings := &EntityEnrichSettings{
 			Level:            settings.Level + 1,
 			EnrichMedia:      settings.EnrichMedia,
+			PublishedOnly:    settings.PublishedOnly,
 			RelCollectionIds: settings.RelCollectionIds,
 			RelConditions:    settings.RelConditions,
 			MediaConditions:  settings.MediaConditions,
+			Locales:          settings.Locales,
 		}
 
-		relations = dao.enrichEntityRelations(relations, subSettings)
+		relations = dao.enrichEntityRelations(relations, subSettings, relationExpands(collection.Fields, settings.Expand))
 		// ---
 
 		for i, item := range entities {
-			for j, dataItem := range item.Data {
-				for key, val := range dataItem {
-					for _, field := range collection.Fields {
-						if field.Key != key {
-							continue
-						}
-
-						if field.Type == models.FieldTypeMedia {
-							entities[i].Data[j][key] = extractEntityMedias(utils.InterfaceToObjectIds(val), medias, field)
-
-							break
-						} else if field.Type == models.FieldTypeRelation {
-							entities[i].Data[j][key] = extractEntityRelations(utils.InterfaceToObjectIds(val), relations, field)
-
-							break
-						}
-					}
-				}
+			for j, _ := range item.Data {
+				enrichDataFields(entities[i].Data[j], collection.Fields, medias, relations, settings.Expand)
 			}
 		}
 	}
@@ -497,8 +1773,115 @@ func (dao *EntityDAO) EnrichEntities(
 	return entities
 }
 
+// getEnrichRelations fetches the relation entities with the provided ids
+// in batches of `enrich.batchSize` items, so that none of them is skipped.
+func (dao *EntityDAO) getEnrichRelations(ids []bson.ObjectId, settings *EntityEnrichSettings) []models.Entity {
+	result := []models.Entity{}
+
+	batchSize := app.Config.GetInt("enrich.batchSize")
+	if batchSize <= 0 {
+		batchSize = len(ids)
+	}
+
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		conditions := bson.M{}
+		for key, val := range settings.RelConditions {
+			conditions[key] = val
+		}
+		conditions["_id"] = bson.M{"$in": ids[start:end]}
+		conditions["collection_id"] = bson.M{"$in": settings.RelCollectionIds}
+
+		items, err := dao.GetList(0, 0, conditions, nil, EntityProjection{Locales: settings.Locales})
+		if err != nil {
+			continue
+		}
+
+		result = append(result, items...)
+	}
+
+	return result
+}
+
+// EnrichLocalizedEntities enriches localized entity relation and media data fields.
+func (dao *EntityDAO) EnrichLocalizedEntities(
+	entities []models.LocalizedEntity,
+	collection *models.Collection,
+	settings *EntityEnrichSettings,
+) []models.LocalizedEntity {
+	wrapped := make([]models.Entity, len(entities))
+
+	for i, item := range entities {
+		wrapped[i] = models.Entity{
+			ID:           item.ID,
+			CollectionID: item.CollectionID,
+			Data:         map[string]map[string]interface{}{item.Locale: item.Data},
+		}
+
+		if item.Draft != nil {
+			wrapped[i].Draft = map[string]map[string]interface{}{item.Locale: item.Draft}
+		}
+	}
+
+	wrapped = dao.EnrichEntities(wrapped, collection, settings)
+
+	for i, item := range wrapped {
+		entities[i].Data = item.Data[entities[i].Locale]
+
+		if item.Draft == nil {
+			entities[i].Draft = nil
+			entities[i].DraftLocales = nil
+		}
+	}
+
+	return entities
+}
+
+// enrichDataFields replaces recursively the media and relation ids of a single data item
+// with their corresponding models (only for the fields from the `expand` tree).
+func enrichDataFields(
+	data map[string]interface{},
+	fields []models.CollectionField,
+	medias []models.Media,
+	relations []models.Entity,
+	expand EntityExpand,
+) {
+	for _, field := range fields {
+		sub, expanded := expand.Child(field.Key)
+		if !expanded {
+			continue
+		}
+
+		val, ok := data[field.Key]
+		if !ok {
+			continue
+		}
+
+		if field.Type == models.FieldTypeMedia {
+			data[field.Key] = extractEntityMedias(utils.InterfaceToObjectIds(val), medias, field)
+		} else if field.Type == models.FieldTypeRelation {
+			data[field.Key] = extractEntityRelations(utils.InterfaceToObjectIds(val), relations, field)
+		} else if field.Type == models.FieldTypeGroup {
+			meta, _ := models.NewMetaGroup(field.Meta)
+
+			for _, item := range models.GroupItems(val) {
+				enrichDataFields(item, meta.Fields, medias, relations, sub)
+			}
+		}
+	}
+}
+
 // enrichEntityRelations takes care for enriching recursively entity relations.
-func (dao *EntityDAO) enrichEntityRelations(rels []models.Entity, settings *EntityEnrichSettings) []models.Entity {
+// The `expands` map holds the expand tree of each relation collection (nil means all fields).
+func (dao *EntityDAO) enrichEntityRelations(
+	rels []models.Entity,
+	settings *EntityEnrichSettings,
+	expands map[bson.ObjectId]EntityExpand,
+) []models.Entity {
 	collectionDAO := NewCollectionDAO(dao.Session)
 
 	collectionEntitiesMap := map[bson.ObjectId][]models.Entity{}
@@ -513,10 +1896,19 @@ func (dao *EntityDAO) enrichEntityRelations(rels []models.Entity, settings *Enti
 
 	collections, _ := collectionDAO.GetList(len(collectionIds), 0, bson.M{"_id": bson.M{"$in": collectionIds}}, nil)
 	for _, collection := range collections {
+		collectionSettings := *settings
+		if expands != nil {
+			// the relations without matching expand tree are not enriched further
+			collectionSettings.Expand = EntityExpand{}
+			if sub, ok := expands[collection.ID]; ok {
+				collectionSettings.Expand = sub
+			}
+		}
+
 		collectionEntitiesMap[collection.ID] = dao.EnrichEntities(
 			collectionEntitiesMap[collection.ID],
 			&collection,
-			settings,
+			&collectionSettings,
 		)
 	}
 
@@ -528,41 +1920,120 @@ func (dao *EntityDAO) enrichEntityRelations(rels []models.Entity, settings *Enti
 	return result
 }
 
-// extractMediaAndRelationIds extracts media and relation ids from entity data based on collection fields settings.
-func extractMediaAndRelationIds(entities []models.Entity, collection *models.Collection) (mediaIds []bson.ObjectId, relationIds []bson.ObjectId) {
+// relationExpands returns the merged expand trees of the relation fields
+// grouped by their related collection id.
+// Returns nil if all fields should be enriched.
+func relationExpands(fields []models.CollectionField, expand EntityExpand) map[bson.ObjectId]EntityExpand {
+	if expand == nil {
+		return nil
+	}
+
+	result := map[bson.ObjectId]EntityExpand{}
+
+	add := func(collectionID bson.ObjectId, sub EntityExpand) {
+		if existing, ok := result[collectionID]; ok {
+			result[collectionID] = existing.Merge(sub)
+		} else {
+			result[collectionID] = sub
+		}
+	}
+
+	for _, field := range fields {
+		sub, expanded := expand.Child(field.Key)
+		if !expanded {
+			continue
+		}
+
+		if field.Type == models.FieldTypeRelation {
+			meta, _ := models.NewMetaRelation(field.Meta)
+
+			add(meta.CollectionID, sub)
+		} else if field.Type == models.FieldTypeGroup {
+			meta, _ := models.NewMetaGroup(field.Meta)
+
+			for collectionID, groupSub := range relationExpands(meta.Fields, sub) {
+				add(collectionID, groupSub)
+			}
+		}
+	}
+
+	return result
+}
+
+// extractMediaAndRelationIds extracts the unique media and relation ids from entity data
+// based on collection fields settings (only for the fields from the `expand` tree).
+func extractMediaAndRelationIds(
+	entities []models.Entity,
+	collection *models.Collection,
+	expand EntityExpand,
+) (mediaIds []bson.ObjectId, relationIds []bson.ObjectId) {
 	var mIds []interface{}
 	var rIds []interface{}
 
 	// extract media and relation ids
 	for _, item := range entities {
 		for _, dataItem := range item.Data {
-		DATA_LOOP:
-			for key, val := range dataItem {
-				for _, field := range collection.Fields {
-					if field.Key != key {
-						continue
-					}
+			extractDataIds(dataItem, collection.Fields, &mIds, &rIds, expand)
+		}
+	}
 
-					if field.Type == models.FieldTypeMedia { // media
-						if ids, isSlice := val.([]interface{}); isSlice == true {
-							mIds = append(mIds, ids...)
-						}
-					} else if field.Type == models.FieldTypeRelation { // relation
-						if ids, isSlice := val.([]interface{}); isSlice == true {
-							rIds = append(rIds, ids...)
-						}
-					}
+	mediaIds = uniqueObjectIds(utils.InterfaceToObjectIds(mIds))
+	relationIds = uniqueObjectIds(utils.InterfaceToObjectIds(rIds))
 
-					continue DATA_LOOP
-				}
-			}
+	return mediaIds, relationIds
+}
+
+// uniqueObjectIds returns a new slice without the duplicated ids.
+func uniqueObjectIds(ids []bson.ObjectId) []bson.ObjectId {
+	result := []bson.ObjectId{}
+	existing := map[bson.ObjectId]bool{}
+
+	for _, id := range ids {
+		if !existing[id] {
+			existing[id] = true
+			result = append(result, id)
 		}
 	}
 
-	mediaIds = utils.InterfaceToObjectIds(mIds)
-	relationIds = utils.InterfaceToObjectIds(rIds)
+	return result
+}
+
+// extractDataIds extracts recursively the media and relation ids of a single data item
+// (only for the fields from the `expand` tree).
+func extractDataIds(
+	data map[string]interface{},
+	fields []models.CollectionField,
+	mIds *[]interface{},
+	rIds *[]interface{},
+	expand EntityExpand,
+) {
+	for _, field := range fields {
+		sub, expanded := expand.Child(field.Key)
+		if !expanded {
+			continue
+		}
 
-	return mediaIds, relationIds
+		val, ok := data[field.Key]
+		if !ok {
+			continue
+		}
+
+		if field.Type == models.FieldTypeMedia { // media
+			if ids, isSlice := val.([]interface{}); isSlice == true {
+				*mIds = append(*mIds, ids...)
+			}
+		} else if field.Type == models.FieldTypeRelation { // relation
+			if ids, isSlice := val.([]interface{}); isSlice == true {
+				*rIds = append(*rIds, ids...)
+			}
+		} else if field.Type == models.FieldTypeGroup { // nested fields
+			meta, _ := models.NewMetaGroup(field.Meta)
+
+			for _, item := range models.GroupItems(val) {
+				extractDataIds(item, meta.Fields, mIds, rIds, sub)
+			}
+		}
+	}
 }
 
 // extractEntityMedias extracts entity media items from list based on their ids and on collection field settings.
@@ -0,0 +1,225 @@
+package daos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Bulk executes the entity bulk operations of the provided form over the collection entities
+// and stores the outcome of each operation in `result` (the operations that already
+// have a failed result, eg. because of insufficient access rights, are skipped).
+//
+// If `form.Atomic` is set, the whole batch is validated before writing anything
+// and nothing is written in case of a single invalid operation (including operations
+// that conflict with each other, see `checkBulkConflicts()`). An operation could still fail
+// during the execution (eg. because of a concurrent change), so check `result.HasDone()`
+// to find whether the batch was partially executed.
+// Optionally you could specify additional conditions to limit the updatable/deletable entities.
+func (dao *EntityDAO) Bulk(
+	collection *models.Collection,
+	form *models.EntityBulkForm,
+	result *models.EntityBulkResult,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) {
+	if form.Atomic {
+		targets := map[string]int{}
+		uniques := map[string]int{}
+
+		for i, op := range form.Operations {
+			if result.Items[i].Status == models.EntityBulkItemFailed {
+				continue
+			}
+
+			model, err := dao.validateBulkOperation(collection, op, author, additionalConditions...)
+			if err == nil {
+				err = checkBulkConflicts(collection, i, op, model, targets, uniques)
+			}
+
+			if err != nil {
+				result.SetError(i, err)
+			}
+		}
+
+		if result.HasErrors() {
+			return
+		}
+	}
+
+	// the operations are resolved right before their execution
+	// so that each one works with the latest entity state
+	for i, op := range form.Operations {
+		if result.Items[i].Status == models.EntityBulkItemFailed {
+			continue
+		}
+
+		model, err := dao.executeBulkOperation(collection, op, author, additionalConditions...)
+		if err != nil {
+			result.SetError(i, err)
+
+			continue
+		}
+
+		result.SetDone(i, model)
+	}
+}
+
+// resolveBulkOperation validates the provided bulk operation fields and returns
+// its related entity model (nil on create) and entity form (nil on delete and status change).
+func (dao *EntityDAO) resolveBulkOperation(
+	collection *models.Collection,
+	op models.EntityBulkOperation,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) (*models.Entity, *models.EntityForm, error) {
+	if err := op.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var entity *models.Entity
+
+	if op.Action != models.EntityBulkActionCreate {
+		conditions := bson.M{"collection_id": collection.ID}
+		if len(additionalConditions) > 0 {
+			for k, v := range additionalConditions[0] {
+				conditions[k] = v
+			}
+		}
+
+		var fetchErr error
+
+		entity, fetchErr = dao.GetByID(op.ID, conditions)
+		if fetchErr != nil {
+			return nil, nil, errors.New("Entity item doesn't exist.")
+		}
+	}
+
+	if op.Action != models.EntityBulkActionCreate && op.Action != models.EntityBulkActionUpdate {
+		return entity, nil, nil
+	}
+
+	form := &models.EntityForm{
+		Model:        entity,
+		CollectionID: collection.ID,
+		Status:       op.Status,
+		Data:         op.Data,
+		PublishAt:    op.PublishAt,
+		UnpublishAt:  op.UnpublishAt,
+		Publish:      op.Publish,
+		Author:       author,
+	}
+
+	return entity, form, nil
+}
+
+// validateBulkOperation checks whether the provided bulk operation could be executed
+// (the entity data is validated the same way as a regular create/update).
+// Returns the prepared entity model of the create and update operations (nil for the others).
+func (dao *EntityDAO) validateBulkOperation(
+	collection *models.Collection,
+	op models.EntityBulkOperation,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) (*models.Entity, error) {
+	entity, form, err := dao.resolveBulkOperation(collection, op, author, additionalConditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Action {
+	case models.EntityBulkActionCreate:
+		return dao.prepareCreate(form)
+	case models.EntityBulkActionUpdate:
+		return dao.prepareUpdate(form)
+	case models.EntityBulkActionDelete:
+		refs, refsErr := dao.findReferenceFields(models.FieldTypeRelation, entity.CollectionID)
+		if refsErr != nil {
+			return nil, refsErr
+		}
+
+		return nil, dao.checkRestrictedReferences(refs, entity.ID)
+	}
+
+	return nil, nil
+}
+
+// checkBulkConflicts returns an error if the provided (already validated) bulk operation
+// targets an entity that is changed by another operation of the same batch or stores a unique
+// field value that is used by another operation of the batch (both would fail halfway through the execution).
+// The `targets` (entity id => operation index) and `uniques` (unique value => operation index)
+// maps are updated with the operation entity id and unique field values.
+func checkBulkConflicts(
+	collection *models.Collection,
+	index int,
+	op models.EntityBulkOperation,
+	model *models.Entity,
+	targets map[string]int,
+	uniques map[string]int,
+) error {
+	if op.ID != "" {
+		if j, ok := targets[op.ID]; ok {
+			return fmt.Errorf("The entity item is already changed by the operation at index %d.", j)
+		}
+
+		targets[op.ID] = index
+	}
+
+	if model == nil {
+		return nil
+	}
+
+	for _, field := range collection.Fields {
+		// the slugs are made unique on save
+		if !field.Unique || field.Type == models.FieldTypeSlug {
+			continue
+		}
+
+		for _, data := range []map[string]map[string]interface{}{model.Data, model.Draft} {
+			for group, values := range data {
+				value, ok := values[field.Key]
+				if !ok || field.IsEmptyValue(value) {
+					continue
+				}
+
+				key := group + "." + field.Key + ":" + fmt.Sprint(value)
+
+				if j, ok := uniques[key]; ok && j != index {
+					return fmt.Errorf("The %s field value is already used by the operation at index %d.", field.Key, j)
+				}
+
+				uniques[key] = index
+			}
+		}
+	}
+
+	return nil
+}
+
+// executeBulkOperation executes the provided bulk operation
+// and returns the created, updated or deleted entity model.
+func (dao *EntityDAO) executeBulkOperation(
+	collection *models.Collection,
+	op models.EntityBulkOperation,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) (*models.Entity, error) {
+	entity, form, err := dao.resolveBulkOperation(collection, op, author, additionalConditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Action {
+	case models.EntityBulkActionCreate:
+		return dao.Create(form)
+	case models.EntityBulkActionUpdate:
+		return dao.Update(form)
+	case models.EntityBulkActionDelete:
+		return entity, dao.Delete(entity, author)
+	default:
+		return dao.UpdateStatus(entity, op.Status, author)
+	}
+}
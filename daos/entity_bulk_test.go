@@ -0,0 +1,235 @@
+package daos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestEntityDAO_Bulk(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+	collection, _ := NewCollectionDAO(TestSession).GetByID("5a833090e1382351eaad3732")
+
+	form := &models.EntityBulkForm{
+		Operations: []models.EntityBulkOperation{
+			{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: testBulkData("new")},
+			{Action: models.EntityBulkActionUpdate, ID: "5a8bea7ee1382310bec8076c", Status: models.EntityStatusActive, Data: testBulkData("")},
+			{Action: models.EntityBulkActionStatus, ID: "5a8bea3ae1382310bec8076b", Status: models.EntityStatusInactive},
+			{Action: models.EntityBulkActionDelete, ID: "5a8beaa2e1382310bec8076d"}, // from another collection
+			{Action: models.EntityBulkActionDelete, ID: "5a8bea7ee1382310bec8076c"},
+			{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: testBulkData("forbidden")},
+		},
+	}
+
+	result := models.NewEntityBulkResult(form)
+
+	// eg. insufficient access rights
+	result.SetError(5, errors.New("forbidden"))
+
+	dao.Bulk(collection, form, result, models.RevisionAuthor{})
+
+	expectedStatuses := []string{
+		models.EntityBulkItemDone,
+		models.EntityBulkItemFailed,
+		models.EntityBulkItemDone,
+		models.EntityBulkItemFailed,
+		models.EntityBulkItemDone,
+		models.EntityBulkItemFailed,
+	}
+
+	for i, item := range result.Items {
+		if item.Status != expectedStatuses[i] {
+			t.Errorf("Expected %s status, got %s (item %d: %v)", expectedStatuses[i], item.Status, i, item)
+		}
+	}
+
+	if _, err := dao.GetByID(result.Items[0].ID.Hex()); err != nil {
+		t.Error("Expected the entity to be created, got error", err)
+	}
+
+	if entity, _ := dao.GetByID("5a8bea3ae1382310bec8076b"); entity == nil || entity.Status != models.EntityStatusInactive {
+		t.Error("Expected the entity status to be changed, got", entity)
+	}
+
+	if _, err := dao.GetByID("5a8bea7ee1382310bec8076c"); err == nil {
+		t.Error("Expected the entity to be deleted")
+	}
+
+	if _, err := dao.GetByID("5a8beaa2e1382310bec8076d"); err != nil {
+		t.Error("Expected the other collection entity to be kept, got error", err)
+	}
+
+	if total, _ := dao.Count(bson.M{"collection_id": collection.ID}); total != 2 {
+		t.Errorf("Expected 2 collection entities, got %d", total)
+	}
+}
+
+func TestEntityDAO_Bulk_Atomic(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	runMigrations(t)
+
+	dao := NewEntityDAO(TestSession)
+	collection, _ := NewCollectionDAO(TestSession).GetByID("5a833090e1382351eaad3732")
+
+	setFieldOnDelete(t, "5a8b32d4e13823769a18bc1c", "rels", models.MetaOnDeleteRestrict)
+
+	// invalid batch
+	invalidForm := &models.EntityBulkForm{
+		Atomic: true,
+		Operations: []models.EntityBulkOperation{
+			{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: testBulkData("new")},
+			{Action: models.EntityBulkActionStatus, ID: "5a8bea7ee1382310bec8076c", Status: models.EntityStatusInactive},
+			{Action: models.EntityBulkActionDelete, ID: "5a8bea3ae1382310bec8076b"}, // restricted
+		},
+	}
+
+	invalidResult := models.NewEntityBulkResult(invalidForm)
+
+	dao.Bulk(collection, invalidForm, invalidResult, models.RevisionAuthor{})
+
+	expectedStatuses := []string{models.EntityBulkItemSkipped, models.EntityBulkItemSkipped, models.EntityBulkItemFailed}
+	for i, item := range invalidResult.Items {
+		if item.Status != expectedStatuses[i] {
+			t.Errorf("Expected %s status, got %s (item %d: %v)", expectedStatuses[i], item.Status, i, item)
+		}
+	}
+
+	if total, _ := dao.Count(bson.M{"collection_id": collection.ID}); total != 2 {
+		t.Errorf("Expected 2 collection entities, got %d", total)
+	}
+
+	if entity, _ := dao.GetByID("5a8bea7ee1382310bec8076c"); entity == nil || entity.Status != models.EntityStatusActive {
+		t.Error("Expected the entity status to be unchanged, got", entity)
+	}
+
+	// valid batch
+	validForm := &models.EntityBulkForm{
+		Atomic: true,
+		Operations: []models.EntityBulkOperation{
+			{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: testBulkData("new")},
+			{Action: models.EntityBulkActionStatus, ID: "5a8bea7ee1382310bec8076c", Status: models.EntityStatusInactive},
+		},
+	}
+
+	validResult := models.NewEntityBulkResult(validForm)
+
+	dao.Bulk(collection, validForm, validResult, models.RevisionAuthor{})
+
+	if validResult.HasErrors() {
+		t.Error("Expected the valid batch to not have errors, got", validResult.Items)
+	}
+
+	if total, _ := dao.Count(bson.M{"collection_id": collection.ID}); total != 3 {
+		t.Errorf("Expected 3 collection entities, got %d", total)
+	}
+
+	if entity, _ := dao.GetByID("5a8bea7ee1382310bec8076c"); entity == nil || entity.Status != models.EntityStatusInactive {
+		t.Error("Expected the entity status to be changed, got", entity)
+	}
+}
+
+func TestEntityDAO_Bulk_AtomicConflicts(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	uniqueCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "unique_test",
+		Fields: []models.CollectionField{
+			{Key: "title", Type: models.FieldTypePlain, Multilingual: true},
+			{Key: "code", Type: models.FieldTypePlain, Unique: true},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(uniqueCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	existing := &models.Entity{
+		ID:           bson.NewObjectId(),
+		CollectionID: uniqueCollection.ID,
+		Status:       models.EntityStatusActive,
+		Data: map[string]map[string]interface{}{
+			models.EntitySharedDataKey: map[string]interface{}{"code": "existing"},
+		},
+	}
+
+	if err := TestSession.DB("").C(dao.Collection).Insert(existing); err != nil {
+		t.Fatal("Failed to insert test entity", err)
+	}
+
+	codeData := func(code string) map[string]map[string]interface{} {
+		return map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "test", "code": code},
+		}
+	}
+
+	testScenarios := []struct {
+		Operations       []models.EntityBulkOperation
+		ExpectedStatuses []string
+	}{
+		// duplicated unique value
+		{
+			[]models.EntityBulkOperation{
+				{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: codeData("abc")},
+				{Action: models.EntityBulkActionCreate, Status: models.EntityStatusActive, Data: codeData("abc")},
+			},
+			[]string{models.EntityBulkItemSkipped, models.EntityBulkItemFailed},
+		},
+		// entity changed by more than one operation
+		{
+			[]models.EntityBulkOperation{
+				{Action: models.EntityBulkActionDelete, ID: existing.ID.Hex()},
+				{Action: models.EntityBulkActionUpdate, ID: existing.ID.Hex(), Status: models.EntityStatusActive, Data: codeData("existing")},
+			},
+			[]string{models.EntityBulkItemSkipped, models.EntityBulkItemFailed},
+		},
+	}
+
+	for i, scenario := range testScenarios {
+		form := &models.EntityBulkForm{Atomic: true, Operations: scenario.Operations}
+
+		result := models.NewEntityBulkResult(form)
+
+		dao.Bulk(uniqueCollection, form, result, models.RevisionAuthor{})
+
+		for j, item := range result.Items {
+			if item.Status != scenario.ExpectedStatuses[j] {
+				t.Errorf("(%d) Expected %s status, got %s (item %d: %v)", i, scenario.ExpectedStatuses[j], item.Status, j, item)
+			}
+		}
+
+		if result.HasDone() {
+			t.Errorf("(%d) Expected nothing to be executed, got %v", i, result.Items)
+		}
+
+		if total, _ := dao.Count(bson.M{"collection_id": uniqueCollection.ID}); total != 1 {
+			t.Errorf("(%d) Expected 1 collection entity, got %d", i, total)
+		}
+	}
+}
+
+// testBulkData returns new "col1" entity data with the provided title for all locales.
+func testBulkData(title string) map[string]map[string]interface{} {
+	data := map[string]map[string]interface{}{}
+
+	for _, locale := range []string{"en", "bg", "de"} {
+		data[locale] = map[string]interface{}{
+			"title":       title,
+			"description": "test",
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,366 @@
+package daos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// referenceField defines a collection media/relation field (including the nested group ones)
+// that could store references to other items.
+type referenceField struct {
+	CollectionID bson.ObjectId
+	Field        models.CollectionField
+	Path         []string // the field key path within a data locale group (eg. ["faq", "rels"])
+	Multilingual bool     // whether the top level field value is stored per locale
+}
+
+// OnDelete returns the reference field meta on delete policy (nullify by default).
+func (r referenceField) OnDelete() string {
+	var policy string
+
+	if r.Field.Type == models.FieldTypeMedia {
+		meta, _ := models.NewMetaMedia(r.Field.Meta)
+		policy = meta.OnDelete
+	} else {
+		meta, _ := models.NewMetaRelation(r.Field.Meta)
+		policy = meta.OnDelete
+	}
+
+	if policy == "" {
+		return models.MetaOnDeleteNullify
+	}
+
+	return policy
+}
+
+// -------------------------------------------------------------------
+// • Query methods
+// -------------------------------------------------------------------
+
+// GetEntityReferences returns all entities with relation fields that point to the provided entity.
+// Set `withDrafts` to match also the references stored only in the entity drafts.
+// Optionally you could specify additional conditions to filter the result (eg. by status).
+func (dao *EntityDAO) GetEntityReferences(entity *models.Entity, withDrafts bool, additionalConditions ...bson.M) ([]models.Entity, error) {
+	refs, err := dao.findReferenceFields(models.FieldTypeRelation, entity.CollectionID)
+	if err != nil {
+		return []models.Entity{}, err
+	}
+
+	return dao.getReferences(refs, entity.ID, withDrafts, additionalConditions...)
+}
+
+// GetMediaReferences returns all entities with media fields that point to the provided media item.
+// Set `withDrafts` to match also the references stored only in the entity drafts.
+// Optionally you could specify additional conditions to filter the result (eg. by status).
+func (dao *EntityDAO) GetMediaReferences(media *models.Media, withDrafts bool, additionalConditions ...bson.M) ([]models.Entity, error) {
+	refs, err := dao.findReferenceFields(models.FieldTypeMedia, "")
+	if err != nil {
+		return []models.Entity{}, err
+	}
+
+	return dao.getReferences(refs, media.ID, withDrafts, additionalConditions...)
+}
+
+// getReferences returns all entities that store `id` in any of the provided reference fields.
+func (dao *EntityDAO) getReferences(refs []referenceField, id bson.ObjectId, withDrafts bool, additionalConditions ...bson.M) ([]models.Entity, error) {
+	conditions, err := dao.referenceConditions(refs, id, withDrafts)
+	if err != nil || conditions == nil {
+		return []models.Entity{}, err
+	}
+
+	for _, condition := range additionalConditions {
+		utils.AppendAndCondition(conditions, condition)
+	}
+
+	return dao.GetList(0, 0, conditions, []string{"+created"})
+}
+
+// findReferenceFields returns the media or relation (`fieldType`) fields of all collections.
+// For relation fields you could specify `collectionID` to return only the fields
+// related to that collection.
+func (dao *EntityDAO) findReferenceFields(fieldType string, collectionID bson.ObjectId) ([]referenceField, error) {
+	result := []referenceField{}
+
+	collections, err := NewCollectionDAO(dao.Session).GetList(0, 0, nil, nil)
+	if err != nil {
+		return result, err
+	}
+
+	var walk func(collection models.Collection, fields []models.CollectionField, path []string, multilingual bool)
+	walk = func(collection models.Collection, fields []models.CollectionField, path []string, multilingual bool) {
+		for _, field := range fields {
+			fieldPath := append(append([]string{}, path...), field.Key)
+
+			// the multilingual flag of the nested group fields is inherited from the top level field
+			fieldMultilingual := multilingual
+			if len(path) == 0 {
+				fieldMultilingual = field.Multilingual
+			}
+
+			if field.Type == models.FieldTypeGroup {
+				meta, _ := models.NewMetaGroup(field.Meta)
+
+				walk(collection, meta.Fields, fieldPath, fieldMultilingual)
+
+				continue
+			}
+
+			if field.Type != fieldType {
+				continue
+			}
+
+			if fieldType == models.FieldTypeRelation && collectionID != "" {
+				meta, _ := models.NewMetaRelation(field.Meta)
+				if meta.CollectionID != collectionID {
+					continue
+				}
+			}
+
+			result = append(result, referenceField{
+				CollectionID: collection.ID,
+				Field:        field,
+				Path:         fieldPath,
+				Multilingual: fieldMultilingual,
+			})
+		}
+	}
+
+	for _, collection := range collections {
+		walk(collection, collection.Fields, nil, false)
+	}
+
+	return result, nil
+}
+
+// referenceConditions builds the find conditions that match the entities storing `id`
+// in their data (and draft, if `withDrafts` is set) value of any of the provided reference fields.
+// Returns nil if there are no reference fields.
+func (dao *EntityDAO) referenceConditions(refs []referenceField, id bson.ObjectId, withDrafts bool) (bson.M, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	languages, err := NewLanguageDAO(dao.Session).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// the ids could be stored also as plain hex strings (eg. imported data)
+	idCondition := bson.M{"$in": []interface{}{id, id.Hex()}}
+
+	props := []string{"data"}
+	if withDrafts {
+		props = append(props, "draft")
+	}
+
+	or := []bson.M{}
+
+	for _, ref := range refs {
+		groups := []string{models.EntitySharedDataKey}
+		if ref.Multilingual {
+			groups = []string{}
+			for _, lang := range languages {
+				groups = append(groups, lang.Locale)
+			}
+		}
+
+		for _, prop := range props {
+			for _, group := range groups {
+				or = append(or, bson.M{
+					"collection_id": ref.CollectionID,
+					prop + "." + group + "." + strings.Join(ref.Path, "."): idCondition,
+				})
+			}
+		}
+	}
+
+	return bson.M{"$or": or}, nil
+}
+
+// -------------------------------------------------------------------
+// • On delete policies
+// -------------------------------------------------------------------
+
+// checkRestrictedReferences returns an error if `id` is stored
+// in any of the provided reference fields with restrict on delete policy.
+func (dao *EntityDAO) checkRestrictedReferences(refs []referenceField, id bson.ObjectId) error {
+	restricted := []referenceField{}
+	for _, ref := range refs {
+		if ref.OnDelete() == models.MetaOnDeleteRestrict {
+			restricted = append(restricted, ref)
+		}
+	}
+
+	conditions, err := dao.referenceConditions(restricted, id, true)
+	if err != nil || conditions == nil {
+		return err
+	}
+
+	total, err := dao.Count(conditions)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		return fmt.Errorf("The item is referenced by %d entity item(s) and can't be deleted.", total)
+	}
+
+	return nil
+}
+
+// releaseReferences applies the nullify and cascade on delete policies
+// of the provided reference fields to all entities that store `id`.
+// The changed (and deleted) referencing entities get a new revision and their
+// update (delete) hooks are queued for delivery by the hook delivery worker.
+// NB! The referenced item should be already deleted to prevent circular cascade deletes.
+func (dao *EntityDAO) releaseReferences(refs []referenceField, id bson.ObjectId, author models.RevisionAuthor) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	// group the reference fields by their policy
+	policyRefs := map[string][]referenceField{}
+	for _, ref := range refs {
+		policy := ref.OnDelete()
+		policyRefs[policy] = append(policyRefs[policy], ref)
+	}
+
+	// remove the id from the referencing entities data
+	nullifyItems, nullifyErr := dao.getReferences(policyRefs[models.MetaOnDeleteNullify], id, true)
+	if nullifyErr != nil {
+		return nullifyErr
+	}
+
+	for i := range nullifyItems {
+		item := &nullifyItems[i]
+
+		for _, ref := range policyRefs[models.MetaOnDeleteNullify] {
+			if ref.CollectionID != item.CollectionID {
+				continue
+			}
+
+			for _, data := range []map[string]map[string]interface{}{item.Data, item.Draft} {
+				for _, group := range data {
+					removeDataID(group, ref.Path, id)
+				}
+			}
+		}
+
+		item.Modified = time.Now().Unix()
+
+		if err := dao.refreshSearchText(item); err != nil {
+			return err
+		}
+
+		updateErr := session.DB("").C(dao.Collection).UpdateId(item.ID, bson.M{"$set": bson.M{
+			"data":        item.Data,
+			"draft":       item.Draft,
+			"search_text": item.SearchText,
+			"modified":    item.Modified,
+		}})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		if _, err := NewEntityRevisionDAO(session).Create(item, models.EntityRevisionActionUpdate, author); err != nil {
+			return err
+		}
+
+		if err := dao.queueHook(item, utils.HookActionUpdate); err != nil {
+			return err
+		}
+	}
+
+	// delete the referencing entities (their own references are released recursively)
+	cascadeItems, cascadeErr := dao.getReferences(policyRefs[models.MetaOnDeleteCascade], id, true)
+	if cascadeErr != nil {
+		return cascadeErr
+	}
+
+	for i := range cascadeItems {
+		// the item could be already deleted by another cascade delete
+		err := dao.Delete(&cascadeItems[i], author)
+		if err == mgo.ErrNotFound {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := dao.queueHook(&cascadeItems[i], utils.HookActionDelete); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueHook creates a pending update or delete (`action`) hook delivery
+// for an entity changed as a side effect of another item deletion.
+// Does nothing if the entity collection doesn't have such hook.
+func (dao *EntityDAO) queueHook(entity *models.Entity, action string) error {
+	collection, err := dao.GetEntityCollection(entity)
+	if err != nil {
+		return err
+	}
+
+	url := collection.UpdateHook
+	if action == utils.HookActionDelete {
+		url = collection.DeleteHook
+	}
+
+	if url == "" {
+		return nil
+	}
+
+	entity.ExpandSharedData()
+
+	_, err = NewHookDeliveryDAO(dao.Session).Create(collection, url, utils.HookTypeEntity, action, entity)
+
+	return err
+}
+
+// removeDataID removes `id` from the reference field value located at `path`
+// of a single data locale group (the nested group items are traversed too).
+func removeDataID(data map[string]interface{}, path []string, id bson.ObjectId) {
+	if len(path) == 0 || data == nil {
+		return
+	}
+
+	value, ok := data[path[0]]
+	if !ok {
+		return
+	}
+
+	if len(path) > 1 {
+		for _, item := range models.GroupItems(value) {
+			removeDataID(item, path[1:], id)
+		}
+
+		return
+	}
+
+	ids := []bson.ObjectId{}
+	switch v := value.(type) {
+	case []bson.ObjectId:
+		ids = v
+	case []interface{}:
+		ids = utils.InterfaceToObjectIds(v)
+	}
+
+	result := []bson.ObjectId{}
+	for _, itemID := range ids {
+		if itemID != id {
+			result = append(result, itemID)
+		}
+	}
+
+	data[path[0]] = result
+}
@@ -0,0 +1,266 @@
+package daos
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestEntityDAO_GetEntityReferences(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	runMigrations(t)
+
+	dao := NewEntityDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		Conditions  []bson.M
+		ExpectedIds []string
+	}{
+		{"5a8bea7ee1382310bec8076c", nil, []string{}},
+		{"5a8bea3ae1382310bec8076b", nil, []string{"5a8beaa2e1382310bec8076d"}},
+		{"5a8bea3ae1382310bec8076b", []bson.M{{"status": models.EntityStatusInactive}}, []string{}},
+	}
+
+	for _, scenario := range testScenarios {
+		entity, _ := dao.GetByID(scenario.ID)
+
+		items, err := dao.GetEntityReferences(entity, true, scenario.Conditions...)
+		if err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+			continue
+		}
+
+		assertEntityIds(t, items, scenario.ExpectedIds)
+	}
+}
+
+func TestEntityDAO_GetMediaReferences(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	runMigrations(t)
+
+	dao := NewEntityDAO(TestSession)
+	mediaDAO := NewMediaDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		ExpectedIds []string
+	}{
+		{"5a7cb889e1382325ece3a108", []string{"5a8beaa2e1382310bec8076d"}},
+		{"5a7db16de138233f19f7d815", []string{"5a8beac6e1382310bec8076f"}},
+	}
+
+	for _, scenario := range testScenarios {
+		media, _ := mediaDAO.GetByID(scenario.ID)
+
+		items, err := dao.GetMediaReferences(media, true)
+		if err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+			continue
+		}
+
+		assertEntityIds(t, items, scenario.ExpectedIds)
+	}
+}
+
+func TestEntityDAO_Delete_OnDelete(t *testing.T) {
+	referenced := "5a8bea3ae1382310bec8076b"
+	referencing := "5a8beaa2e1382310bec8076d"
+
+	testScenarios := []struct {
+		Policy              string
+		ExpectError         bool
+		ExpectReferencing   bool
+		ExpectedRelationIds []bson.ObjectId
+	}{
+		{"", false, true, []bson.ObjectId{}},
+		{models.MetaOnDeleteNullify, false, true, []bson.ObjectId{}},
+		{models.MetaOnDeleteRestrict, true, true, []bson.ObjectId{bson.ObjectIdHex(referenced)}},
+		{models.MetaOnDeleteCascade, false, false, nil},
+	}
+
+	for _, scenario := range testScenarios {
+		fixtures.InitFixtures(TestSession)
+
+		runMigrations(t)
+
+		dao := NewEntityDAO(TestSession)
+
+		setFieldOnDelete(t, "5a8b32d4e13823769a18bc1c", "rels", scenario.Policy)
+
+		entity, _ := dao.GetByID(referenced)
+
+		err := dao.Delete(entity)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if _, getErr := dao.GetByID(referenced); (getErr == nil) != scenario.ExpectError {
+			t.Errorf("Expected the referenced entity to be deleted only on success (scenario %v)", scenario)
+		}
+
+		item, getErr := dao.GetByID(referencing)
+		if (getErr == nil) != scenario.ExpectReferencing {
+			t.Errorf("Expected the referencing entity existence to be %v, got %v (scenario %v)", scenario.ExpectReferencing, getErr == nil, scenario)
+		}
+
+		if getErr == nil {
+			ids := utils.InterfaceToObjectIds(item.Data[models.EntitySharedDataKey]["rels"])
+			if !reflect.DeepEqual(ids, scenario.ExpectedRelationIds) {
+				t.Errorf("Expected %v relation ids, got %v (scenario %v)", scenario.ExpectedRelationIds, ids, scenario)
+			}
+		}
+
+		fixtures.CleanFixtures(TestSession)
+	}
+}
+
+func TestEntityDAO_Delete_OnDelete_History(t *testing.T) {
+	referenced := "5a8bea3ae1382310bec8076b"
+	referencing := bson.ObjectIdHex("5a8beaa2e1382310bec8076d")
+
+	testScenarios := []struct {
+		Policy         string
+		ExpectedAction string
+	}{
+		{models.MetaOnDeleteNullify, models.EntityRevisionActionUpdate},
+		{models.MetaOnDeleteCascade, models.EntityRevisionActionDelete},
+	}
+
+	for _, scenario := range testScenarios {
+		fixtures.InitFixtures(TestSession)
+
+		runMigrations(t)
+
+		dao := NewEntityDAO(TestSession)
+
+		setFieldOnDelete(t, "5a8b32d4e13823769a18bc1c", "rels", scenario.Policy)
+
+		hooksErr := TestSession.DB("").C("collection").UpdateId(bson.ObjectIdHex("5a8b32d4e13823769a18bc1c"), bson.M{"$set": bson.M{
+			"update_hook": "http://localhost:1/update",
+			"delete_hook": "http://localhost:1/delete",
+		}})
+		if hooksErr != nil {
+			t.Fatal("Failed to update the collection hooks", hooksErr)
+		}
+
+		entity, _ := dao.GetByID(referenced)
+
+		if err := dao.Delete(entity); err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		revisions, _ := TestSession.DB("").C("entity_revision").Find(bson.M{
+			"entity_id": referencing,
+			"action":    scenario.ExpectedAction,
+		}).Count()
+		if revisions != 1 {
+			t.Errorf("Expected 1 %s revision of the referencing entity, got %d", scenario.ExpectedAction, revisions)
+		}
+
+		deliveries, _ := TestSession.DB("").C("hook_delivery").Find(bson.M{
+			"url":    "http://localhost:1/" + scenario.ExpectedAction,
+			"action": scenario.ExpectedAction,
+		}).Count()
+		if deliveries != 1 {
+			t.Errorf("Expected 1 queued %s hook delivery, got %d", scenario.ExpectedAction, deliveries)
+		}
+
+		if scenario.Policy == models.MetaOnDeleteNullify {
+			item, _ := dao.GetByID(referencing.Hex())
+			if item.Modified <= 1519122756 {
+				t.Errorf("Expected the referencing entity modified time to be updated, got %d", item.Modified)
+			}
+		}
+
+		fixtures.CleanFixtures(TestSession)
+	}
+}
+
+func TestMediaDAO_Delete_OnDelete(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	runMigrations(t)
+
+	dao := NewMediaDAO(TestSession)
+
+	setFieldOnDelete(t, "5a8b32d4e13823769a18bc1c", "files", models.MetaOnDeleteRestrict)
+
+	media, _ := dao.GetByID("5a7cb889e1382325ece3a108")
+
+	if err := dao.Delete(media); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if _, err := dao.GetByID("5a7cb889e1382325ece3a108"); err != nil {
+		t.Error("Expected the restricted media item to be kept, got error", err)
+	}
+}
+
+func TestRemoveDataID(t *testing.T) {
+	id := bson.ObjectIdHex("5a8bea3ae1382310bec8076b")
+	otherID := bson.ObjectIdHex("5a8bea7ee1382310bec8076c")
+
+	data := map[string]interface{}{
+		"rels": []interface{}{id, otherID.Hex()},
+		"faq": []interface{}{
+			bson.M{"question": "a", "rels": []interface{}{id}},
+			bson.M{"question": "b", "rels": []interface{}{otherID}},
+		},
+	}
+
+	removeDataID(data, []string{"rels"}, id)
+	removeDataID(data, []string{"faq", "rels"}, id)
+	removeDataID(data, []string{"missing"}, id)
+
+	expected := map[string]interface{}{
+		"rels": []bson.ObjectId{otherID},
+		"faq": []interface{}{
+			bson.M{"question": "a", "rels": []bson.ObjectId{}},
+			bson.M{"question": "b", "rels": []bson.ObjectId{otherID}},
+		},
+	}
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+// setFieldOnDelete updates the on delete meta policy of a single collection field.
+func setFieldOnDelete(t *testing.T, collectionID string, key string, policy string) {
+	err := TestSession.DB("").C("collection").Update(
+		bson.M{"_id": bson.ObjectIdHex(collectionID), "fields.key": key},
+		bson.M{"$set": bson.M{"fields.$.meta.on_delete": policy}},
+	)
+
+	if err != nil {
+		t.Fatal("Failed to update the collection field meta", err)
+	}
+}
+
+// assertEntityIds checks whether the provided entities have exactly the expected ids.
+func assertEntityIds(t *testing.T, items []models.Entity, expectedIds []string) {
+	if len(items) != len(expectedIds) {
+		t.Errorf("Expected %d items, got %d (%v)", len(expectedIds), len(items), items)
+		return
+	}
+
+	for i, item := range items {
+		if item.ID.Hex() != expectedIds[i] {
+			t.Errorf("Expected %s item, got %s", expectedIds[i], item.ID.Hex())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// EntityRevisionDAO gets and persists entity revision data in database.
+type EntityRevisionDAO struct {
+	Session    *mgo.Session
+	Collection string
+}
+
+// ensureIndexes makes sure that the required db indexes and constraints are set.
+func (dao *EntityRevisionDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	index := mgo.Index{
+		Key:        []string{"entity_id", "-created"},
+		Background: true,
+	}
+
+	if err := c.EnsureIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+// NewEntityRevisionDAO creates a new EntityRevisionDAO.
+func NewEntityRevisionDAO(session *mgo.Session) *EntityRevisionDAO {
+	dao := &EntityRevisionDAO{
+		Session:    session,
+		Collection: "entity_revision",
+	}
+
+	dao.ensureIndexes()
+
+	return dao
+}
+
+// -------------------------------------------------------------------
+// • Query methods
+// -------------------------------------------------------------------
+
+// Count returns the total number of EntityRevision models based on the provided conditions.
+func (dao *EntityRevisionDAO) Count(conditions bson.M) (int, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	result, err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		Count()
+
+	return result, err
+}
+
+// GetList returns list with EntityRevision models.
+func (dao *EntityRevisionDAO) GetList(limit int, offset int, conditions bson.M, sortData []string) ([]models.EntityRevision, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.EntityRevision{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		Sort(sortData...).
+		Skip(offset).
+		Limit(limit).
+		All(&items)
+
+	return items, err
+}
+
+// GetOne returns single EntityRevision model based on the provided conditions.
+func (dao *EntityRevisionDAO) GetOne(conditions bson.M) (*models.EntityRevision, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := &models.EntityRevision{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		One(model)
+
+	return model, err
+}
+
+// GetByID returns single EntityRevision model by its id.
+func (dao *EntityRevisionDAO) GetByID(id string, additionalConditions ...bson.M) (*models.EntityRevision, error) {
+	if !bson.IsObjectIdHex(id) {
+		err := errors.New("Invalid object id format")
+
+		return &models.EntityRevision{}, err
+	}
+
+	conditions := bson.M{}
+	if len(additionalConditions) > 0 && additionalConditions[0] != nil {
+		conditions = additionalConditions[0]
+	}
+	conditions["_id"] = bson.ObjectIdHex(id)
+
+	return dao.GetOne(conditions)
+}
+
+// -------------------------------------------------------------------
+// • DB persists methods
+// -------------------------------------------------------------------
+
+// Create inserts a new revision entry for the provided entity model state.
+func (dao *EntityRevisionDAO) Create(entity *models.Entity, action string, author models.RevisionAuthor) (*models.EntityRevision, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := models.NewEntityRevision(entity, action, author)
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).Insert(model)
+
+	return model, dbErr
+}
+
+// DeleteAll deletes all entity revisions by the provided conditions.
+func (dao *EntityRevisionDAO) DeleteAll(conditions bson.M) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).RemoveAll(conditions)
+
+	return err
+}
@@ -0,0 +1,201 @@
+package daos
+
+import (
+	"testing"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewEntityRevisionDAO(t *testing.T) {
+	dao := NewEntityRevisionDAO(TestSession)
+
+	if dao == nil {
+		t.Error("Expected EntityRevisionDAO pointer, got nil")
+	}
+
+	if dao.Collection != "entity_revision" {
+		t.Error("Expected entity_revision collection, got ", dao.Collection)
+	}
+}
+
+func TestEntityRevisionDAO_Count(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions bson.M
+		Expected   int
+	}{
+		{nil, 3},
+		{bson.M{"action": "missing"}, 0},
+		{bson.M{"entity_id": bson.ObjectIdHex("5a8bea3ae1382310bec8076b")}, 2},
+		{bson.M{"author.model": "key"}, 1},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.Count(scenario.Conditions)
+		if result != scenario.Expected {
+			t.Errorf("Expected %d, got %d (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestEntityRevisionDAO_GetList(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions    bson.M
+		Sort          []string
+		Limit         int
+		Offset        int
+		ExpectedCount int
+		ExpectedOrder []string
+	}{
+		{nil, nil, 10, 0, 3, nil},
+		{nil, nil, 10, 1, 2, nil},
+		{bson.M{"action": "missing"}, nil, 10, 0, 0, nil},
+		{bson.M{"entity_id": bson.ObjectIdHex("5a8bea3ae1382310bec8076b")}, []string{"created"}, 10, 0, 2, []string{"5b1e5a0be138235b3f1c7a01", "5b1e5a0be138235b3f1c7a02"}},
+		{bson.M{"entity_id": bson.ObjectIdHex("5a8bea3ae1382310bec8076b")}, []string{"-created"}, 10, 0, 2, []string{"5b1e5a0be138235b3f1c7a02", "5b1e5a0be138235b3f1c7a01"}},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.GetList(scenario.Limit, scenario.Offset, scenario.Conditions, scenario.Sort)
+		if len(result) != scenario.ExpectedCount {
+			t.Fatalf("Expected %d items, got %d (scenario %v)", scenario.ExpectedCount, len(result), scenario)
+		}
+
+		if scenario.ExpectedOrder != nil {
+			for i, id := range scenario.ExpectedOrder {
+				if result[i].ID.Hex() != id {
+					t.Fatalf("Invalid order - expected %s to be at position %d (scenario %v)", id, i, scenario)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestEntityRevisionDAO_GetOne(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions  bson.M
+		ExpectError bool
+		ExpectedID  string
+	}{
+		{bson.M{"action": "missing"}, true, ""},
+		{bson.M{"action": models.EntityRevisionActionUpdate}, false, "5b1e5a0be138235b3f1c7a02"},
+	}
+
+	for _, scenario := range testScenarios {
+		item, err := dao.GetOne(scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if item.ID.Hex() != scenario.ExpectedID {
+			t.Errorf("Expected revision item with %s id, got %s (scenario %v)", scenario.ExpectedID, item.ID.Hex(), scenario)
+		}
+	}
+}
+
+func TestEntityRevisionDAO_GetByID(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		Conditions  bson.M
+		ExpectError bool
+		ExpectedID  string
+	}{
+		{"invalid", nil, true, ""},
+		{"5a8bea3ae1382310bec8076b", nil, true, ""},
+		{"5b1e5a0be138235b3f1c7a01", bson.M{"action": "invalid"}, true, ""},
+		{"5b1e5a0be138235b3f1c7a01", nil, false, "5b1e5a0be138235b3f1c7a01"},
+		{"5b1e5a0be138235b3f1c7a03", bson.M{"entity_id": bson.ObjectIdHex("5a8beaa2e1382310bec8076d")}, false, "5b1e5a0be138235b3f1c7a03"},
+	}
+
+	for _, scenario := range testScenarios {
+		item, err := dao.GetByID(scenario.ID, scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if item.ID.Hex() != scenario.ExpectedID {
+			t.Errorf("Expected revision item with %s id, got %s (scenario %v)", scenario.ExpectedID, item.ID.Hex(), scenario)
+		}
+	}
+}
+
+func TestEntityRevisionDAO_Create(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	entity := &models.Entity{
+		ID:           bson.ObjectIdHex("5a8bea7ee1382310bec8076c"),
+		CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
+		Status:       models.EntityStatusActive,
+		Data:         map[string]map[string]interface{}{"en": map[string]interface{}{"title": "test"}},
+	}
+
+	author := models.RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
+
+	revision, err := dao.Create(entity, models.EntityRevisionActionUpdate, author)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, fetchErr := dao.GetByID(revision.ID.Hex())
+	if fetchErr != nil {
+		t.Fatal("Expected the revision to be stored, got error", fetchErr)
+	}
+
+	if stored.EntityID != entity.ID || stored.Action != models.EntityRevisionActionUpdate || stored.Author != author {
+		t.Error("The stored revision doesn't match the created one", stored)
+	}
+}
+
+func TestEntityRevisionDAO_DeleteAll(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityRevisionDAO(TestSession)
+
+	collectionID := "5a833090e1382351eaad3732"
+
+	dao.DeleteAll(bson.M{"collection_id": bson.ObjectIdHex(collectionID)})
+
+	items, _ := dao.GetList(100, 0, nil, nil)
+
+	if len(items) != 1 {
+		t.Error("Expected 1 item, got", len(items))
+	}
+
+	for _, item := range items {
+		if item.CollectionID.Hex() == collectionID {
+			t.Error("Expected collection id to be different from ", collectionID)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package daos
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// ImportRows creates or updates (if the row has an `_id` column value) the collection
+// entities from the provided flat entity rows. Each entity is saved through `Create()`/`Update()`,
+// so the imported data is validated the same way as a regular entity create/update.
+// The invalid rows are reported in the result errors without aborting the import.
+func (dao *EntityDAO) ImportRows(
+	collection *models.Collection,
+	rows []models.EntityRow,
+	publish bool,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) *models.EntityImport {
+	result := &models.EntityImport{
+		Created: []bson.ObjectId{},
+		Updated: []bson.ObjectId{},
+		Errors:  map[string]interface{}{},
+	}
+
+	languages, languagesErr := NewLanguageDAO(dao.Session).GetAll()
+	if languagesErr != nil {
+		result.Errors["0"] = languagesErr.Error()
+
+		return result
+	}
+
+	locales := []string{}
+	for _, lang := range languages {
+		locales = append(locales, lang.Locale)
+	}
+
+	columns := models.NewEntityRowColumns(collection, locales)
+
+	for i, row := range rows {
+		rowKey := strconv.Itoa(i + 1)
+
+		model, created, err := dao.importRow(collection, columns, row, publish, author, additionalConditions...)
+		if err != nil {
+			if dataErr, ok := err.(*utils.DataError); ok {
+				result.Errors[rowKey] = dataErr.Data
+			} else {
+				result.Errors[rowKey] = err.Error()
+			}
+
+			continue
+		}
+
+		if created {
+			result.Created = append(result.Created, model.ID)
+			result.CreatedEntities = append(result.CreatedEntities, *model)
+		} else {
+			result.Updated = append(result.Updated, model.ID)
+			result.UpdatedEntities = append(result.UpdatedEntities, *model)
+		}
+	}
+
+	return result
+}
+
+// importRow creates or updates a single collection entity from the provided row.
+// Returns the saved entity model and whether it was newly created.
+func (dao *EntityDAO) importRow(
+	collection *models.Collection,
+	columns []models.EntityRowColumn,
+	row models.EntityRow,
+	publish bool,
+	author models.RevisionAuthor,
+	additionalConditions ...bson.M,
+) (*models.Entity, bool, error) {
+	data := row.Data(columns)
+
+	if err := dao.resolveRelationSlugs(collection, data); err != nil {
+		return nil, false, err
+	}
+
+	id := row.String(models.EntityRowColumnID)
+	status := row.String(models.EntityRowColumnStatus)
+
+	// create
+	if id == "" {
+		if status == "" {
+			status = models.EntityStatusActive
+		}
+
+		model, err := dao.Create(&models.EntityForm{
+			CollectionID: collection.ID,
+			Status:       status,
+			Data:         data,
+			Author:       author,
+		})
+
+		return model, true, err
+	}
+
+	// update
+	conditions := bson.M{"collection_id": collection.ID}
+	if len(additionalConditions) > 0 {
+		for k, v := range additionalConditions[0] {
+			conditions[k] = v
+		}
+	}
+
+	entity, fetchErr := dao.GetByID(id, conditions)
+	if fetchErr != nil {
+		return nil, false, errors.New("Entity item doesn't exist.")
+	}
+
+	if status == "" {
+		status = entity.Status
+	}
+
+	// merge the row values with a copy of the latest entity data
+	oldData := entity.Draft
+	if len(oldData) == 0 {
+		oldData = entity.Data
+	}
+
+	mergedData := map[string]map[string]interface{}{}
+	for _, group := range []map[string]map[string]interface{}{oldData, data} {
+		for locale, values := range group {
+			if _, ok := mergedData[locale]; !ok {
+				mergedData[locale] = map[string]interface{}{}
+			}
+
+			for k, v := range values {
+				mergedData[locale][k] = v
+			}
+		}
+	}
+
+	model, err := dao.Update(&models.EntityForm{
+		Model:        entity,
+		CollectionID: collection.ID,
+		Status:       status,
+		Data:         mergedData,
+		PublishAt:    entity.PublishAt,
+		UnpublishAt:  entity.UnpublishAt,
+		Publish:      publish,
+		Author:       author,
+	})
+
+	return model, false, err
+}
+
+// resolveRelationSlugs replaces the relation field values of the provided entity data
+// that are not valid ids with the ids of the related collection entities having such slug.
+func (dao *EntityDAO) resolveRelationSlugs(collection *models.Collection, data map[string]map[string]interface{}) error {
+	collectionDAO := NewCollectionDAO(dao.Session)
+
+	errorsMap := map[string]interface{}{}
+
+	for _, field := range collection.Fields {
+		if field.Type != models.FieldTypeRelation {
+			continue
+		}
+
+		meta, _ := models.NewMetaRelation(field.Meta)
+
+		var relCollection *models.Collection
+
+		for locale, values := range data {
+			items, ok := values[field.Key].([]interface{})
+			if !ok {
+				continue
+			}
+
+			for i, item := range items {
+				slug, isString := item.(string)
+				if !isString || slug == "" || bson.IsObjectIdHex(slug) {
+					continue
+				}
+
+				if relCollection == nil {
+					var collectionErr error
+
+					relCollection, collectionErr = collectionDAO.GetByID(meta.CollectionID.Hex())
+					if collectionErr != nil {
+						return collectionErr
+					}
+				}
+
+				var rel *models.Entity
+
+				conditions, conditionsErr := dao.SlugConditions(relCollection, slug)
+				if conditionsErr != nil {
+					return conditionsErr
+				}
+
+				if conditions != nil {
+					rel, _ = dao.GetOne(conditions)
+				}
+
+				if rel == nil || rel.ID == "" {
+					column := locale + "." + field.Key
+					if locale == models.EntitySharedDataKey {
+						column = field.Key
+					}
+
+					errorsMap[column] = fmt.Sprintf("Unable to resolve relation %q.", slug)
+
+					continue
+				}
+
+				items[i] = rel.ID
+			}
+		}
+	}
+
+	if len(errorsMap) > 0 {
+		return utils.NewDataError(errorsMap)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package daos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestEntityDAO_ImportRows(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	runMigrations(t)
+
+	dao := NewEntityDAO(TestSession)
+
+	// relate the "col2" rels field to a new collection with slug field
+	slugCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "slug_test",
+		Fields: []models.CollectionField{
+			{Key: "title", Type: models.FieldTypePlain, Multilingual: true},
+			{Key: "slug", Type: models.FieldTypeSlug, Multilingual: true, Meta: map[string]interface{}{"source": "title"}},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(slugCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	relErr := TestSession.DB("").C("collection").Update(
+		bson.M{"_id": bson.ObjectIdHex("5a8b32d4e13823769a18bc1c"), "fields.key": "rels"},
+		bson.M{"$set": bson.M{"fields.$.meta.collection_id": slugCollection.ID.Hex()}},
+	)
+	if relErr != nil {
+		t.Fatal("Failed to update the collection field meta", relErr)
+	}
+
+	rel := &models.Entity{
+		ID:           bson.NewObjectId(),
+		CollectionID: slugCollection.ID,
+		Status:       models.EntityStatusActive,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Hello world", "slug": "hello-world"},
+			"bg": map[string]interface{}{"title": "Здравей, свят", "slug": "zdravey-svyat"},
+		},
+	}
+
+	if err := TestSession.DB("").C("entity").Insert(rel); err != nil {
+		t.Fatal("Failed to insert test entity", err)
+	}
+
+	collection, _ := NewCollectionDAO(TestSession).GetByID("5a8b32d4e13823769a18bc1c")
+
+	rows := []models.EntityRow{
+		// valid create (relation by slug)
+		{"en.title": "new", "bg.title": "new", "de.title": "new", "rels": "hello-world"},
+		// unresolvable relation
+		{"en.title": "new", "bg.title": "new", "de.title": "new", "rels": "missing"},
+		// invalid create
+		{"en.title": "", "bg.title": "new", "de.title": "new"},
+		// valid update (relation by locale slug)
+		{"_id": "5a8beaa2e1382310bec8076d", "en.title": "updated", "rels": "zdravey-svyat"},
+		// missing entity
+		{"_id": "5a8bea3ae1382310bec8076b", "en.title": "updated"},
+	}
+
+	result := dao.ImportRows(collection, rows, true, models.RevisionAuthor{})
+
+	if len(result.Created) != 1 {
+		t.Fatalf("Expected 1 created entity, got %v", result.Created)
+	}
+
+	if len(result.Updated) != 1 || result.Updated[0].Hex() != "5a8beaa2e1382310bec8076d" {
+		t.Errorf("Expected only 5a8beaa2e1382310bec8076d to be updated, got %v", result.Updated)
+	}
+
+	if len(result.CreatedEntities) != 1 || result.CreatedEntities[0].ID != result.Created[0] {
+		t.Errorf("Expected the created entity model, got %v", result.CreatedEntities)
+	}
+
+	if len(result.UpdatedEntities) != 1 || result.UpdatedEntities[0].ID != result.Updated[0] {
+		t.Errorf("Expected the updated entity model, got %v", result.UpdatedEntities)
+	}
+
+	for _, key := range []string{"2", "3", "5"} {
+		if _, ok := result.Errors[key]; !ok {
+			t.Errorf("Expected row %s error, got %v", key, result.Errors)
+		}
+	}
+
+	if len(result.Errors) != 3 {
+		t.Errorf("Expected 3 row errors, got %v", result.Errors)
+	}
+
+	if rowErr, _ := result.Errors["2"].(map[string]interface{}); rowErr["rels"] != `Unable to resolve relation "missing".` {
+		t.Errorf("Expected unresolved relation error, got %v", result.Errors["2"])
+	}
+
+	created, _ := dao.GetByID(result.Created[0].Hex())
+	if created == nil || created.Status != models.EntityStatusActive {
+		t.Fatal("Expected the entity to be created as active, got", created)
+	}
+
+	if ids := created.Data[models.EntitySharedDataKey]["rels"]; fmt.Sprint(ids) != fmt.Sprint([]interface{}{rel.ID}) {
+		t.Errorf("Expected %v resolved relation, got %v", rel.ID, ids)
+	}
+
+	updated, _ := dao.GetByID("5a8beaa2e1382310bec8076d")
+	if updated.Data["en"]["title"] != "updated" {
+		t.Errorf("Expected the en title to be updated, got %v", updated.Data["en"]["title"])
+	}
+
+	if updated.Data["bg"]["title"] == "updated" {
+		t.Error("Expected the bg title to be kept")
+	}
+
+	if ids := updated.Data[models.EntitySharedDataKey]["rels"]; fmt.Sprint(ids) != fmt.Sprint([]interface{}{rel.ID}) {
+		t.Errorf("Expected %v resolved relation, got %v", rel.ID, ids)
+	}
+}
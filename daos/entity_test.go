@@ -2,8 +2,11 @@ package daos
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/gofreta/gofreta-api/app"
 	"github.com/gofreta/gofreta-api/fixtures"
 	"github.com/gofreta/gofreta-api/models"
 	"github.com/gofreta/gofreta-api/utils"
@@ -23,6 +26,47 @@ func TestNewEntityDAO(t *testing.T) {
 	}
 }
 
+func TestEntityDAO_EnsureGeoIndexes(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	geoCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "geo_test",
+		Fields: []models.CollectionField{
+			{Key: "location", Type: models.FieldTypeGeoPoint},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(geoCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	if err := dao.EnsureGeoIndexes(); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	indexes, _ := TestSession.DB("").C(dao.Collection).Indexes()
+
+	for _, locale := range []string{models.EntitySharedDataKey, "en", "bg", "de"} {
+		key := "$2dsphere:data." + locale + ".location"
+
+		exist := false
+		for _, index := range indexes {
+			if len(index.Key) == 1 && index.Key[0] == key {
+				exist = true
+				break
+			}
+		}
+
+		if !exist {
+			t.Errorf("Expected %s index to exist", key)
+		}
+	}
+}
+
 func TestEntityDAO_Count(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -86,6 +130,24 @@ func TestEntityDAO_GetList(t *testing.T) {
 	}
 }
 
+func TestEntityProjection_IsEmpty(t *testing.T) {
+	testScenarios := []struct {
+		Projection EntityProjection
+		Expected   bool
+	}{
+		{EntityProjection{}, true},
+		{EntityProjection{Paths: []string{"data.en.title"}}, true},
+		{EntityProjection{Fields: []string{"title"}}, false},
+		{EntityProjection{Locales: []string{"en"}}, false},
+	}
+
+	for i, scenario := range testScenarios {
+		if result := scenario.Projection.IsEmpty(); result != scenario.Expected {
+			t.Errorf("(%d) Expected %v, got %v", i, scenario.Expected, result)
+		}
+	}
+}
+
 func TestEntityDAO_GetOne(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -117,6 +179,72 @@ func TestEntityDAO_GetOne(t *testing.T) {
 	}
 }
 
+func TestEntityDAO_GetOne_Projection(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	conditions := bson.M{"_id": bson.ObjectIdHex("5a8bea7ee1382310bec8076c")}
+
+	testScenarios := []struct {
+		Projection     EntityProjection
+		ExpectedData   string
+		ExpectedDraft  string
+		ExpectedSearch bool
+	}{
+		{
+			EntityProjection{},
+			`{"bg":{"description":"\u003cp\u003eTest 2 description bg\u003c/p\u003e","short_description":"Test 2 short description bg","title":"Test 2 title bg"},"de":{"description":"\u003cp\u003eTest 2 description de\u003c/p\u003e","short_description":"Test 2 short description de","title":"Test 2 title de"},"en":{"description":"\u003cp\u003eTest 2 description en\u003c/p\u003e","short_description":"Test 2 short description en","title":"Test 2 title en"}}`,
+			`{"bg":{"description":"\u003cp\u003eTest 2 description bg\u003c/p\u003e","short_description":"Test 2 short description bg","title":"Test 2 draft title bg"},"de":{"description":"\u003cp\u003eTest 2 description de\u003c/p\u003e","short_description":"Test 2 short description de","title":"Test 2 draft title de"},"en":{"description":"\u003cp\u003eTest 2 description en\u003c/p\u003e","short_description":"Test 2 short description en","title":"Test 2 draft title en"}}`,
+			true,
+		},
+		{
+			EntityProjection{Fields: []string{"title"}},
+			`{"bg":{"title":"Test 2 title bg"},"de":{"title":"Test 2 title de"},"en":{"title":"Test 2 title en"}}`,
+			`{"bg":{"title":"Test 2 draft title bg"},"de":{"title":"Test 2 draft title de"},"en":{"title":"Test 2 draft title en"}}`,
+			false,
+		},
+		{
+			EntityProjection{Locales: []string{"en"}},
+			`{"en":{"description":"\u003cp\u003eTest 2 description en\u003c/p\u003e","short_description":"Test 2 short description en","title":"Test 2 title en"}}`,
+			`{"en":{"description":"\u003cp\u003eTest 2 description en\u003c/p\u003e","short_description":"Test 2 short description en","title":"Test 2 draft title en"}}`,
+			false,
+		},
+		{
+			EntityProjection{Fields: []string{"title", "missing"}, Locales: []string{"en", "de"}},
+			`{"de":{"title":"Test 2 title de"},"en":{"title":"Test 2 title en"}}`,
+			`{"de":{"title":"Test 2 draft title de"},"en":{"title":"Test 2 draft title en"}}`,
+			false,
+		},
+	}
+
+	for i, scenario := range testScenarios {
+		item, err := dao.GetOne(conditions, scenario.Projection)
+		if err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+
+		if item.Status != models.EntityStatusActive || item.Created == 0 {
+			t.Errorf("(%d) Expected the entity meta fields to be always fetched, got %v", i, item)
+		}
+
+		data, _ := json.Marshal(item.Data)
+		if string(data) != scenario.ExpectedData {
+			t.Errorf("(%d) Expected data %s, got %s", i, scenario.ExpectedData, data)
+		}
+
+		draft, _ := json.Marshal(item.Draft)
+		if string(draft) != scenario.ExpectedDraft {
+			t.Errorf("(%d) Expected draft %s, got %s", i, scenario.ExpectedDraft, draft)
+		}
+
+		if (len(item.SearchText) > 0) != scenario.ExpectedSearch {
+			t.Errorf("(%d) Unexpected search text %v", i, item.SearchText)
+		}
+	}
+}
+
 func TestEntityDAO_GetByID(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -215,6 +343,74 @@ func TestEntityDAO_GetEntityCollection(t *testing.T) {
 	}
 }
 
+func TestEntityDAO_CountSearch(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	testScenarios := []struct {
+		Term       string
+		Conditions bson.M
+		Expected   int
+	}{
+		{"missing", nil, 0},
+		{"title", nil, 2},
+		{"draft", nil, 0},
+		{"title", bson.M{"_id": bson.ObjectIdHex("5a8bea3ae1382310bec8076b")}, 1},
+		{"title -2", nil, 1},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.CountSearch(scenario.Term, scenario.Conditions)
+		if result != scenario.Expected {
+			t.Errorf("Expected %d, got %d (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestEntityDAO_Search(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	testScenarios := []struct {
+		Term          string
+		Limit         int
+		Offset        int
+		Conditions    bson.M
+		ExpectedOrder []string
+	}{
+		{"missing", 10, 0, nil, []string{}},
+		{"title 1", 10, 0, nil, []string{"5a8bea3ae1382310bec8076b", "5a8bea7ee1382310bec8076c"}},
+		{"title 2", 10, 0, nil, []string{"5a8bea7ee1382310bec8076c", "5a8bea3ae1382310bec8076b"}},
+		{"title 2", 10, 1, nil, []string{"5a8bea3ae1382310bec8076b"}},
+		{"title", 10, 0, bson.M{"status": models.EntityStatusInactive}, []string{}},
+	}
+
+	for _, scenario := range testScenarios {
+		result, err := dao.Search(scenario.Term, scenario.Limit, scenario.Offset, scenario.Conditions)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v (scenario %v)", err, scenario)
+		}
+
+		if len(result) != len(scenario.ExpectedOrder) {
+			t.Fatalf("Expected %d items, got %d (scenario %v)", len(scenario.ExpectedOrder), len(result), scenario)
+		}
+
+		for i, id := range scenario.ExpectedOrder {
+			if result[i].ID.Hex() != id {
+				t.Errorf("Invalid order - expected %s to be at position %d (scenario %v)", id, i, scenario)
+			}
+
+			if result[i].Score <= 0 {
+				t.Errorf("Expected positive text score, got %v (scenario %v)", result[i].Score, scenario)
+			}
+		}
+	}
+}
+
 func TestEntityDAO_Create(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -280,6 +476,24 @@ func TestEntityDAO_Create(t *testing.T) {
 			t.Error("Expected created to be set")
 		}
 	}
+
+	// missing field values should be filled with their collection defaults
+	createdModel, err := dao.Create(&models.EntityForm{
+		CollectionID: bson.ObjectIdHex("5a8b32d4e13823769a18bc1c"),
+		Status:       models.EntityStatusActive,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "test"},
+			"bg": map[string]interface{}{"title": "test"},
+			"de": map[string]interface{}{"title": "test"},
+		},
+	})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if published := createdModel.Data[models.EntitySharedDataKey]["published"]; published != true {
+		t.Errorf("Expected the published default value to be set, got %v", published)
+	}
 }
 
 func TestEntityDAO_Update(t *testing.T) {
@@ -294,10 +508,11 @@ func TestEntityDAO_Update(t *testing.T) {
 		Status       string
 		CollectionID bson.ObjectId
 		Data         map[string]map[string]interface{}
+		Publish      bool
 		ExpectError  bool
 	}{
-		{"", "", nil, true},
-		{models.EntityStatusActive, bson.ObjectIdHex("5a833090e1382351eaad3732"), nil, true},
+		{"", "", nil, false, true},
+		{models.EntityStatusActive, bson.ObjectIdHex("5a833090e1382351eaad3732"), nil, false, true},
 		{models.EntityStatusActive, bson.ObjectIdHex("5a833090e1382351eaad3732"), map[string]map[string]interface{}{
 			"en": map[string]interface{}{
 				"title":       "test",
@@ -313,7 +528,7 @@ func TestEntityDAO_Update(t *testing.T) {
 				"title":       "test",
 				"description": "test",
 			},
-		}, true},
+		}, false, true},
 		{models.UserStatusInactive, bson.ObjectIdHex("5a833090e1382351eaad3732"), map[string]map[string]interface{}{
 			"en": map[string]interface{}{
 				"title":       "test",
@@ -329,7 +544,21 @@ func TestEntityDAO_Update(t *testing.T) {
 				"title":       "test",
 				"description": "test",
 			},
-		}, false},
+		}, false, false},
+		{models.UserStatusInactive, bson.ObjectIdHex("5a833090e1382351eaad3732"), map[string]map[string]interface{}{
+			"en": map[string]interface{}{
+				"title":       "test_publish",
+				"description": "test",
+			},
+			"bg": map[string]interface{}{
+				"title":       "test_publish",
+				"description": "test",
+			},
+			"de": map[string]interface{}{
+				"title":       "test_publish",
+				"description": "test",
+			},
+		}, true, false},
 	}
 
 	for _, scenario := range testScenarios {
@@ -338,6 +567,7 @@ func TestEntityDAO_Update(t *testing.T) {
 			Status:       scenario.Status,
 			Data:         scenario.Data,
 			CollectionID: scenario.CollectionID,
+			Publish:      scenario.Publish,
 		}
 
 		updatedModel, err := dao.Update(form)
@@ -355,6 +585,16 @@ func TestEntityDAO_Update(t *testing.T) {
 		if updatedModel.Modified == originalModel.Modified {
 			t.Errorf("Expected modified date to be updated, got %d vs %d (scenario %v)", updatedModel.Modified, originalModel.Modified, scenario)
 		}
+
+		if updatedModel.Draft["en"]["title"] != scenario.Data["en"]["title"] {
+			t.Errorf("Expected the draft data to be updated, got %v (scenario %v)", updatedModel.Draft, scenario)
+		}
+
+		if scenario.Publish && updatedModel.Data["en"]["title"] != scenario.Data["en"]["title"] {
+			t.Errorf("Expected the live data to be published, got %v (scenario %v)", updatedModel.Data, scenario)
+		} else if !scenario.Publish && updatedModel.Data["en"]["title"] != originalModel.Data["en"]["title"] {
+			t.Errorf("Expected the live data to be unchanged, got %v (scenario %v)", updatedModel.Data, scenario)
+		}
 	}
 }
 
@@ -392,151 +632,523 @@ func TestEntityDAO_Delete(t *testing.T) {
 		if getErr == nil {
 			t.Error("Expected the record to be deleted")
 		}
+
+		// ensure that a delete revision has been stored
+		revisionDAO := NewEntityRevisionDAO(TestSession)
+		if _, revisionErr := revisionDAO.GetOne(bson.M{"entity_id": scenario.Model.ID, "action": models.EntityRevisionActionDelete}); revisionErr != nil {
+			t.Error("Expected delete revision to be stored, got error", revisionErr)
+		}
 	}
 }
 
-func TestEntityDAO_DeleteAll(t *testing.T) {
+func TestEntityDAO_Update_TranslationFlags(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
 	dao := NewEntityDAO(TestSession)
 
-	collectionID := "5a833090e1382351eaad3732"
+	originalModel, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
 
-	dao.DeleteAll(bson.M{"collection_id": bson.ObjectIdHex(collectionID)})
+	data := map[string]map[string]interface{}{}
+	for locale, values := range originalModel.Draft {
+		data[locale] = map[string]interface{}{}
+		for k, v := range values {
+			data[locale][k] = v
+		}
+	}
 
-	items, _ := dao.GetList(100, 0, nil, nil)
+	// change the source (en) title and the bg description
+	data["en"]["title"] = "New title en"
+	data["bg"]["description"] = "New description bg"
 
-	if len(items) != 3 {
-		t.Error("Expected 3 items, got", len(items))
+	updatedModel, err := dao.Update(&models.EntityForm{
+		Model:        originalModel,
+		Status:       originalModel.Status,
+		Data:         data,
+		CollectionID: originalModel.CollectionID,
+	})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
 	}
 
-	for _, item := range items {
-		if item.CollectionID.Hex() == collectionID {
-			t.Error("Expected collection id to be different from ", collectionID)
-		}
+	expected := models.TranslationFlags{
+		"bg": []string{"title"},
+		"de": []string{"title"},
+	}
+
+	if !reflect.DeepEqual(updatedModel.NeedsTranslation, expected) {
+		t.Errorf("Expected %v flags, got %v", expected, updatedModel.NeedsTranslation)
+	}
+
+	// ensure that the flags were persisted
+	storedModel, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+	if !reflect.DeepEqual(storedModel.NeedsTranslation, expected) {
+		t.Errorf("Expected %v stored flags, got %v", expected, storedModel.NeedsTranslation)
 	}
 }
 
-func TestEntityDAO_InitDataLocale(t *testing.T) {
+func TestEntityDAO_ImportTranslations(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
 	dao := NewEntityDAO(TestSession)
 
-	defaultLocale := "en"
-	newLocale := "new_locale"
+	collection, _ := NewCollectionDAO(TestSession).GetByName("col1")
 
-	dao.InitDataLocale(newLocale, defaultLocale)
+	units := []models.TranslationUnit{
+		{ID: "invalid", Target: "test"},
+		{ID: "5a8bea3ae1382310bec8076b.missing", Target: "test"},
+		{ID: "5a8bea3ae1382310bec8076b.title", Target: "Imported title bg"},
+		{ID: "5a8bea3ae1382310bec8076b.description", Target: ""}, // not translated
+		{ID: "5a8beaa2e1382310bec8076d.title", Target: "test"},   // from different collection
+		{ID: "5a8bea7ee1382310bec8076c.title", Target: "Imported title 2 bg"},
+	}
 
-	items, err := dao.GetList(100, 0, nil, nil)
+	result := dao.ImportTranslations(collection, "bg", units, false, models.RevisionAuthor{})
 
-	if err != nil {
-		t.Fatal("Expected nil, got error", err)
+	expectedUpdated := []bson.ObjectId{
+		bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+		bson.ObjectIdHex("5a8bea7ee1382310bec8076c"),
+	}
+	if !reflect.DeepEqual(result.Updated, expectedUpdated) {
+		t.Errorf("Expected %v updated entities, got %v", expectedUpdated, result.Updated)
 	}
 
-	for _, item := range items {
-		if _, exist := item.Data[newLocale]; !exist {
-			t.Errorf("Expected %s to be set (model %v)", newLocale, item)
+	if len(result.Entities) != len(expectedUpdated) || result.Entities[0].ID != expectedUpdated[0] {
+		t.Errorf("Expected the updated entity models, got %v", result.Entities)
+	}
+
+	expectedErrorKeys := []string{"invalid", "5a8bea3ae1382310bec8076b.missing", "5a8beaa2e1382310bec8076d"}
+	if len(result.Errors) != len(expectedErrorKeys) {
+		t.Errorf("Expected %d errors, got %v", len(expectedErrorKeys), result.Errors)
+	}
+	for _, key := range expectedErrorKeys {
+		if _, ok := result.Errors[key]; !ok {
+			t.Errorf("Expected %s error key, got %v", key, result.Errors)
 		}
+	}
 
-		defaultData, _ := json.Marshal(item.Data[defaultLocale])
-		newData, _ := json.Marshal(item.Data[newLocale])
+	entity1, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	if entity1.Draft["bg"]["title"] != "Imported title bg" || entity1.Draft["bg"]["description"] != "<p>Test description bg</p>" {
+		t.Errorf("Expected only the bg title draft to be changed, got %v", entity1.Draft["bg"])
+	}
+	if entity1.Data["bg"]["title"] != "Test 1 title bg" {
+		t.Errorf("Expected the live data to be unchanged, got %v", entity1.Data["bg"])
+	}
 
-		if string(defaultData) != string(newData) {
-			t.Errorf("Expected item data to be duplicated for the new locale (model %v)", item)
-		}
+	entity2, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+	if entity2.Draft["bg"]["title"] != "Imported title 2 bg" || entity2.Draft["en"]["title"] != "Test 2 draft title en" {
+		t.Errorf("Expected only the bg title draft to be changed, got %v", entity2.Draft)
 	}
 }
 
-func TestEntityDAO_RenameDataLocale(t *testing.T) {
+func TestEntityDAO_Restore(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
 	dao := NewEntityDAO(TestSession)
+	revisionDAO := NewEntityRevisionDAO(TestSession)
 
-	oldLocale := "en"
-	newLocale := "en_new"
-
-	dao.RenameDataLocale(oldLocale, newLocale)
+	author := models.RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
 
-	items, err := dao.GetList(100, 0, nil, nil)
+	// invalid revision data (missing required fields)
+	invalidRevision, _ := revisionDAO.GetByID("5b1e5a0be138235b3f1c7a03")
+	invalidRevision.CollectionID = bson.ObjectIdHex("5a833090e1382351eaad3732")
+	if _, err := dao.Restore(invalidRevision, author); err == nil {
+		t.Error("Expected error, got nil")
+	}
 
+	// existing entity
+	revision, _ := revisionDAO.GetByID("5b1e5a0be138235b3f1c7a01")
+	restored, err := dao.Restore(revision, author)
 	if err != nil {
 		t.Fatal("Expected nil, got error", err)
 	}
 
-	for _, item := range items {
-		if _, exist := item.Data[oldLocale]; exist {
-			t.Fatalf("Expected %s to be replaced with %s (model %v)", oldLocale, newLocale, item)
-		}
+	if restored.Status != models.EntityStatusInactive || restored.Draft["en"]["title"] != "Test 1 old title en" {
+		t.Error("Expected the entity draft to be restored to the revision state, got", restored)
+	}
 
-		if _, exist := item.Data[newLocale]; !exist {
-			t.Fatalf("Expected %s to be replaced with %s (model %v)", oldLocale, newLocale, item)
-		}
+	if restored.Data["en"]["title"] != "Test 1 title en" {
+		t.Error("Expected the entity live data to be unchanged, got", restored.Data)
+	}
+
+	// deleted entity
+	entity, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	dao.Delete(entity, author)
+
+	recreated, recreateErr := dao.Restore(revision, author)
+	if recreateErr != nil {
+		t.Fatal("Expected nil, got error", recreateErr)
+	}
+
+	if _, fetchErr := dao.GetByID(recreated.ID.Hex()); fetchErr != nil || recreated.ID != revision.EntityID {
+		t.Error("Expected the deleted entity to be recreated with its original id")
+	}
+
+	if recreated.Data["en"]["title"] != "Test 1 old title en" {
+		t.Error("Expected the recreated entity to be published, got", recreated.Data)
+	}
+
+	if recreated.Created != revision.EntityCreated {
+		t.Errorf("Expected the original entity created time %d, got %d", revision.EntityCreated, recreated.Created)
+	}
+
+	// create, delete and 2 restore revisions
+	count, _ := revisionDAO.Count(bson.M{"entity_id": revision.EntityID})
+	if count != 5 {
+		t.Error("Expected 5 entity revisions, got", count)
 	}
 }
 
-func TestEntityDAO_RemoveDataLocale(t *testing.T) {
+func TestEntityDAO_Publish(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
 	dao := NewEntityDAO(TestSession)
 
-	locale := "en"
+	author := models.RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
 
-	dao.RemoveDataLocale(locale)
+	// invalid draft data (missing required fields)
+	invalidModel, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	invalidModel.Draft = map[string]map[string]interface{}{}
+	if _, err := dao.Publish(invalidModel, author); err == nil {
+		t.Error("Expected error, got nil")
+	}
 
-	items, err := dao.GetList(100, 0, nil, nil)
+	// valid draft data
+	model, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+	model.Status = models.EntityStatusInactive
 
+	published, err := dao.Publish(model, author)
 	if err != nil {
 		t.Fatal("Expected nil, got error", err)
 	}
 
-	for _, item := range items {
-		if _, exist := item.Data[locale]; exist {
-			t.Errorf("Expected %s to be removed (model %v)", locale, item)
-		}
+	stored, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+
+	if stored.Status != models.EntityStatusActive {
+		t.Error("Expected the entity to be activated, got", stored.Status)
+	}
+
+	if stored.Data["en"]["title"] != "Test 2 draft title en" {
+		t.Error("Expected the draft data to be published, got", stored.Data)
+	}
+
+	if stored.PublishAt != 0 || stored.Published != published.Published {
+		t.Errorf("Expected publish_at to be reset and published to be %d, got %d and %d", published.Published, stored.PublishAt, stored.Published)
+	}
+
+	if total, _ := dao.CountSearch("draft", nil); total != 1 {
+		t.Error("Expected the published data to be searchable, got", total)
+	}
+
+	// ensure that a publish revision has been stored
+	revisionDAO := NewEntityRevisionDAO(TestSession)
+	if _, revisionErr := revisionDAO.GetOne(bson.M{"entity_id": model.ID, "action": models.EntityRevisionActionPublish}); revisionErr != nil {
+		t.Error("Expected publish revision to be stored, got error", revisionErr)
 	}
 }
 
-func TestEntityDAO_validateAndNormalizeData(t *testing.T) {
+func TestEntityDAO_Unpublish(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
 	dao := NewEntityDAO(TestSession)
-	languageDAO := NewLanguageDAO(TestSession)
 
-	validLocales := []string{}
-	languages, _ := languageDAO.GetAll()
-	for _, lang := range languages {
-		validLocales = append(validLocales, lang.Locale)
+	author := models.RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
+
+	model, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	model.UnpublishAt = 1519200000
+
+	if _, err := dao.Unpublish(model, author); err != nil {
+		t.Fatal("Expected nil, got error", err)
 	}
 
-	validKeys := []string{"title", "short_description", "description"}
+	stored, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
 
-	testScenarios := []struct {
-		Model       *models.Entity
-		ExpectError bool
-	}{
-		{
-			&models.Entity{
-				CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
-				Data: map[string]map[string]interface{}{
-					"missing_locale": map[string]interface{}{
-						"title":       "test",
-						"description": "test",
-					},
-				},
-			},
-			true,
-		},
-		{
-			&models.Entity{
-				CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
-				Data: map[string]map[string]interface{}{
-					"missing_locale": map[string]interface{}{
-						"title":       "test",
+	if stored.Status != models.EntityStatusInactive || stored.UnpublishAt != 0 {
+		t.Error("Expected the entity to be deactivated and unpublish_at to be reset, got", stored)
+	}
+
+	// ensure that an unpublish revision has been stored
+	revisionDAO := NewEntityRevisionDAO(TestSession)
+	if _, revisionErr := revisionDAO.GetOne(bson.M{"entity_id": model.ID, "action": models.EntityRevisionActionUnpublish}); revisionErr != nil {
+		t.Error("Expected unpublish revision to be stored, got error", revisionErr)
+	}
+}
+
+func TestEntityDAO_UpdateStatus(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	author := models.RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
+
+	model, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+
+	if _, err := dao.UpdateStatus(model, "invalid", author); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if _, err := dao.UpdateStatus(model, models.EntityStatusInactive, author); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+
+	if stored.Status != models.EntityStatusInactive {
+		t.Error("Expected the entity to be deactivated, got", stored)
+	}
+
+	// ensure that an update revision has been stored
+	revisionDAO := NewEntityRevisionDAO(TestSession)
+	if _, revisionErr := revisionDAO.GetOne(bson.M{"entity_id": model.ID, "action": models.EntityRevisionActionUpdate}); revisionErr != nil {
+		t.Error("Expected update revision to be stored, got error", revisionErr)
+	}
+}
+
+func TestEntityDAO_PublishScheduled(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	session := TestSession.Copy()
+	defer session.Close()
+
+	// schedule unpublish for an elapsed and for a future time
+	session.DB("").C(dao.Collection).UpdateId(bson.ObjectIdHex("5a8bea3ae1382310bec8076b"), bson.M{"$set": bson.M{"unpublish_at": 1519200000}})
+	session.DB("").C(dao.Collection).UpdateId(bson.ObjectIdHex("5a8beab7e1382310bec8076e"), bson.M{"$set": bson.M{"unpublish_at": 4102444800}})
+
+	items, err := dao.PublishScheduled(models.RevisionAuthor{Model: "scheduler"})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 changed items, got %d (%v)", len(items), items)
+	}
+
+	published, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+	if published.PublishAt != 0 || published.Data["en"]["title"] != "Test 2 draft title en" {
+		t.Error("Expected the scheduled entity to be published, got", published)
+	}
+
+	unpublished, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	if unpublished.UnpublishAt != 0 || unpublished.Status != models.EntityStatusInactive {
+		t.Error("Expected the scheduled entity to be unpublished, got", unpublished)
+	}
+
+	notChanged, _ := dao.GetByID("5a8beab7e1382310bec8076e")
+	if notChanged.UnpublishAt == 0 || notChanged.Status != models.EntityStatusActive {
+		t.Error("Expected the future scheduled entity to be unchanged, got", notChanged)
+	}
+
+	// already processed schedules
+	items2, err2 := dao.PublishScheduled(models.RevisionAuthor{Model: "scheduler"})
+	if err2 != nil || len(items2) != 0 {
+		t.Errorf("Expected no changed items and nil error, got %v (%v)", items2, err2)
+	}
+
+	// invalid draft (missing required title)
+	session.DB("").C(dao.Collection).UpdateId(bson.ObjectIdHex("5a8bea7ee1382310bec8076c"), bson.M{"$set": bson.M{
+		"publish_at":     1519200000,
+		"draft.en.title": "",
+	}})
+
+	items3, err3 := dao.PublishScheduled(models.RevisionAuthor{Model: "scheduler"})
+	if err3 == nil || len(items3) != 0 {
+		t.Errorf("Expected no changed items and error, got %v (%v)", items3, err3)
+	}
+
+	failed, _ := dao.GetByID("5a8bea7ee1382310bec8076c")
+	if failed.PublishAt != 0 {
+		t.Error("Expected the failed entity schedule to be cleared, got", failed.PublishAt)
+	}
+
+	// the failed entity is not retried
+	items4, err4 := dao.PublishScheduled(models.RevisionAuthor{Model: "scheduler"})
+	if err4 != nil || len(items4) != 0 {
+		t.Errorf("Expected no changed items and nil error, got %v (%v)", items4, err4)
+	}
+}
+
+func TestEntityDAO_DeleteAll(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	collectionID := "5a833090e1382351eaad3732"
+
+	dao.DeleteAll(bson.M{"collection_id": bson.ObjectIdHex(collectionID)})
+
+	items, _ := dao.GetList(100, 0, nil, nil)
+
+	if len(items) != 3 {
+		t.Error("Expected 3 items, got", len(items))
+	}
+
+	for _, item := range items {
+		if item.CollectionID.Hex() == collectionID {
+			t.Error("Expected collection id to be different from ", collectionID)
+		}
+	}
+}
+
+func TestEntityDAO_InitDataLocale(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	defaultLocale := "en"
+	newLocale := "new_locale"
+
+	dao.InitDataLocale(newLocale, defaultLocale)
+
+	items, err := dao.GetList(100, 0, nil, nil)
+
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	for _, item := range items {
+		if _, exist := item.Data[newLocale]; !exist {
+			t.Errorf("Expected %s to be set (model %v)", newLocale, item)
+		}
+
+		defaultData, _ := json.Marshal(item.Data[defaultLocale])
+		newData, _ := json.Marshal(item.Data[newLocale])
+
+		if string(defaultData) != string(newData) {
+			t.Errorf("Expected item data to be duplicated for the new locale (model %v)", item)
+		}
+	}
+}
+
+func TestEntityDAO_RenameDataLocale(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	oldLocale := "en"
+	newLocale := "en_new"
+
+	dao.RenameDataLocale(oldLocale, newLocale)
+
+	items, err := dao.GetList(100, 0, nil, nil)
+
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	for _, item := range items {
+		if _, exist := item.Data[oldLocale]; exist {
+			t.Fatalf("Expected %s to be replaced with %s (model %v)", oldLocale, newLocale, item)
+		}
+
+		if _, exist := item.Data[newLocale]; !exist {
+			t.Fatalf("Expected %s to be replaced with %s (model %v)", oldLocale, newLocale, item)
+		}
+	}
+}
+
+func TestEntityDAO_RemoveDataLocale(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	locale := "en"
+
+	dao.RemoveDataLocale(locale)
+
+	items, err := dao.GetList(100, 0, nil, nil)
+
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	for _, item := range items {
+		if _, exist := item.Data[locale]; exist {
+			t.Errorf("Expected %s to be removed (model %v)", locale, item)
+		}
+	}
+}
+
+func TestEntityDAO_RefreshSearchTexts(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	collection, _ := NewCollectionDAO(TestSession).GetByName("col1")
+
+	// make the description searchable too
+	for i, field := range collection.Fields {
+		collection.Fields[i].Searchable = (field.Key == "description")
+	}
+
+	if err := dao.RefreshSearchTexts(collection); err != nil {
+		t.Fatal("Expected nil error, got", err)
+	}
+
+	if total, _ := dao.CountSearch("title", nil); total != 0 {
+		t.Error("Expected the title to be no longer searchable, got", total)
+	}
+
+	result, _ := dao.Search("description 2", 10, 0, nil)
+	if len(result) != 2 || result[0].ID.Hex() != "5a8bea7ee1382310bec8076c" {
+		t.Error("Expected the description to be searchable, got", result)
+	}
+
+	entity, _ := dao.GetByID("5a8bea3ae1382310bec8076b")
+	if !reflect.DeepEqual(entity.SearchText, []string{"Test description bg", "Test description de", "Test description en"}) {
+		t.Error("Unexpected search text", entity.SearchText)
+	}
+}
+
+func TestEntityDAO_validateAndNormalizeData(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+	languageDAO := NewLanguageDAO(TestSession)
+
+	validLocales := []string{}
+	languages, _ := languageDAO.GetAll()
+	for _, lang := range languages {
+		validLocales = append(validLocales, lang.Locale)
+	}
+
+	validKeys := []string{"title", "short_description", "description"}
+
+	testScenarios := []struct {
+		Model       *models.Entity
+		ExpectError bool
+	}{
+		{
+			&models.Entity{
+				CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
+				Data: map[string]map[string]interface{}{
+					"missing_locale": map[string]interface{}{
+						"title":       "test",
+						"description": "test",
+					},
+				},
+			},
+			true,
+		},
+		{
+			&models.Entity{
+				CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
+				Data: map[string]map[string]interface{}{
+					"missing_locale": map[string]interface{}{
+						"title":       "test",
 						"description": "test",
 					},
 					"en": map[string]interface{}{
@@ -557,6 +1169,28 @@ func TestEntityDAO_validateAndNormalizeData(t *testing.T) {
 			},
 			false,
 		},
+		{
+			// short_description max_length meta rule
+			&models.Entity{
+				CollectionID: bson.ObjectIdHex("5a833090e1382351eaad3732"),
+				Data: map[string]map[string]interface{}{
+					"en": map[string]interface{}{
+						"title":             "test",
+						"short_description": strings.Repeat("a", 101),
+						"description":       "test",
+					},
+					"bg": map[string]interface{}{
+						"title":       "test",
+						"description": "test",
+					},
+					"de": map[string]interface{}{
+						"title":       "test",
+						"description": "test",
+					},
+				},
+			},
+			true,
+		},
 	}
 
 	for _, scenario := range testScenarios {
@@ -594,6 +1228,265 @@ func TestEntityDAO_validateAndNormalizeData(t *testing.T) {
 	}
 }
 
+func TestEntityDAO_validateAndNormalizeData_Group(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	groupCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "group_test",
+		Fields: []models.CollectionField{
+			{Key: "faq", Type: models.FieldTypeGroup, Required: true, Multilingual: true, Meta: map[string]interface{}{
+				"fields": []map[string]interface{}{
+					{"key": "question", "type": models.FieldTypePlain, "required": true},
+					{"key": "answer", "type": models.FieldTypeEditor, "meta": map[string]interface{}{"max_length": 5}},
+				},
+				"repeat": true,
+				"max":    2,
+			}},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(groupCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	validItem := map[string]interface{}{"question": "test", "answer": "test"}
+
+	testScenarios := []struct {
+		Faq            interface{}
+		ExpectedErrors []string
+	}{
+		{nil, []string{"faq"}},
+		{[]interface{}{validItem, validItem, validItem}, []string{"faq"}},
+		{[]interface{}{validItem, map[string]interface{}{"answer": "too long"}}, []string{"faq.1.question", "faq.1.answer"}},
+		{[]interface{}{validItem, validItem}, []string{}},
+	}
+
+	for i, scenario := range testScenarios {
+		entity := &models.Entity{
+			CollectionID: groupCollection.ID,
+			Data: map[string]map[string]interface{}{
+				"en": map[string]interface{}{"faq": scenario.Faq},
+				"bg": map[string]interface{}{"faq": []interface{}{validItem}},
+				"de": map[string]interface{}{"faq": []interface{}{validItem}},
+			},
+		}
+
+		err := dao.validateAndNormalizeData(entity)
+
+		if len(scenario.ExpectedErrors) == 0 {
+			if err != nil {
+				t.Errorf("(%d) Expected nil, got error %v", i, err)
+			}
+
+			continue
+		}
+
+		dataErr, ok := err.(*utils.DataError)
+		if !ok {
+			t.Errorf("(%d) Expected DataError, got %v", i, err)
+			continue
+		}
+
+		errorsMap, _ := dataErr.Data.(map[string]interface{})
+		localeErrors, _ := errorsMap["en"].(map[string]string)
+		if len(localeErrors) != len(scenario.ExpectedErrors) {
+			t.Errorf("(%d) Expected %d errors, got %v", i, len(scenario.ExpectedErrors), localeErrors)
+		}
+
+		for _, key := range scenario.ExpectedErrors {
+			if _, exist := localeErrors[key]; !exist {
+				t.Errorf("(%d) Expected %s error key in %v", i, key, localeErrors)
+			}
+		}
+	}
+}
+
+func TestEntityDAO_validateAndNormalizeData_Shared(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	sharedCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "shared_test",
+		Fields: []models.CollectionField{
+			{Key: "title", Type: models.FieldTypePlain, Multilingual: true},
+			{Key: "code", Type: models.FieldTypePlain, Required: true, Unique: true},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(sharedCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	existing := &models.Entity{
+		ID:           bson.NewObjectId(),
+		CollectionID: sharedCollection.ID,
+		Data: map[string]map[string]interface{}{
+			models.EntitySharedDataKey: map[string]interface{}{"code": "taken"},
+		},
+		Draft: map[string]map[string]interface{}{
+			models.EntitySharedDataKey: map[string]interface{}{"code": "taken-draft"},
+		},
+	}
+
+	if err := TestSession.DB("").C(dao.Collection).Insert(existing); err != nil {
+		t.Fatal("Failed to insert test entity", err)
+	}
+
+	// the value changed in a single locale is stored once
+	entity := &models.Entity{
+		CollectionID: sharedCollection.ID,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Title en", "code": "abc"},
+			"bg": map[string]interface{}{"title": "Title bg", "code": "new"},
+			"de": map[string]interface{}{"code": "abc"},
+		},
+	}
+
+	oldData := map[string]map[string]interface{}{
+		models.EntitySharedDataKey: map[string]interface{}{"code": "abc"},
+	}
+
+	if err := dao.validateAndNormalizeData(entity, oldData); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if entity.Data[models.EntitySharedDataKey]["code"] != "new" {
+		t.Errorf("Expected the shared code value to be new, got %v", entity.Data)
+	}
+
+	for _, locale := range []string{"en", "bg", "de"} {
+		if _, exist := entity.Data[locale]["code"]; exist {
+			t.Errorf("Expected %s code key to be moved in the shared group, got %v", locale, entity.Data[locale])
+		}
+
+		if _, exist := entity.Data[locale]["title"]; !exist {
+			t.Errorf("Expected %s title key to be set, got %v", locale, entity.Data[locale])
+		}
+	}
+
+	// unique check (the error is reported under each locale)
+	duplicate := &models.Entity{
+		CollectionID: sharedCollection.ID,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Title en", "code": "taken"},
+		},
+	}
+
+	err := dao.validateAndNormalizeData(duplicate)
+
+	dataErr, ok := err.(*utils.DataError)
+	if !ok {
+		t.Fatal("Expected DataError, got", err)
+	}
+
+	errorsMap, _ := dataErr.Data.(map[string]interface{})
+	for _, locale := range []string{"en", "bg", "de"} {
+		localeErrors, _ := errorsMap[locale].(map[string]string)
+
+		if _, exist := localeErrors["code"]; !exist || len(localeErrors) != 1 {
+			t.Errorf("Expected only %s code error, got %v", locale, localeErrors)
+		}
+	}
+
+	// unique check against the other entities drafts
+	draftDuplicate := &models.Entity{
+		CollectionID: sharedCollection.ID,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Title en", "code": "taken-draft"},
+		},
+	}
+
+	if _, ok := dao.validateAndNormalizeData(draftDuplicate).(*utils.DataError); !ok {
+		t.Error("Expected DataError for a value taken by another entity draft")
+	}
+}
+
+func TestEntityDAO_validateAndNormalizeData_Slug(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewEntityDAO(TestSession)
+
+	slugCollection := &models.Collection{
+		ID:   bson.NewObjectId(),
+		Name: "slug_test",
+		Fields: []models.CollectionField{
+			{Key: "title", Type: models.FieldTypePlain, Multilingual: true},
+			{Key: "slug", Type: models.FieldTypeSlug, Multilingual: true, Meta: map[string]interface{}{"source": "title"}},
+			{Key: "code", Type: models.FieldTypeSlug, Meta: map[string]interface{}{"source": "title"}},
+		},
+	}
+
+	if err := TestSession.DB("").C("collection").Insert(slugCollection); err != nil {
+		t.Fatal("Failed to insert test collection", err)
+	}
+
+	existing := &models.Entity{
+		ID:           bson.NewObjectId(),
+		CollectionID: slugCollection.ID,
+		Data: map[string]map[string]interface{}{
+			models.EntitySharedDataKey: map[string]interface{}{"code": "hello-world"},
+			"en":                       map[string]interface{}{"slug": "hello-world"},
+			"bg":                       map[string]interface{}{"slug": "zdravey-svyat"},
+		},
+		// not published yet slug
+		Draft: map[string]map[string]interface{}{
+			"de": map[string]interface{}{"slug": "custom-slug"},
+		},
+	}
+
+	if err := TestSession.DB("").C(dao.Collection).Insert(existing); err != nil {
+		t.Fatal("Failed to insert test entity", err)
+	}
+
+	entity := &models.Entity{
+		CollectionID: slugCollection.ID,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Hello World"},
+			"bg": map[string]interface{}{"title": "Здравей, Свят"},
+			"de": map[string]interface{}{"title": "Hallo Welt", "slug": "Custom Slug"},
+		},
+	}
+
+	if err := dao.validateAndNormalizeData(entity); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	expected := map[string]interface{}{
+		models.EntitySharedDataKey: "hello-world-2",
+		"en":                       "hello-world-2",
+		"bg":                       "zdravey-svyat-2",
+		"de":                       "custom-slug-2",
+	}
+
+	for locale, slug := range expected {
+		key := "slug"
+		if locale == models.EntitySharedDataKey {
+			key = "code"
+		}
+
+		if entity.Data[locale][key] != slug {
+			t.Errorf("Expected %s %s to be %s, got %v", locale, key, slug, entity.Data[locale][key])
+		}
+	}
+
+	// the entity own slug is not changed
+	if err := dao.validateAndNormalizeData(existing); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if existing.Data["en"]["slug"] != "hello-world" {
+		t.Errorf("Expected the existing entity slug to be unchanged, got %v", existing.Data["en"]["slug"])
+	}
+}
+
 func TestEntityDAO_EnrichEntity(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -611,6 +1504,14 @@ func TestEntityDAO_EnrichEntity(t *testing.T) {
 			&EntityEnrichSettings{EnrichMedia: true, MediaConditions: bson.M{"type": "image"}},
 			`{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":null},"de":{"image":null},"en":{"image":null}},"created":1519119046,"modified":1519119046}`,
 		},
+		{
+			&EntityEnrichSettings{EnrichMedia: true, Expand: EntityExpand{"missing": EntityExpand{}}},
+			`{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":["5a7db16de138233f19f7d815"]},"de":{"image":["5a7db16de138233f19f7d815"]},"en":{"image":["5a7db16de138233f19f7d815"]}},"created":1519119046,"modified":1519119046}`,
+		},
+		{
+			&EntityEnrichSettings{EnrichMedia: true, Expand: EntityExpand{"image": EntityExpand{}}},
+			`{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"de":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"en":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}}},"created":1519119046,"modified":1519119046}`,
+		},
 		{
 			&EntityEnrichSettings{EnrichMedia: true},
 			`{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"de":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"en":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}}},"created":1519119046,"modified":1519119046}`,
@@ -685,6 +1586,11 @@ func TestEntityDAO_EnrichEntitiesByCollectionName(t *testing.T) {
 			&EntityEnrichSettings{EnrichMedia: true, MediaConditions: bson.M{"type": "image"}},
 			`[{"id":"5a8beab7e1382310bec8076e","collection_id":"5a8b33a4e13823769a18bc1d","status":"active","data":{"bg":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}},"de":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}},"en":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}}},"created":1519119031,"modified":1519119031},{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":null},"de":{"image":null},"en":{"image":null}},"created":1519119046,"modified":1519119046}]`,
 		},
+		{
+			// not requested media field
+			&EntityEnrichSettings{EnrichMedia: true, Fields: []string{"missing"}},
+			`[{"id":"5a8beab7e1382310bec8076e","collection_id":"5a8b33a4e13823769a18bc1d","status":"active","data":{"bg":{"image":["5a7c9378e138230137212eb5"]},"de":{"image":["5a7c9378e138230137212eb5"]},"en":{"image":["5a7c9378e138230137212eb5"]}},"created":1519119031,"modified":1519119031},{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":["5a7db16de138233f19f7d815"]},"de":{"image":["5a7db16de138233f19f7d815"]},"en":{"image":["5a7db16de138233f19f7d815"]}},"created":1519119046,"modified":1519119046}]`,
+		},
 		{
 			&EntityEnrichSettings{EnrichMedia: true},
 			`[{"id":"5a8beab7e1382310bec8076e","collection_id":"5a8b33a4e13823769a18bc1d","status":"active","data":{"bg":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}},"de":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}},"en":{"image":{"id":"5a7c9378e138230137212eb5","type":"image","title":"file1","description":"","path":"http://localhost:8092/api/data/1.png","created":1518113656,"modified":1518113656}}},"created":1519119031,"modified":1519119031},{"id":"5a8beac6e1382310bec8076f","collection_id":"5a8b33a4e13823769a18bc1d","status":"inactive","data":{"bg":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"de":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}},"en":{"image":{"id":"5a7db16de138233f19f7d815","type":"other","title":"file3","description":"","path":"http://localhost:8092/api/data/3.zip","created":1518186861,"modified":1518186861}}},"created":1519119046,"modified":1519119046}]`,
@@ -801,6 +1707,8 @@ func TestExtractMediaAndRelationIds(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
 
+	runMigrations(t)
+
 	entityDAO := NewEntityDAO(TestSession)
 	collectionDAO := NewCollectionDAO(TestSession)
 
@@ -812,7 +1720,7 @@ func TestExtractMediaAndRelationIds(t *testing.T) {
 		t.Fatal("Expected nil, got error")
 	}
 
-	mediaIDs, relationIDs := extractMediaAndRelationIds(items, collection)
+	mediaIDs, relationIDs := extractMediaAndRelationIds(items, collection, nil)
 
 	expectedMediaIDs := []bson.ObjectId{
 		bson.ObjectIdHex("5a7cb889e1382325ece3a108"),
@@ -851,6 +1759,203 @@ func TestExtractMediaAndRelationIds(t *testing.T) {
 	}
 }
 
+func TestExtractDataIds(t *testing.T) {
+	fields := []models.CollectionField{
+		{Key: "image", Type: models.FieldTypeMedia},
+		{Key: "specs", Type: models.FieldTypeGroup, Meta: map[string]interface{}{
+			"fields": []map[string]interface{}{
+				{"key": "icon", "type": models.FieldTypeMedia},
+				{"key": "product", "type": models.FieldTypeRelation},
+			},
+			"repeat": true,
+		}},
+	}
+
+	data := map[string]interface{}{
+		"image": []interface{}{"5a7cb889e1382325ece3a108"},
+		"specs": []interface{}{
+			bson.M{
+				"icon":    []interface{}{"5a7c9378e138230137212eb5"},
+				"product": []interface{}{"5a8bea3ae1382310bec8076b"},
+			},
+			bson.M{
+				"icon":    []interface{}{},
+				"product": []interface{}{"5a8bea7ee1382310bec8076c"},
+			},
+		},
+	}
+
+	var mIds []interface{}
+	var rIds []interface{}
+
+	extractDataIds(data, fields, &mIds, &rIds, nil)
+
+	if len(mIds) != 2 || mIds[0] != "5a7cb889e1382325ece3a108" || mIds[1] != "5a7c9378e138230137212eb5" {
+		t.Error("Unexpected media ids", mIds)
+	}
+
+	if len(rIds) != 2 || rIds[0] != "5a8bea3ae1382310bec8076b" || rIds[1] != "5a8bea7ee1382310bec8076c" {
+		t.Error("Unexpected relation ids", rIds)
+	}
+
+	// only the expanded fields
+	mIds = nil
+	rIds = nil
+
+	extractDataIds(data, fields, &mIds, &rIds, NewEntityExpand([]string{"specs.product"}))
+
+	if len(mIds) != 0 {
+		t.Error("Expected no media ids, got", mIds)
+	}
+
+	if len(rIds) != 2 || rIds[0] != "5a8bea3ae1382310bec8076b" || rIds[1] != "5a8bea7ee1382310bec8076c" {
+		t.Error("Unexpected relation ids", rIds)
+	}
+}
+
+func TestEnrichDataFields(t *testing.T) {
+	fields := []models.CollectionField{
+		{Key: "specs", Type: models.FieldTypeGroup, Meta: map[string]interface{}{
+			"fields": []map[string]interface{}{
+				{"key": "icon", "type": models.FieldTypeMedia, "meta": map[string]interface{}{"max": 1}},
+			},
+		}},
+	}
+
+	data := map[string]interface{}{
+		"specs": bson.M{"icon": []interface{}{"5a7c9378e138230137212eb5"}},
+	}
+
+	medias := []models.Media{{ID: bson.ObjectIdHex("5a7c9378e138230137212eb5"), Title: "test"}}
+
+	enrichDataFields(data, fields, medias, nil, EntityExpand{"image": EntityExpand{}})
+
+	if _, ok := data["specs"].(bson.M)["icon"].([]interface{}); !ok {
+		t.Error("Expected the not expanded nested media to be unchanged, got", data["specs"])
+	}
+
+	enrichDataFields(data, fields, medias, nil, nil)
+
+	media, ok := data["specs"].(bson.M)["icon"].(models.Media)
+	if !ok || media.Title != "test" {
+		t.Error("Expected the nested media to be enriched, got", data["specs"])
+	}
+}
+
+func TestNewEntityExpand(t *testing.T) {
+	testScenarios := []struct {
+		Paths    []string
+		Expected EntityExpand
+	}{
+		{nil, nil},
+		{[]string{}, nil},
+		{[]string{"author"}, EntityExpand{"author": EntityExpand{}}},
+		{
+			[]string{"author.avatar", "author", "tags", "author.posts.cover", "tags..invalid"},
+			EntityExpand{
+				"author": EntityExpand{
+					"avatar": EntityExpand{},
+					"posts":  EntityExpand{"cover": EntityExpand{}},
+				},
+				"tags": EntityExpand{},
+			},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		result := NewEntityExpand(scenario.Paths)
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario.Paths)
+		}
+	}
+}
+
+func TestEntityExpand_Child(t *testing.T) {
+	var all EntityExpand
+
+	if sub, ok := all.Child("author"); !ok || sub != nil {
+		t.Errorf("Expected nil expandable subtree, got %v (%v)", sub, ok)
+	}
+
+	expand := NewEntityExpand([]string{"author.avatar"})
+
+	if _, ok := expand.Child("tags"); ok {
+		t.Error("Expected tags to not be expandable")
+	}
+
+	sub, ok := expand.Child("author")
+	if !ok || !reflect.DeepEqual(sub, EntityExpand{"avatar": EntityExpand{}}) {
+		t.Errorf("Expected the author subtree, got %v (%v)", sub, ok)
+	}
+}
+
+func TestEntityExpand_Merge(t *testing.T) {
+	a := NewEntityExpand([]string{"author.avatar", "tags"})
+	b := NewEntityExpand([]string{"author.posts", "cover"})
+
+	if result := a.Merge(nil); result != nil {
+		t.Error("Expected nil, got", result)
+	}
+
+	expected := NewEntityExpand([]string{"author.avatar", "author.posts", "tags", "cover"})
+
+	if result := a.Merge(b); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestRelationExpands(t *testing.T) {
+	fields := []models.CollectionField{
+		{Key: "author", Type: models.FieldTypeRelation, Meta: map[string]interface{}{"collection_id": "5a8b32d4e13823769a18bc1c"}},
+		{Key: "editor", Type: models.FieldTypeRelation, Meta: map[string]interface{}{"collection_id": "5a8b32d4e13823769a18bc1c"}},
+		{Key: "specs", Type: models.FieldTypeGroup, Meta: map[string]interface{}{
+			"fields": []map[string]interface{}{
+				{"key": "product", "type": models.FieldTypeRelation, "meta": map[string]interface{}{"collection_id": "5a8b33a4e13823769a18bc1d"}},
+			},
+		}},
+	}
+
+	if result := relationExpands(fields, nil); result != nil {
+		t.Error("Expected nil, got", result)
+	}
+
+	result := relationExpands(fields, NewEntityExpand([]string{"author.avatar", "editor.rels", "specs.product"}))
+
+	expected := map[bson.ObjectId]EntityExpand{
+		bson.ObjectIdHex("5a8b32d4e13823769a18bc1c"): NewEntityExpand([]string{"avatar", "rels"}),
+		bson.ObjectIdHex("5a8b33a4e13823769a18bc1d"): EntityExpand{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestEntityDAO_getEnrichRelations(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	defer app.Config.Set("enrich.batchSize", app.Config.GetInt("enrich.batchSize"))
+	app.Config.Set("enrich.batchSize", 1)
+
+	dao := NewEntityDAO(TestSession)
+
+	ids := []bson.ObjectId{
+		bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+		bson.ObjectIdHex("5a8bea7ee1382310bec8076c"),
+		bson.ObjectIdHex("5a8beab7e1382310bec8076e"),
+	}
+
+	settings := &EntityEnrichSettings{
+		RelCollectionIds: []bson.ObjectId{bson.ObjectIdHex("5a833090e1382351eaad3732")},
+	}
+
+	result := dao.getEnrichRelations(ids, settings)
+
+	assertEntityIds(t, result, []string{"5a8bea3ae1382310bec8076b", "5a8bea7ee1382310bec8076c"})
+}
+
 func TestExtractEntityMedias(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
@@ -0,0 +1,276 @@
+package daos
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// HookDeliveryDAO persists and sends hook deliveries.
+type HookDeliveryDAO struct {
+	Session    *mgo.Session
+	Collection string
+}
+
+// ensureIndexes makes sure that the required db indexes and constraints are set.
+func (dao *HookDeliveryDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	indexes := []mgo.Index{
+		{Key: []string{"collection_id", "-created"}, Background: true},
+		{Key: []string{"status", "next_attempt"}, Background: true},
+	}
+
+	for _, index := range indexes {
+		if err := c.EnsureIndex(index); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// NewHookDeliveryDAO creates a new HookDeliveryDAO.
+func NewHookDeliveryDAO(session *mgo.Session) *HookDeliveryDAO {
+	dao := &HookDeliveryDAO{
+		Session:    session,
+		Collection: "hook_delivery",
+	}
+
+	dao.ensureIndexes()
+
+	return dao
+}
+
+// -------------------------------------------------------------------
+// • Query methods
+// -------------------------------------------------------------------
+
+// Count returns the total number of HookDelivery models based on the provided conditions.
+func (dao *HookDeliveryDAO) Count(conditions bson.M) (int, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	result, err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		Count()
+
+	return result, err
+}
+
+// GetList returns list with HookDelivery models.
+func (dao *HookDeliveryDAO) GetList(limit int, offset int, conditions bson.M, sortData []string) ([]models.HookDelivery, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.HookDelivery{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		Sort(sortData...).
+		Skip(offset).
+		Limit(limit).
+		All(&items)
+
+	return items, err
+}
+
+// GetOne returns single HookDelivery model based on the provided conditions.
+func (dao *HookDeliveryDAO) GetOne(conditions bson.M) (*models.HookDelivery, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := &models.HookDelivery{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		One(model)
+
+	return model, err
+}
+
+// GetByID returns single HookDelivery model by its id.
+func (dao *HookDeliveryDAO) GetByID(id string, additionalConditions ...bson.M) (*models.HookDelivery, error) {
+	if !bson.IsObjectIdHex(id) {
+		err := errors.New("Invalid object id format")
+
+		return &models.HookDelivery{}, err
+	}
+
+	conditions := bson.M{}
+	if len(additionalConditions) > 0 && additionalConditions[0] != nil {
+		conditions = additionalConditions[0]
+	}
+	conditions["_id"] = bson.ObjectIdHex(id)
+
+	return dao.GetOne(conditions)
+}
+
+// -------------------------------------------------------------------
+// • DB persists methods
+// -------------------------------------------------------------------
+
+// Create inserts and returns a new pending hook delivery.
+// The first delivery attempt is scheduled after the retry delay in case
+// the caller doesn't manage to deliver it right away (see `Send()`).
+func (dao *HookDeliveryDAO) Create(collection *models.Collection, url string, hookType string, hookAction string, hookData interface{}) (*models.HookDelivery, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model, err := models.NewHookDelivery(collection, url, hookType, hookAction, hookData)
+	if err != nil {
+		return nil, err
+	}
+
+	model.NextAttempt = time.Now().Add(hookRetryDelay()).Unix()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).Insert(model)
+
+	return model, dbErr
+}
+
+// Send creates a new hook delivery and makes the first delivery attempt.
+// Does nothing if `url` is empty.
+func (dao *HookDeliveryDAO) Send(collection *models.Collection, url string, hookType string, hookAction string, hookData interface{}) error {
+	if url == "" {
+		return nil
+	}
+
+	model, err := dao.Create(collection, url, hookType, hookAction, hookData)
+	if err != nil {
+		return err
+	}
+
+	return dao.Deliver(model)
+}
+
+// Deliver sends the provided hook delivery payload and stores the attempt result.
+// The payload is signed together with the attempt timestamp using the current collection hooks secret (if any).
+// The returned error is related only to the db read/write (the send error is stored as part of the attempt).
+func (dao *HookDeliveryDAO) Deliver(model *models.HookDelivery) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	secret, secretErr := dao.hookSecret(model.CollectionID)
+	if secretErr != nil {
+		return secretErr
+	}
+
+	timestamp := time.Now().Unix()
+
+	headers := map[string]string{
+		utils.HookDeliveryHeader:  model.ID.Hex(),
+		utils.HookTimestampHeader: strconv.FormatInt(timestamp, 10),
+	}
+	if secret != "" {
+		headers[utils.HookSignatureHeader] = utils.SignHookPayload(secret, timestamp, []byte(model.Payload))
+	}
+
+	timeout := time.Duration(app.Config.GetInt("hooks.timeout")) * time.Second
+
+	code, sendErr := utils.SendHookPayload(model.Url, []byte(model.Payload), headers, timeout)
+
+	model.AddAttempt(code, sendErr, app.Config.GetInt("hooks.maxAttempts"), hookRetryDelay())
+
+	// db write
+	return session.DB("").C(dao.Collection).UpdateId(model.ID, model)
+}
+
+// DeliverPending retries up to `limit` pending hook deliveries with elapsed next attempt time.
+// Each delivery is claimed atomically before being sent (see `claimPending()`), so that
+// multiple app instances could run the delivery worker without sending the same hook twice.
+// Returns the number of the processed deliveries.
+func (dao *HookDeliveryDAO) DeliverPending(limit int) (int, error) {
+	processed := 0
+
+	for processed < limit {
+		model, err := dao.claimPending()
+		if err == mgo.ErrNotFound {
+			break
+		}
+
+		if err != nil {
+			return processed, err
+		}
+
+		dao.Deliver(model)
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+// claimPending atomically leases a single pending hook delivery with elapsed next attempt time
+// by postponing its next attempt (in case the delivery process is interrupted, it will be retried
+// after the lease expires). Returns `mgo.ErrNotFound` if there are no more deliveries to claim.
+func (dao *HookDeliveryDAO) claimPending() (*models.HookDelivery, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	now := time.Now()
+
+	// the lease should outlast a single delivery attempt
+	lease := time.Duration(app.Config.GetInt("hooks.timeout"))*time.Second + hookRetryDelay()
+
+	model := &models.HookDelivery{}
+
+	change := mgo.Change{
+		Update:    bson.M{"$set": bson.M{"next_attempt": now.Add(lease).Unix()}},
+		ReturnNew: true,
+	}
+
+	_, err := session.DB("").C(dao.Collection).
+		Find(bson.M{
+			"status":       models.HookDeliveryStatusPending,
+			"next_attempt": bson.M{"$lte": now.Unix()},
+		}).
+		Sort("next_attempt").
+		Apply(change, model)
+
+	return model, err
+}
+
+// DeleteAll deletes all hook deliveries by the provided conditions.
+func (dao *HookDeliveryDAO) DeleteAll(conditions bson.M) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).RemoveAll(conditions)
+
+	return err
+}
+
+// hookSecret returns the current hooks secret of the provided collection
+// (empty string if the collection no longer exists).
+func (dao *HookDeliveryDAO) hookSecret(collectionID bson.ObjectId) (string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	collection := &models.Collection{}
+
+	err := session.DB("").C("collection").
+		FindId(collectionID).
+		Select(bson.M{"hook_secret": 1}).
+		One(collection)
+
+	if err == mgo.ErrNotFound {
+		return "", nil
+	}
+
+	return collection.HookSecret, err
+}
+
+// hookRetryDelay returns the configured initial hook delivery retry delay.
+func hookRetryDelay() time.Duration {
+	return time.Duration(app.Config.GetInt("hooks.retryDelay")) * time.Second
+}
@@ -0,0 +1,330 @@
+package daos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewHookDeliveryDAO(t *testing.T) {
+	dao := NewHookDeliveryDAO(TestSession)
+
+	if dao == nil {
+		t.Error("Expected HookDeliveryDAO pointer, got nil")
+	}
+
+	if dao.Collection != "hook_delivery" {
+		t.Error("Expected hook_delivery collection, got ", dao.Collection)
+	}
+}
+
+func TestHookDeliveryDAO_Count(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions bson.M
+		Expected   int
+	}{
+		{nil, 3},
+		{bson.M{"status": "missing"}, 0},
+		{bson.M{"collection_id": bson.ObjectIdHex("5a833090e1382351eaad3732")}, 2},
+		{bson.M{"status": models.HookDeliveryStatusPending}, 1},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.Count(scenario.Conditions)
+		if result != scenario.Expected {
+			t.Errorf("Expected %d, got %d (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestHookDeliveryDAO_GetList(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions    bson.M
+		Sort          []string
+		Limit         int
+		Offset        int
+		ExpectedCount int
+		ExpectedOrder []string
+	}{
+		{nil, nil, 10, 0, 3, nil},
+		{nil, nil, 10, 1, 2, nil},
+		{bson.M{"status": "missing"}, nil, 10, 0, 0, nil},
+		{bson.M{"collection_id": bson.ObjectIdHex("5a833090e1382351eaad3732")}, []string{"created"}, 10, 0, 2, []string{"5b2a1c3fe138231f4c5d9e01", "5b2a1c3fe138231f4c5d9e02"}},
+		{bson.M{"collection_id": bson.ObjectIdHex("5a833090e1382351eaad3732")}, []string{"-created"}, 10, 0, 2, []string{"5b2a1c3fe138231f4c5d9e02", "5b2a1c3fe138231f4c5d9e01"}},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.GetList(scenario.Limit, scenario.Offset, scenario.Conditions, scenario.Sort)
+		if len(result) != scenario.ExpectedCount {
+			t.Fatalf("Expected %d items, got %d (scenario %v)", scenario.ExpectedCount, len(result), scenario)
+		}
+
+		if scenario.ExpectedOrder != nil {
+			for i, id := range scenario.ExpectedOrder {
+				if result[i].ID.Hex() != id {
+					t.Fatalf("Invalid order - expected %s to be at position %d (scenario %v)", id, i, scenario)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestHookDeliveryDAO_GetOne(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions  bson.M
+		ExpectError bool
+		ExpectedID  string
+	}{
+		{bson.M{"status": "missing"}, true, ""},
+		{bson.M{"status": models.HookDeliveryStatusSuccess}, false, "5b2a1c3fe138231f4c5d9e02"},
+	}
+
+	for _, scenario := range testScenarios {
+		item, err := dao.GetOne(scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if item.ID.Hex() != scenario.ExpectedID {
+			t.Errorf("Expected hook delivery with %s id, got %s (scenario %v)", scenario.ExpectedID, item.ID.Hex(), scenario)
+		}
+	}
+}
+
+func TestHookDeliveryDAO_GetByID(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		Conditions  bson.M
+		ExpectError bool
+	}{
+		{"invalid", nil, true},
+		{"5b2a1c3fe138231f4c5d9e01", bson.M{"collection_id": bson.ObjectIdHex("5a8b32d4e13823769a18bc1c")}, true},
+		{"5b2a1c3fe138231f4c5d9e01", bson.M{"collection_id": bson.ObjectIdHex("5a833090e1382351eaad3732")}, false},
+		{"5b2a1c3fe138231f4c5d9e01", nil, false},
+	}
+
+	for _, scenario := range testScenarios {
+		item, err := dao.GetByID(scenario.ID, scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if !scenario.ExpectError && item.ID.Hex() != scenario.ID {
+			t.Errorf("Expected hook delivery with %s id, got %s (scenario %v)", scenario.ID, item.ID.Hex(), scenario)
+		}
+	}
+}
+
+func TestHookDeliveryDAO_Create(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	collection := &models.Collection{ID: bson.ObjectIdHex("5a833090e1382351eaad3732"), HookSecret: "test_secret"}
+
+	model, err := dao.Create(collection, "http://test.dev", utils.HookTypeEntity, utils.HookActionCreate, nil)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, fetchErr := dao.GetByID(model.ID.Hex())
+	if fetchErr != nil {
+		t.Fatal("Expected the delivery to be stored, got error", fetchErr)
+	}
+
+	if stored.Status != models.HookDeliveryStatusPending {
+		t.Error("Expected pending delivery, got", stored)
+	}
+
+	// should be picked up by the retry worker only if the immediate delivery fails to complete
+	if stored.NextAttempt <= time.Now().Unix() {
+		t.Error("Expected the first worker attempt to be delayed, got", stored.NextAttempt)
+	}
+}
+
+func TestHookDeliveryDAO_Send(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	collection := &models.Collection{ID: bson.ObjectIdHex("5a833090e1382351eaad3732"), HookSecret: "test_secret"}
+
+	TestSession.DB("").C("collection").UpdateId(collection.ID, bson.M{"$set": bson.M{"hook_secret": collection.HookSecret}})
+
+	hooksCount := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hooksCount++
+
+		timestamp, _ := strconv.ParseInt(r.Header.Get(utils.HookTimestampHeader), 10, 64)
+		if timestamp < time.Now().Unix()-5 {
+			t.Error("Expected the delivery attempt timestamp header to be set, got", r.Header.Get(utils.HookTimestampHeader))
+		}
+
+		// the payload should be signed with the current collection secret
+		expectedSignature := utils.SignHookPayload(collection.HookSecret, timestamp, []byte(`{"type":"entity","action":"create","data":"test"}`))
+		if r.Header.Get(utils.HookSignatureHeader) != expectedSignature {
+			t.Errorf("Expected %s signature, got %s", expectedSignature, r.Header.Get(utils.HookSignatureHeader))
+		}
+
+		if r.Header.Get(utils.HookDeliveryHeader) == "" {
+			t.Error("Expected delivery header to be set")
+		}
+	}))
+	defer ts.Close()
+
+	// empty url
+	if err := dao.Send(collection, "", utils.HookTypeEntity, utils.HookActionCreate, "test"); err != nil {
+		t.Error("Expected nil, got error", err)
+	}
+
+	if err := dao.Send(collection, ts.URL, utils.HookTypeEntity, utils.HookActionCreate, "test"); err != nil {
+		t.Error("Expected nil, got error", err)
+	}
+
+	if hooksCount != 1 {
+		t.Fatalf("Expected 1 hook to be sent, got %d", hooksCount)
+	}
+
+	count, _ := dao.Count(bson.M{"url": ts.URL, "status": models.HookDeliveryStatusSuccess})
+	if count != 1 {
+		t.Errorf("Expected 1 successful delivery to be stored, got %d", count)
+	}
+
+	// redelivery after the collection secret change
+	collection.HookSecret = "new_test_secret"
+	TestSession.DB("").C("collection").UpdateId(collection.ID, bson.M{"$set": bson.M{"hook_secret": collection.HookSecret}})
+
+	delivery, _ := dao.GetOne(bson.M{"url": ts.URL})
+	if err := dao.Deliver(delivery); err != nil {
+		t.Error("Expected nil, got error", err)
+	}
+
+	if hooksCount != 2 {
+		t.Errorf("Expected 2 hooks to be sent, got %d", hooksCount)
+	}
+}
+
+func TestHookDeliveryDAO_Deliver(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	model, _ := dao.GetByID("5b2a1c3fe138231f4c5d9e03")
+	model.Url = ts.URL
+
+	if err := dao.Deliver(model); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, _ := dao.GetByID("5b2a1c3fe138231f4c5d9e03")
+
+	if len(stored.Attempts) != 2 || stored.Attempts[1].Code != http.StatusServiceUnavailable {
+		t.Fatal("Expected the failed attempt to be stored, got", stored.Attempts)
+	}
+
+	if stored.Status != models.HookDeliveryStatusPending || stored.NextAttempt <= stored.Attempts[1].Created {
+		t.Error("Expected the delivery to be rescheduled, got", stored)
+	}
+}
+
+func TestHookDeliveryDAO_DeliverPending(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	hooksCount := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hooksCount++
+	}))
+	defer ts.Close()
+
+	dao.Session.DB("").C(dao.Collection).UpdateAll(nil, bson.M{"$set": bson.M{"url": ts.URL}})
+
+	// not elapsed next attempt
+	collection := &models.Collection{ID: bson.ObjectIdHex("5a833090e1382351eaad3732")}
+	dao.Create(collection, ts.URL, utils.HookTypeEntity, utils.HookActionCreate, nil)
+
+	processed, err := dao.DeliverPending(10)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if processed != 1 || hooksCount != 1 {
+		t.Fatalf("Expected 1 processed delivery, got %d (sent %d)", processed, hooksCount)
+	}
+
+	stored, _ := dao.GetByID("5b2a1c3fe138231f4c5d9e03")
+	if stored.Status != models.HookDeliveryStatusSuccess {
+		t.Error("Expected the pending delivery to be sent, got", stored)
+	}
+
+	// already claimed delivery (eg. by another app instance)
+	dao.Session.DB("").C(dao.Collection).UpdateId(bson.ObjectIdHex("5b2a1c3fe138231f4c5d9e03"), bson.M{"$set": bson.M{
+		"status":       models.HookDeliveryStatusPending,
+		"next_attempt": time.Now().Add(time.Minute).Unix(),
+	}})
+
+	processed2, err2 := dao.DeliverPending(10)
+	if err2 != nil || processed2 != 0 || hooksCount != 1 {
+		t.Errorf("Expected no processed deliveries and nil error, got %d (%v)", processed2, err2)
+	}
+}
+
+func TestHookDeliveryDAO_DeleteAll(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewHookDeliveryDAO(TestSession)
+
+	dao.DeleteAll(bson.M{"collection_id": bson.ObjectIdHex("5a833090e1382351eaad3732")})
+
+	if total, _ := dao.Count(nil); total != 1 {
+		t.Error("Expected 1 remaining delivery, got", total)
+	}
+}
@@ -2,6 +2,7 @@ package daos
 
 import (
 	"errors"
+	"time"
 
 	"github.com/gofreta/gofreta-api/models"
 
@@ -133,6 +134,10 @@ func (dao *KeyDAO) Create(form *models.KeyForm) (*models.Key, error) {
 		return &models.Key{}, validateErr
 	}
 
+	if rolesErr := NewRoleDAO(session).validateRoles(form.Roles); rolesErr != nil {
+		return &models.Key{}, rolesErr
+	}
+
 	model := form.ResolveModel()
 
 	// db write
@@ -152,6 +157,10 @@ func (dao *KeyDAO) Update(form *models.KeyForm) (*models.Key, error) {
 		return &models.Key{}, validateErr
 	}
 
+	if rolesErr := NewRoleDAO(session).validateRoles(form.Roles); rolesErr != nil {
+		return &models.Key{}, rolesErr
+	}
+
 	model := form.ResolveModel()
 
 	// db write
@@ -171,6 +180,40 @@ func (dao *KeyDAO) Delete(model *models.Key) error {
 	return deleteErr
 }
 
+// Rotate issues a new token for the provided Key model and keeps
+// the previous one valid for the specified grace period (in seconds).
+func (dao *KeyDAO) Rotate(model *models.Key, grace int64) (*models.Key, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	rotatedModel := *model
+
+	if tokenErr := rotatedModel.RotateToken(grace); tokenErr != nil {
+		return &models.Key{}, tokenErr
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(rotatedModel.ID, bson.M{"$set": bson.M{
+		"token":                  rotatedModel.Token,
+		"previous_token":         rotatedModel.PreviousToken,
+		"previous_token_expires": rotatedModel.PreviousTokenExpires,
+		"modified":               rotatedModel.Modified,
+	}})
+
+	return &rotatedModel, dbErr
+}
+
+// UpdateLastUsed sets the last used timestamp of the provided Key model to the current time.
+func (dao *KeyDAO) UpdateLastUsed(model *models.Key) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.LastUsed = time.Now().Unix()
+
+	// db write
+	return session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{"last_used": model.LastUsed}})
+}
+
 // SetAccessGroup sets new access group to all available keys.
 func (dao *KeyDAO) SetAccessGroup(group string, actions ...string) error {
 	session := dao.Session.Copy()
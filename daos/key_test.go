@@ -178,18 +178,22 @@ func TestKeyDAO_Create(t *testing.T) {
 	testScenarios := []struct {
 		Title       string
 		Access      map[string][]string
+		Roles       []bson.ObjectId
 		ExpectError bool
 	}{
-		{"", nil, true},
-		{"Test", nil, true},
-		{"", map[string][]string{"test": []string{"index", "view"}}, true},
-		{"Test", map[string][]string{"test": []string{"index", "view"}}, false},
+		{"", nil, nil, true},
+		{"Test", nil, nil, true},
+		{"", map[string][]string{"test": []string{"index", "view"}}, nil, true},
+		{"Test", map[string][]string{"test": []string{"index", "view"}}, []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e009")}, true},
+		{"Test", map[string][]string{"test": []string{"index", "view"}}, nil, false},
+		{"Test", map[string][]string{"test": []string{"index", "view"}}, []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")}, false},
 	}
 
 	for _, scenario := range testScenarios {
 		form := &models.KeyForm{
 			Title:  scenario.Title,
 			Access: scenario.Access,
+			Roles:  scenario.Roles,
 		}
 
 		createdModel, err := dao.Create(form)
@@ -315,6 +319,70 @@ func TestKeyDAO_Delete(t *testing.T) {
 	}
 }
 
+func TestKeyDAO_Rotate(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewKeyDAO(TestSession)
+
+	originalModel, _ := dao.GetByID("5a75ee63e1382336728c2add")
+
+	rotatedModel, err := dao.Rotate(originalModel, 3600)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if rotatedModel.Token == originalModel.Token {
+		t.Error("Expected the token to be changed")
+	}
+
+	// ensure that the changes were persisted and the access data was kept
+	model, _ := dao.GetByID("5a75ee63e1382336728c2add")
+
+	if model.Token != rotatedModel.Token {
+		t.Errorf("Expected %s token, got %s", rotatedModel.Token, model.Token)
+	}
+
+	if model.PreviousToken != originalModel.Token || !model.IsValidToken(originalModel.Token) {
+		t.Errorf("Expected the original token to be valid during the grace period, got %v", model)
+	}
+
+	if len(model.Access) != len(originalModel.Access) {
+		t.Errorf("Expected the access data to be kept, got %v", model.Access)
+	}
+
+	// rotate without grace period
+	dao.Rotate(model, 0)
+
+	model, _ = dao.GetByID("5a75ee63e1382336728c2add")
+	if model.IsValidToken(rotatedModel.Token) {
+		t.Error("Expected the rotated token to be invalid")
+	}
+}
+
+func TestKeyDAO_UpdateLastUsed(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewKeyDAO(TestSession)
+
+	model, _ := dao.GetByID("5a75ee63e1382336728c2add")
+
+	if err := dao.UpdateLastUsed(model); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	updatedModel, _ := dao.GetByID("5a75ee63e1382336728c2add")
+	if updatedModel.LastUsed == 0 || updatedModel.LastUsed != model.LastUsed {
+		t.Errorf("Expected %d last used timestamp, got %d", model.LastUsed, updatedModel.LastUsed)
+	}
+
+	// nonexisting model
+	if err := dao.UpdateLastUsed(&models.Key{ID: bson.ObjectIdHex("5a896174f69744822caee83c")}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
 func TestKeyDAO_SetAccessGroup(t *testing.T) {
 	fixtures.InitFixtures(TestSession)
 	defer fixtures.CleanFixtures(TestSession)
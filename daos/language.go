@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
@@ -127,6 +128,23 @@ func (dao *LanguageDAO) GetByLocale(locale string, additionalConditions ...bson.
 	return dao.GetOne(conditions)
 }
 
+// GetFallbackChain returns the locale fallback chain of the language with the specified locale
+// (see `models.Language.FallbackChain()`).
+func (dao *LanguageDAO) GetFallbackChain(locale string) ([]string, error) {
+	languages, err := dao.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lang := range languages {
+		if lang.Locale == locale {
+			return lang.FallbackChain(languages), nil
+		}
+	}
+
+	return nil, errors.New("Language with locale '" + locale + "' doesn't exist.")
+}
+
 // GetByID returns single language model by its id.
 func (dao *LanguageDAO) GetByID(id string, additionalConditions ...bson.M) (*models.Language, error) {
 	if !bson.IsObjectIdHex(id) {
@@ -159,6 +177,10 @@ func (dao *LanguageDAO) Create(form *models.LanguageForm) (*models.Language, err
 		return &models.Language{}, validateErr
 	}
 
+	if fallbackErr := dao.validateFallback(form); fallbackErr != nil {
+		return &models.Language{}, fallbackErr
+	}
+
 	model := form.ResolveModel()
 
 	// db write
@@ -172,6 +194,10 @@ func (dao *LanguageDAO) Create(form *models.LanguageForm) (*models.Language, err
 		}
 	}
 
+	if dbErr == nil {
+		NewEntityDAO(session).EnsureGeoIndexes()
+	}
+
 	return model, dbErr
 }
 
@@ -188,16 +214,26 @@ func (dao *LanguageDAO) Update(form *models.LanguageForm) (*models.Language, err
 		return &models.Language{}, validateErr
 	}
 
+	if fallbackErr := dao.validateFallback(form); fallbackErr != nil {
+		return &models.Language{}, fallbackErr
+	}
+
 	model := form.ResolveModel()
 
 	// db write
 	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
 
 	// @todo add some sort of transaction support
-	// update entities related data
+	// update entities and other languages related data
 	if dbErr == nil && oldLocale != model.Locale {
 		entityDAO := NewEntityDAO(session)
 		entityDAO.RenameDataLocale(oldLocale, model.Locale)
+		entityDAO.EnsureGeoIndexes()
+
+		session.DB("").C(dao.Collection).UpdateAll(
+			bson.M{"fallback": oldLocale},
+			bson.M{"$set": bson.M{"fallback.$": model.Locale}},
+		)
 	}
 
 	return model, dbErr
@@ -221,7 +257,49 @@ func (dao *LanguageDAO) Delete(model *models.Language) error {
 	if deleteErr == nil {
 		entityDAO := NewEntityDAO(session)
 		entityDAO.RemoveDataLocale(model.Locale)
+
+		session.DB("").C(dao.Collection).UpdateAll(
+			bson.M{"fallback": model.Locale},
+			bson.M{"$pull": bson.M{"fallback": model.Locale}},
+		)
 	}
 
 	return deleteErr
 }
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// validateFallback checks whether all form fallback locales belong to existing languages.
+func (dao *LanguageDAO) validateFallback(form *models.LanguageForm) error {
+	if len(form.Fallback) == 0 {
+		return nil
+	}
+
+	languages, err := dao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, locale := range form.Fallback {
+		exist := false
+
+		for _, lang := range languages {
+			// the form model locale could be changed
+			if lang.Locale == locale && (form.Model == nil || lang.ID != form.Model.ID) {
+				exist = true
+
+				break
+			}
+		}
+
+		if !exist {
+			return utils.NewDataError(map[string]interface{}{
+				"fallback": "Language with locale '" + locale + "' doesn't exist.",
+			})
+		}
+	}
+
+	return nil
+}
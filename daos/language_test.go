@@ -1,6 +1,7 @@
 package daos
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/gofreta/gofreta-api/fixtures"
@@ -218,17 +219,20 @@ func TestLanguageDAO_Create(t *testing.T) {
 	testScenarios := []struct {
 		Locale      string
 		Title       string
+		Fallback    []string
 		ExpectError bool
 	}{
-		{"", "", true},
-		{"invalid locale", "Title", true},
-		{"valid_locale", "Title", false},
+		{"", "", nil, true},
+		{"invalid locale", "Title", nil, true},
+		{"valid_locale", "Title", []string{"missing"}, true},
+		{"valid_locale", "Title", []string{"bg", "en"}, false},
 	}
 
 	for _, scenario := range testScenarios {
 		form := &models.LanguageForm{
-			Locale: scenario.Locale,
-			Title:  scenario.Title,
+			Locale:   scenario.Locale,
+			Title:    scenario.Title,
+			Fallback: scenario.Fallback,
 		}
 
 		createdModel, err := dao.Create(form)
@@ -251,6 +255,10 @@ func TestLanguageDAO_Create(t *testing.T) {
 			t.Errorf("Expected %s title, got %s (scenario %v)", scenario.Title, createdModel.Title, scenario)
 		}
 
+		if !reflect.DeepEqual(createdModel.Fallback, scenario.Fallback) {
+			t.Errorf("Expected %v fallback, got %v (scenario %v)", scenario.Fallback, createdModel.Fallback, scenario)
+		}
+
 		if createdModel.Modified <= 0 {
 			t.Error("Expected modified to be set")
 		}
@@ -272,18 +280,22 @@ func TestLanguageDAO_Update(t *testing.T) {
 	testScenarios := []struct {
 		Locale      string
 		Title       string
+		Fallback    []string
 		ExpectError bool
 	}{
-		{"", "", true},
-		{"invalid locale", "Title", true},
-		{"valid_locale", "Title", false},
+		{"", "", nil, true},
+		{"invalid locale", "Title", nil, true},
+		// the language old locale should not be accepted as fallback
+		{"valid_locale", "Title", []string{"bg"}, true},
+		{"valid_locale", "Title", []string{"de", "en"}, false},
 	}
 
 	for _, scenario := range testScenarios {
 		form := &models.LanguageForm{
-			Model:  originalModel,
-			Locale: scenario.Locale,
-			Title:  scenario.Title,
+			Model:    originalModel,
+			Locale:   scenario.Locale,
+			Title:    scenario.Title,
+			Fallback: scenario.Fallback,
 		}
 
 		updatedModel, err := dao.Update(form)
@@ -306,10 +318,26 @@ func TestLanguageDAO_Update(t *testing.T) {
 			t.Errorf("Expected %s title, got %s (scenario %v)", scenario.Title, updatedModel.Title, scenario)
 		}
 
+		if !reflect.DeepEqual(updatedModel.Fallback, scenario.Fallback) {
+			t.Errorf("Expected %v fallback, got %v (scenario %v)", scenario.Fallback, updatedModel.Fallback, scenario)
+		}
+
 		if updatedModel.Modified == originalModel.Modified {
 			t.Errorf("Expected modified date to be updated, got %d vs %d (scenario %v)", updatedModel.Modified, originalModel.Modified, scenario)
 		}
 	}
+
+	// changing the locale should update the other languages fallbacks
+	en, _ := dao.GetByLocale("en")
+	en.Fallback = []string{}
+	if _, err := dao.Update(&models.LanguageForm{Model: en, Locale: "en_new", Title: en.Title, Fallback: en.Fallback}); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	de, _ := dao.GetByLocale("de")
+	if !reflect.DeepEqual(de.Fallback, []string{"en_new"}) {
+		t.Errorf("Expected the de fallback to be renamed, got %v", de.Fallback)
+	}
 }
 
 func TestLanguageDAO_Delete(t *testing.T) {
@@ -325,9 +353,9 @@ func TestLanguageDAO_Delete(t *testing.T) {
 		// nonexisting model
 		{&models.Language{ID: bson.ObjectIdHex("5a896174f69744822caee83c")}, true},
 		// existing model
-		{&models.Language{ID: bson.ObjectIdHex("5a894a3ee138237565d4f7ce")}, false},
+		{&models.Language{ID: bson.ObjectIdHex("5a894a3ee138237565d4f7ce"), Locale: "en"}, false},
 		// existing model
-		{&models.Language{ID: bson.ObjectIdHex("5a89601d35eca6cea28f09c8")}, false},
+		{&models.Language{ID: bson.ObjectIdHex("5a89601d35eca6cea28f09c8"), Locale: "bg"}, false},
 		// existing model but because it is the only one left should return an error
 		{&models.Language{ID: bson.ObjectIdHex("5a8be9dfe1382310bec8076a")}, true},
 	}
@@ -351,4 +379,41 @@ func TestLanguageDAO_Delete(t *testing.T) {
 			t.Error("Expected the record to be deleted")
 		}
 	}
+
+	// ensure that the deleted locales were removed from the fallbacks
+	de, _ := dao.GetByLocale("de")
+	if len(de.Fallback) != 0 {
+		t.Errorf("Expected empty de fallback, got %v", de.Fallback)
+	}
+}
+
+func TestLanguageDAO_GetFallbackChain(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewLanguageDAO(TestSession)
+
+	testScenarios := []struct {
+		Locale      string
+		ExpectError bool
+		Expected    []string
+	}{
+		{"missing", true, nil},
+		{"en", false, []string{"en"}},
+		{"de", false, []string{"de", "en"}},
+	}
+
+	for _, scenario := range testScenarios {
+		result, err := dao.GetFallbackChain(scenario.Locale)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v chain, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
 }
@@ -189,12 +189,28 @@ func (dao *MediaDAO) Delete(model *models.Media) error {
 	session := dao.Session.Copy()
 	defer session.Close()
 
+	entityDAO := NewEntityDAO(session)
+
+	refs, refsErr := entityDAO.findReferenceFields(models.FieldTypeMedia, "")
+	if refsErr != nil {
+		return refsErr
+	}
+
+	if err := entityDAO.checkRestrictedReferences(refs, model.ID); err != nil {
+		return err
+	}
+
 	// delete the file
 	if err := model.DeleteFile(); err != nil {
 		return err
 	}
 
-	return session.DB("").C(dao.Collection).RemoveId(model.ID)
+	if err := session.DB("").C(dao.Collection).RemoveId(model.ID); err != nil {
+		return err
+	}
+
+	// apply the referencing media fields on delete policies
+	return entityDAO.releaseReferences(refs, model.ID, models.RevisionAuthor{})
 }
 
 // -------------------------------------------------------------------
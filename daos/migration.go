@@ -0,0 +1,201 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// migration defines a single one-time db upgrade step.
+type migration struct {
+	Name string
+	Up   func(session *mgo.Session) error
+}
+
+// migrationEntry defines the stored state of a single applied (or running) migration.
+type migrationEntry struct {
+	Name    string `bson:"_id"`
+	Applied int64  `bson:"applied"`
+	Created int64  `bson:"created"`
+}
+
+// migrations lists all db upgrade steps in the order they should be applied.
+var migrations = []migration{
+	{"entity_search_text", migrateEntitySearchText},
+	{"entity_shared_data", migrateEntitySharedData},
+	{"role_access_group", migrateRoleAccessGroup},
+	{"user_session_expire_at", migrateUserSessionExpireAt},
+}
+
+// MigrationDAO applies and keeps track of the one-time db upgrade steps.
+type MigrationDAO struct {
+	Session    *mgo.Session
+	Collection string
+}
+
+// NewMigrationDAO creates a new MigrationDAO.
+func NewMigrationDAO(session *mgo.Session) *MigrationDAO {
+	return &MigrationDAO{
+		Session:    session,
+		Collection: "migration",
+	}
+}
+
+// Run applies all registered migrations that are not applied yet and
+// returns the names of the applied ones. Each migration is claimed before
+// it is applied, so that it runs only once in multi-instance deployments.
+// A failed migration is released and will be retried on the next run.
+func (dao *MigrationDAO) Run() ([]string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	applied := []string{}
+
+	for _, m := range migrations {
+		entry := &migrationEntry{Name: m.Name, Created: time.Now().Unix()}
+
+		if err := c.Insert(entry); err != nil {
+			// already applied (or applying) by another run
+			if mgo.IsDup(err) {
+				continue
+			}
+
+			return applied, err
+		}
+
+		if err := m.Up(session); err != nil {
+			c.RemoveId(m.Name)
+
+			return applied, err
+		}
+
+		if err := c.UpdateId(m.Name, bson.M{"$set": bson.M{"applied": time.Now().Unix()}}); err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, m.Name)
+	}
+
+	return applied, nil
+}
+
+// -------------------------------------------------------------------
+// • Migrations
+// -------------------------------------------------------------------
+
+// migrateEntitySearchText builds the full-text search values of the entities
+// stored before the searchable fields were introduced.
+func migrateEntitySearchText(session *mgo.Session) error {
+	collections, err := NewCollectionDAO(session).GetList(0, 0, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	entityDAO := NewEntityDAO(session)
+
+	for i, _ := range collections {
+		if err := entityDAO.RefreshSearchTexts(&collections[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateEntitySharedData moves the non-multilingual field values of the entities
+// stored before the shared data group was introduced from their locale groups into the shared one.
+// The fields with different locale values in any of the collection entities are marked
+// as multilingual instead, so that none of the existing values is lost.
+func migrateEntitySharedData(session *mgo.Session) error {
+	collectionDAO := NewCollectionDAO(session)
+
+	collections, err := collectionDAO.GetList(0, 0, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	entityDAO := NewEntityDAO(session)
+
+	for i, _ := range collections {
+		collection := &collections[i]
+
+		shared := []models.CollectionField{}
+		for _, field := range collection.Fields {
+			if !field.Multilingual {
+				shared = append(shared, field)
+			}
+		}
+
+		conflicts, conflictsErr := entityDAO.SharedDataConflicts(collection.ID, shared)
+		if conflictsErr != nil {
+			return conflictsErr
+		}
+
+		if len(conflicts) > 0 {
+			for j, field := range collection.Fields {
+				if utils.StringInSlice(field.Key, conflicts) {
+					collection.Fields[j].Multilingual = true
+				}
+			}
+
+			updateErr := session.DB("").C(collectionDAO.Collection).UpdateId(collection.ID, bson.M{
+				"$set": bson.M{"fields": collection.Fields},
+			})
+			if updateErr != nil {
+				return updateErr
+			}
+		}
+
+		diff := &models.SchemaDiff{Multilingual: []string{}}
+		for _, field := range collection.Fields {
+			if !field.Multilingual {
+				diff.Multilingual = append(diff.Multilingual, field.Key)
+			}
+		}
+
+		if err := entityDAO.MigrateSchema(collection, diff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateRoleAccessGroup grants the role access group to all users
+// that have access to the users management (aka. the admins before the roles were introduced).
+func migrateRoleAccessGroup(session *mgo.Session) error {
+	_, err := session.DB("").C("user").UpdateAll(
+		bson.M{
+			"access.user": bson.M{"$exists": true, "$ne": []string{}},
+			"access.role": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"access.role": []string{"index", "view", "create", "update", "delete"}}},
+	)
+
+	return err
+}
+
+// migrateUserSessionExpireAt sets the auto removal date of the user sessions
+// stored before the expired sessions cleanup was introduced.
+func migrateUserSessionExpireAt(session *mgo.Session) error {
+	c := session.DB("").C("user_session")
+
+	item := &models.UserSession{}
+
+	iter := c.Find(bson.M{"expire_at": bson.M{"$exists": false}}).Iter()
+	for iter.Next(item) {
+		if err := c.UpdateId(item.ID, bson.M{"$set": bson.M{"expire_at": time.Unix(item.Expires, 0)}}); err != nil {
+			iter.Close()
+
+			return err
+		}
+	}
+
+	return iter.Close()
+}
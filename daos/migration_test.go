@@ -0,0 +1,170 @@
+package daos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewMigrationDAO(t *testing.T) {
+	dao := NewMigrationDAO(TestSession)
+
+	if dao == nil {
+		t.Fatal("Expected MigrationDAO pointer, got nil")
+	}
+
+	if dao.Collection != "migration" {
+		t.Error("Expected migration collection, got ", dao.Collection)
+	}
+}
+
+func TestMigrationDAO_Run(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewMigrationDAO(TestSession)
+
+	applied, err := dao.Run()
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if len(applied) != len(migrations) {
+		t.Fatalf("Expected %d applied migrations, got %v", len(migrations), applied)
+	}
+
+	for _, m := range migrations {
+		entry := &migrationEntry{}
+		if err := TestSession.DB("").C(dao.Collection).FindId(m.Name).One(entry); err != nil || entry.Applied <= 0 {
+			t.Errorf("Expected migration %s to be stored as applied, got %v (%v)", m.Name, entry, err)
+		}
+	}
+
+	// already applied
+	applied2, err2 := dao.Run()
+	if err2 != nil || len(applied2) != 0 {
+		t.Errorf("Expected no applied migrations and nil error, got %v (%v)", applied2, err2)
+	}
+}
+
+func TestMigrateEntitySearchText(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	entityDAO := NewEntityDAO(TestSession)
+
+	// entities stored before the search support
+	TestSession.DB("").C(entityDAO.Collection).UpdateAll(nil, bson.M{"$unset": bson.M{"search_text": 1}})
+
+	if err := migrateEntitySearchText(TestSession); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	entity, _ := entityDAO.GetByID("5a8bea3ae1382310bec8076b")
+	if len(entity.SearchText) != 3 || entity.SearchText[2] != "Test 1 title en" {
+		t.Error("Expected the entity search text to be rebuilt, got", entity.SearchText)
+	}
+
+	results, _ := entityDAO.Search("title", 10, 0, bson.M{"collection_id": entity.CollectionID})
+	if len(results) != 2 {
+		t.Error("Expected 2 search results, got", len(results))
+	}
+}
+
+func TestMigrateEntitySharedData(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	if err := migrateEntitySharedData(TestSession); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	// the fields with different locale values should be marked as multilingual
+	collection, _ := NewCollectionDAO(TestSession).GetByID("5a8b32d4e13823769a18bc1c")
+	for _, field := range collection.Fields {
+		expected := utils.StringInSlice(field.Key, []string{"title", "release", "files"})
+
+		if field.Multilingual != expected {
+			t.Errorf("Expected field %s multilingual flag to be %v", field.Key, expected)
+		}
+	}
+
+	entity, _ := NewEntityDAO(TestSession).GetByID("5a8beaa2e1382310bec8076d")
+
+	shared := entity.Data[models.EntitySharedDataKey]
+	for _, key := range []string{"description", "variants", "published", "options", "rels"} {
+		if _, ok := shared[key]; !ok {
+			t.Errorf("Expected %s to be moved in the shared group, got %v", key, shared)
+		}
+
+		for _, locale := range []string{"en", "bg", "de"} {
+			if _, ok := entity.Data[locale][key]; ok {
+				t.Errorf("Expected %s %s to be removed, got %v", locale, key, entity.Data[locale])
+			}
+		}
+	}
+
+	// none of the different locale values should be lost
+	if fmt.Sprint(entity.Data["en"]["release"]) != "1" || fmt.Sprint(entity.Data["de"]["release"]) != "1519119010" {
+		t.Errorf("Expected the locale release values to be kept, got %v", entity.Data)
+	}
+
+	if fmt.Sprint(entity.Data["de"]["files"]) != "[5a7c9378e138230137212eb5]" {
+		t.Errorf("Expected the de files to be kept, got %v", entity.Data["de"]["files"])
+	}
+}
+
+func TestMigrateRoleAccessGroup(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	userDAO := NewUserDAO(TestSession)
+
+	// users stored before the roles support
+	TestSession.DB("").C(userDAO.Collection).UpdateAll(nil, bson.M{"$unset": bson.M{"access.role": 1}})
+	TestSession.DB("").C(userDAO.Collection).UpdateId(
+		bson.ObjectIdHex("5a8a99f0e138230ecd915d37"),
+		bson.M{"$unset": bson.M{"access.user": 1}},
+	)
+
+	if err := migrateRoleAccessGroup(TestSession); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	users, _ := userDAO.GetList(0, 0, nil, nil)
+	for _, user := range users {
+		_, hasUser := user.Access["user"]
+		_, hasRole := user.Access["role"]
+
+		if hasUser != hasRole {
+			t.Errorf("Expected user %s role access to be %v, got %v", user.ID.Hex(), hasUser, user.Access)
+		}
+	}
+}
+
+func TestMigrateUserSessionExpireAt(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	if err := migrateUserSessionExpireAt(TestSession); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	sessions := []models.UserSession{}
+	TestSession.DB("").C("user_session").Find(nil).All(&sessions)
+
+	if len(sessions) == 0 {
+		t.Fatal("Expected at least one user session")
+	}
+
+	for _, session := range sessions {
+		if session.ExpireAt.Unix() != session.Expires {
+			t.Errorf("Expected session %s expire_at to match its expires, got %v", session.ID.Hex(), session.ExpireAt)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// rateLimitEntry defines the stored attempts state of a single rate limit key.
+type rateLimitEntry struct {
+	Key           string    `bson:"_id"`
+	Count         int       `bson:"count"`
+	WindowExpires int64     `bson:"window_expires"`
+	LockedUntil   int64     `bson:"locked_until"`
+	ExpireAt      time.Time `bson:"expire_at"`
+}
+
+// RateLimitDAO is a db backed `utils.RateLimiter` implementation
+// that shares the attempts state between multiple app instances.
+type RateLimitDAO struct {
+	Session    *mgo.Session
+	Collection string
+	Options    utils.RateLimitOptions
+}
+
+// ensureIndexes makes sure that the required db indexes and constraints are set.
+func (dao *RateLimitDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	// auto remove the expired entries
+	index := mgo.Index{
+		Key:         []string{"expire_at"},
+		Background:  true,
+		ExpireAfter: time.Second,
+	}
+
+	if err := c.EnsureIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+// NewRateLimitDAO creates a new RateLimitDAO.
+func NewRateLimitDAO(session *mgo.Session, options utils.RateLimitOptions) *RateLimitDAO {
+	dao := &RateLimitDAO{
+		Session:    session,
+		Collection: "rate_limit",
+		Options:    options,
+	}
+
+	dao.ensureIndexes()
+
+	return dao
+}
+
+// Check implements `utils.RateLimiter.Check`.
+func (dao *RateLimitDAO) Check(key string) (time.Duration, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	entry := &rateLimitEntry{}
+
+	err := session.DB("").C(dao.Collection).FindId(key).One(entry)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rateLimitLockout(entry.LockedUntil, time.Now()), nil
+}
+
+// Hit implements `utils.RateLimiter.Hit`.
+func (dao *RateLimitDAO) Hit(key string) (time.Duration, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	now := time.Now()
+
+	// clear the expired window (the ttl index removal is not immediate)
+	c.Remove(bson.M{
+		"_id":            key,
+		"window_expires": bson.M{"$lte": now.Unix()},
+		"locked_until":   bson.M{"$lte": now.Unix()},
+	})
+
+	windowExpires := now.Add(dao.Options.Window)
+
+	change := mgo.Change{
+		Update: bson.M{
+			"$inc": bson.M{"count": 1},
+			"$setOnInsert": bson.M{
+				"window_expires": windowExpires.Unix(),
+				"locked_until":   int64(0),
+				"expire_at":      windowExpires,
+			},
+		},
+		Upsert:    true,
+		ReturnNew: true,
+	}
+
+	entry := &rateLimitEntry{}
+
+	_, err := c.FindId(key).Apply(change, entry)
+	if mgo.IsDup(err) {
+		// concurrent insert of the same key
+		_, err = c.FindId(key).Apply(change, entry)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if entry.Count >= dao.Options.MaxAttempts && entry.LockedUntil <= now.Unix() {
+		lockedUntil := now.Add(dao.Options.Lockout)
+
+		// start counting again after the lockout
+		err = c.UpdateId(key, bson.M{"$set": bson.M{
+			"count":          0,
+			"window_expires": lockedUntil.Unix(),
+			"locked_until":   lockedUntil.Unix(),
+			"expire_at":      lockedUntil,
+		}})
+
+		entry.LockedUntil = lockedUntil.Unix()
+	}
+
+	return rateLimitLockout(entry.LockedUntil, now), err
+}
+
+// Reset implements `utils.RateLimiter.Reset`.
+func (dao *RateLimitDAO) Reset(key string) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	err := session.DB("").C(dao.Collection).RemoveId(key)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// rateLimitLockout returns the remaining lockout duration until the `lockedUntil` unix timestamp.
+func rateLimitLockout(lockedUntil int64, now time.Time) time.Duration {
+	if lockedUntil <= now.Unix() {
+		return 0
+	}
+
+	return time.Unix(lockedUntil, 0).Sub(now)
+}
@@ -0,0 +1,93 @@
+package daos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/utils"
+)
+
+func TestNewRateLimitDAO(t *testing.T) {
+	dao := NewRateLimitDAO(TestSession, utils.RateLimitOptions{MaxAttempts: 3})
+
+	if dao == nil {
+		t.Error("Expected RateLimitDAO pointer, got nil")
+	}
+
+	if dao.Collection != "rate_limit" {
+		t.Error("Expected rate_limit collection, got ", dao.Collection)
+	}
+
+	if dao.Options.MaxAttempts != 3 {
+		t.Error("Expected 3 max attempts, got ", dao.Options.MaxAttempts)
+	}
+}
+
+func TestRateLimitDAO_Hit(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRateLimitDAO(TestSession, utils.RateLimitOptions{
+		MaxAttempts: 3,
+		Window:      time.Minute,
+		Lockout:     time.Hour,
+	})
+
+	for i := 1; i <= 2; i++ {
+		if lockout, err := dao.Hit("test"); lockout != 0 || err != nil {
+			t.Fatalf("Expected no lockout after %d attempts, got %v (error %v)", i, lockout, err)
+		}
+	}
+
+	if lockout, err := dao.Check("test"); lockout != 0 || err != nil {
+		t.Fatalf("Expected the key to not be locked, got %v (error %v)", lockout, err)
+	}
+
+	// limit reached
+	if lockout, err := dao.Hit("test"); lockout <= 59*time.Minute || lockout > time.Hour || err != nil {
+		t.Fatalf("Expected ~1h lockout, got %v (error %v)", lockout, err)
+	}
+
+	if lockout, _ := dao.Check("test"); lockout <= 59*time.Minute || lockout > time.Hour {
+		t.Fatalf("Expected the key to be locked, got %v", lockout)
+	}
+
+	// the attempts during the lockout shouldn't extend it
+	if lockout, _ := dao.Hit("test"); lockout > time.Hour {
+		t.Fatalf("Expected the lockout to not be extended, got %v", lockout)
+	}
+
+	// other keys shouldn't be affected
+	if lockout, _ := dao.Check("other"); lockout != 0 {
+		t.Fatalf("Expected the other key to not be locked, got %v", lockout)
+	}
+}
+
+func TestRateLimitDAO_Reset(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRateLimitDAO(TestSession, utils.RateLimitOptions{
+		MaxAttempts: 1,
+		Window:      time.Minute,
+		Lockout:     time.Hour,
+	})
+
+	// missing key
+	if err := dao.Reset("test"); err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if lockout, _ := dao.Hit("test"); lockout == 0 {
+		t.Fatal("Expected lockout, got 0")
+	}
+
+	if err := dao.Reset("test"); err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if lockout, _ := dao.Check("test"); lockout != 0 {
+		t.Fatalf("Expected the key lockout to be reset, got %v", lockout)
+	}
+}
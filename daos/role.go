@@ -0,0 +1,462 @@
+package daos
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// RoleDAO gets and persists role data in database.
+type RoleDAO struct {
+	Session         *mgo.Session
+	Collection      string
+	StampCollection string
+}
+
+// roleStampID is the id of the roles change stamp document.
+const roleStampID = "roles"
+
+// roleStamp defines the stored roles change stamp
+// (incremented on every role change from any app instance).
+type roleStamp struct {
+	ID      string `bson:"_id"`
+	Version int64  `bson:"version"`
+}
+
+// ensureIndexes makes sure that the required db indexes and constraints are set.
+func (dao *RoleDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	index := mgo.Index{
+		Key:        []string{"title"},
+		Unique:     true,
+		DropDups:   true,
+		Background: true,
+		Sparse:     true,
+	}
+
+	if err := c.EnsureIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+// NewRoleDAO creates a new RoleDAO.
+func NewRoleDAO(session *mgo.Session) *RoleDAO {
+	dao := &RoleDAO{
+		Session:         session,
+		Collection:      "role",
+		StampCollection: "role_stamp",
+	}
+
+	dao.ensureIndexes()
+
+	return dao
+}
+
+// -------------------------------------------------------------------
+// • Query methods
+// -------------------------------------------------------------------
+
+// Count returns the total number of role models based on the provided conditions.
+func (dao *RoleDAO) Count(conditions bson.M) (int, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	result, err := session.DB("").C(dao.Collection).Find(conditions).Count()
+
+	return result, err
+}
+
+// GetList returns list with role models.
+func (dao *RoleDAO) GetList(limit int, offset int, conditions bson.M, sortData []string) ([]models.Role, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.Role{}
+
+	// for case insensitive sort
+	collation := &mgo.Collation{
+		Locale:   "en",
+		Strength: 2,
+	}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		Collation(collation).
+		Sort(sortData...).
+		Limit(limit).
+		Skip(offset).
+		All(&items)
+
+	return items, err
+}
+
+// GetByIDs returns all role models with the provided ids.
+func (dao *RoleDAO) GetByIDs(ids []bson.ObjectId) ([]models.Role, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	items := []models.Role{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(bson.M{"_id": bson.M{"$in": ids}}).
+		All(&items)
+
+	return items, err
+}
+
+// GetOne returns single role model based on the provided conditions.
+func (dao *RoleDAO) GetOne(conditions bson.M) (*models.Role, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := &models.Role{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		One(model)
+
+	return model, err
+}
+
+// GetByID returns single role model by its id.
+func (dao *RoleDAO) GetByID(id string, additionalConditions ...bson.M) (*models.Role, error) {
+	if !bson.IsObjectIdHex(id) {
+		err := errors.New("Invalid object id format")
+
+		return &models.Role{}, err
+	}
+
+	conditions := bson.M{}
+	if len(additionalConditions) > 0 && additionalConditions[0] != nil {
+		conditions = additionalConditions[0]
+	}
+	conditions["_id"] = bson.ObjectIdHex(id)
+
+	return dao.GetOne(conditions)
+}
+
+// EffectiveAccess returns the union of the permissions of the provided roles
+// and the identity own access (see `models.MergeAccess()`).
+// The roles permissions are cached for `roles.cacheExpire` seconds
+// (the cache is cleared on every role change made by any app instance,
+// which is checked at most once every `roles.stampCheckInterval` seconds).
+func (dao *RoleDAO) EffectiveAccess(roleIDs []bson.ObjectId, access map[string][]string) (map[string][]string, error) {
+	accessList := []map[string][]string{}
+
+	checkInterval := time.Second * time.Duration(app.Config.GetInt64("roles.stampCheckInterval"))
+
+	if len(roleIDs) > 0 &&
+		app.Config.GetInt64("roles.cacheExpire") > 0 &&
+		roleAccessCache.shouldCheckStamp(checkInterval) {
+		stamp, stampErr := dao.currentStamp()
+		if stampErr != nil {
+			return nil, stampErr
+		}
+
+		roleAccessCache.sync(stamp)
+	}
+
+	missingIDs := []bson.ObjectId{}
+	for _, id := range roleIDs {
+		if roleAccess, ok := roleAccessCache.get(id); ok {
+			accessList = append(accessList, roleAccess)
+		} else {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+
+	if len(missingIDs) > 0 {
+		// the roles could be changed while fetching them
+		version := roleAccessCache.currentVersion()
+
+		roles, err := dao.GetByIDs(missingIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		expire := time.Second * time.Duration(app.Config.GetInt64("roles.cacheExpire"))
+
+		for _, role := range roles {
+			accessList = append(accessList, role.Access)
+
+			roleAccessCache.set(role.ID, role.Access, expire, version)
+		}
+	}
+
+	accessList = append(accessList, access)
+
+	return models.MergeAccess(accessList...), nil
+}
+
+// -------------------------------------------------------------------
+// • DB persists methods
+// -------------------------------------------------------------------
+
+// Create inserts and returns a new role model.
+func (dao *RoleDAO) Create(form *models.RoleForm) (*models.Role, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	// validate
+	validateErr := form.Validate()
+	if validateErr != nil {
+		return &models.Role{}, validateErr
+	}
+
+	model := form.ResolveModel()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).Insert(model)
+
+	return model, dbErr
+}
+
+// Update updates and returns existing role model.
+// The changes are applied to all role users and keys.
+func (dao *RoleDAO) Update(form *models.RoleForm) (*models.Role, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	// validate
+	validateErr := form.Validate()
+	if validateErr != nil {
+		return &models.Role{}, validateErr
+	}
+
+	model := form.ResolveModel()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
+
+	roleAccessCache.remove(model.ID)
+
+	if dbErr == nil {
+		dbErr = dao.touchStamp()
+	}
+
+	return model, dbErr
+}
+
+// Delete deletes the provided role model and unassigns it from all users and keys.
+func (dao *RoleDAO) Delete(model *models.Role) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	// db write
+	deleteErr := session.DB("").C(dao.Collection).RemoveId(model.ID)
+
+	roleAccessCache.remove(model.ID)
+
+	// @todo add some sort of transaction support
+	// update users and keys related data
+	if deleteErr == nil {
+		for _, collection := range []string{"user", "key"} {
+			session.DB("").C(collection).UpdateAll(
+				bson.M{"roles": model.ID},
+				bson.M{"$pull": bson.M{"roles": model.ID}},
+			)
+		}
+
+		deleteErr = dao.touchStamp()
+	}
+
+	return deleteErr
+}
+
+// UnsetAccessGroup unsets access group from all available roles.
+func (dao *RoleDAO) UnsetAccessGroup(group string) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.Collection).
+		UpdateAll(bson.M{}, bson.M{"$unset": bson.M{"access." + group: 1}})
+
+	roleAccessCache.clear()
+
+	if err == nil {
+		err = dao.touchStamp()
+	}
+
+	return err
+}
+
+// -------------------------------------------------------------------
+// • Helpers
+// -------------------------------------------------------------------
+
+// currentStamp returns the stored roles change stamp version (0 if none).
+func (dao *RoleDAO) currentStamp() (int64, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	stamp := &roleStamp{}
+
+	err := session.DB("").C(dao.StampCollection).FindId(roleStampID).One(stamp)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	return stamp.Version, err
+}
+
+// touchStamp increments the stored roles change stamp, so that
+// the roles access cache of all app instances gets invalidated.
+func (dao *RoleDAO) touchStamp() error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	_, err := session.DB("").C(dao.StampCollection).
+		UpsertId(roleStampID, bson.M{"$inc": bson.M{"version": 1}})
+
+	return err
+}
+
+// validateRoles checks whether all provided role ids belong to existing roles.
+func (dao *RoleDAO) validateRoles(ids []bson.ObjectId) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	roles, err := dao.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		exist := false
+
+		for _, role := range roles {
+			if role.ID == id {
+				exist = true
+
+				break
+			}
+		}
+
+		if !exist {
+			return utils.NewDataError(map[string]interface{}{
+				"roles": "Role with id '" + id.Hex() + "' doesn't exist.",
+			})
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+// • Roles access cache
+// -------------------------------------------------------------------
+
+// roleAccessCache holds the shared roles access permissions cache of the app instance.
+var roleAccessCache = &roleCache{items: map[bson.ObjectId]roleCacheItem{}}
+
+// roleCacheItem defines a single cached role access permissions.
+type roleCacheItem struct {
+	access  map[string][]string
+	expires time.Time
+}
+
+// roleCache is a simple concurrent safe roles access permissions cache.
+type roleCache struct {
+	mux     sync.RWMutex
+	items   map[bson.ObjectId]roleCacheItem
+	version int64
+	stamp   int64
+	checked time.Time
+}
+
+// shouldCheckStamp reports whether the stored roles change stamp
+// wasn't checked within the provided interval.
+func (rc *roleCache) shouldCheckStamp(interval time.Duration) bool {
+	rc.mux.RLock()
+	defer rc.mux.RUnlock()
+
+	return rc.checked.Add(interval).Before(time.Now())
+}
+
+// sync clears the cache if it was loaded for a different stored roles change stamp.
+func (rc *roleCache) sync(stamp int64) {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	rc.checked = time.Now()
+
+	if rc.stamp == stamp {
+		return
+	}
+
+	rc.items = map[bson.ObjectId]roleCacheItem{}
+	rc.version++
+	rc.stamp = stamp
+}
+
+// currentVersion returns the cache version (incremented on every removal).
+func (rc *roleCache) currentVersion() int64 {
+	rc.mux.RLock()
+	defer rc.mux.RUnlock()
+
+	return rc.version
+}
+
+// get returns the cached access permissions of a role (if exist and not expired).
+func (rc *roleCache) get(id bson.ObjectId) (map[string][]string, bool) {
+	rc.mux.RLock()
+	defer rc.mux.RUnlock()
+
+	item, ok := rc.items[id]
+	if !ok || item.expires.Before(time.Now()) {
+		return nil, false
+	}
+
+	return item.access, true
+}
+
+// set caches the access permissions of a role for the provided duration.
+// Nothing is cached if the duration is not positive or the cache was changed
+// after the `version` was taken (aka. the access permissions could be outdated).
+func (rc *roleCache) set(id bson.ObjectId, access map[string][]string, expire time.Duration, version int64) {
+	if expire <= 0 {
+		return
+	}
+
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	if rc.version != version {
+		return
+	}
+
+	rc.items[id] = roleCacheItem{access: access, expires: time.Now().Add(expire)}
+}
+
+// remove removes the cached access permissions of a role.
+func (rc *roleCache) remove(id bson.ObjectId) {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	delete(rc.items, id)
+	rc.version++
+}
+
+// clear removes all cached roles access permissions
+// and forces a new roles change stamp check.
+func (rc *roleCache) clear() {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	rc.items = map[bson.ObjectId]roleCacheItem{}
+	rc.version++
+	rc.stamp = 0
+	rc.checked = time.Time{}
+}
@@ -0,0 +1,447 @@
+package daos
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewRoleDAO(t *testing.T) {
+	dao := NewRoleDAO(TestSession)
+
+	if dao == nil {
+		t.Error("Expected RoleDAO pointer, got nil")
+	}
+
+	if dao.Collection != "role" {
+		t.Error("Expected role collection, got ", dao.Collection)
+	}
+
+	if dao.StampCollection != "role_stamp" {
+		t.Error("Expected role_stamp collection, got ", dao.StampCollection)
+	}
+}
+
+func TestRoleDAO_Count(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions bson.M
+		Expected   int
+	}{
+		{nil, 2},
+		{bson.M{"title": "missing"}, 0},
+		{bson.M{"title": "Editor"}, 1},
+	}
+
+	for _, scenario := range testScenarios {
+		result, _ := dao.Count(scenario.Conditions)
+
+		if result != scenario.Expected {
+			t.Errorf("Expected %d, got %d (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestRoleDAO_GetList(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		Conditions bson.M
+		Sort       []string
+		Limit      int
+		Offset     int
+		Expected   []string
+	}{
+		{nil, []string{"title"}, 10, 0, []string{"Editor", "Viewer"}},
+		{nil, []string{"-title"}, 10, 0, []string{"Viewer", "Editor"}},
+		{nil, []string{"title"}, 1, 1, []string{"Viewer"}},
+		{bson.M{"title": "missing"}, nil, 10, 0, []string{}},
+	}
+
+	for _, scenario := range testScenarios {
+		items, _ := dao.GetList(scenario.Limit, scenario.Offset, scenario.Conditions, scenario.Sort)
+
+		titles := []string{}
+		for _, item := range items {
+			titles = append(titles, item.Title)
+		}
+
+		if !reflect.DeepEqual(titles, scenario.Expected) {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, titles, scenario)
+		}
+	}
+}
+
+func TestRoleDAO_GetByID(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		Conditions  bson.M
+		ExpectError bool
+	}{
+		{"", nil, true},
+		{"invalid", nil, true},
+		{"5b1a3c4de138234cd5a2e009", nil, true},
+		{"5b1a3c4de138234cd5a2e001", bson.M{"title": "Viewer"}, true},
+		{"5b1a3c4de138234cd5a2e001", nil, false},
+		{"5b1a3c4de138234cd5a2e001", bson.M{"title": "Editor"}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		model, err := dao.GetByID(scenario.ID, scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && (err != nil || model.ID.Hex() != scenario.ID) {
+			t.Errorf("Expected %s model, got %v (error %v)", scenario.ID, model, err)
+		}
+	}
+}
+
+func TestRoleDAO_GetByIDs(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	items, err := dao.GetByIDs([]bson.ObjectId{
+		bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"),
+		bson.ObjectIdHex("5b1a3c4de138234cd5a2e009"),
+	})
+
+	if err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if len(items) != 1 || items[0].ID.Hex() != "5b1a3c4de138234cd5a2e001" {
+		t.Errorf("Expected only the 5b1a3c4de138234cd5a2e001 role, got %v", items)
+	}
+}
+
+func TestRoleDAO_EffectiveAccess(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	roleAccessCache.clear()
+	defer roleAccessCache.clear()
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		Roles    []bson.ObjectId
+		Access   map[string][]string
+		Expected map[string][]string
+	}{
+		{nil, nil, map[string][]string{}},
+		{
+			nil,
+			map[string][]string{"user": []string{"index"}},
+			map[string][]string{"user": []string{"index"}},
+		},
+		{
+			// missing role
+			[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e009")},
+			map[string][]string{"user": []string{"index"}},
+			map[string][]string{"user": []string{"index"}},
+		},
+		{
+			[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")},
+			map[string][]string{"media": []string{"delete"}},
+			map[string][]string{
+				"media":                    []string{"index", "view", "upload", "delete"},
+				"language":                 []string{"index", "view"},
+				"5a833090e1382351eaad3732": []string{"index", "view", "create", "update"},
+			},
+		},
+	}
+
+	for i, scenario := range testScenarios {
+		result, err := dao.EffectiveAccess(scenario.Roles, scenario.Access)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %d)", err, i)
+		}
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v, got %v (scenario %d)", scenario.Expected, result, i)
+		}
+	}
+}
+
+func TestRoleDAO_EffectiveAccess_Cache(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	roleAccessCache.clear()
+	defer roleAccessCache.clear()
+
+	defer app.Config.Set("roles.cacheExpire", app.Config.GetInt64("roles.cacheExpire"))
+	app.Config.Set("roles.cacheExpire", 60)
+
+	defer app.Config.Set("roles.stampCheckInterval", app.Config.GetInt64("roles.stampCheckInterval"))
+	app.Config.Set("roles.stampCheckInterval", 60)
+
+	dao := NewRoleDAO(TestSession)
+
+	roles := []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")}
+
+	// load the role into the cache
+	dao.EffectiveAccess(roles, nil)
+
+	// direct db change (eg. from another app instance)
+	TestSession.DB("").C("role").UpdateId(roles[0], bson.M{"$set": bson.M{
+		"access": map[string][]string{"key": []string{"index"}},
+	}})
+
+	result, _ := dao.EffectiveAccess(roles, nil)
+	if _, ok := result["language"]; !ok {
+		t.Fatalf("Expected the cached role access, got %v", result)
+	}
+
+	// change stamp of another app instance should be checked only once per interval
+	dao.touchStamp()
+
+	result, _ = dao.EffectiveAccess(roles, nil)
+	if _, ok := result["language"]; !ok {
+		t.Fatalf("Expected the cached role access until the next stamp check, got %v", result)
+	}
+
+	// change stamp of another app instance should clear the cache
+	app.Config.Set("roles.stampCheckInterval", 0)
+
+	result, _ = dao.EffectiveAccess(roles, nil)
+	if !reflect.DeepEqual(result, map[string][]string{"key": []string{"index"}}) {
+		t.Fatalf("Expected the changed role access, got %v", result)
+	}
+
+	// update via the dao should clear the cache
+	role, _ := dao.GetByID(roles[0].Hex())
+	dao.Update(&models.RoleForm{
+		Model:  role,
+		Title:  role.Title,
+		Access: map[string][]string{"user": []string{"view"}},
+	})
+
+	result, _ = dao.EffectiveAccess(roles, nil)
+	if !reflect.DeepEqual(result, map[string][]string{"user": []string{"view"}}) {
+		t.Fatalf("Expected the updated role access, got %v", result)
+	}
+
+	// expired cache
+	roleAccessCache.set(roles[0], map[string][]string{"test": []string{}}, time.Nanosecond, roleAccessCache.currentVersion())
+	time.Sleep(time.Millisecond)
+
+	if _, ok := roleAccessCache.get(roles[0]); ok {
+		t.Error("Expected the cached role access to be expired")
+	}
+
+	// outdated cache version
+	version := roleAccessCache.currentVersion()
+	roleAccessCache.remove(roles[0])
+	roleAccessCache.set(roles[0], map[string][]string{"test": []string{}}, time.Minute, version)
+
+	if _, ok := roleAccessCache.get(roles[0]); ok {
+		t.Error("Expected the outdated role access to not be cached")
+	}
+}
+
+func TestRoleDAO_Create(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		Title       string
+		Access      map[string][]string
+		ExpectError bool
+	}{
+		{"", nil, true},
+		{"Test", nil, true},
+		{"", map[string][]string{"test": []string{"index", "view"}}, true},
+		// existing title
+		{"Editor", map[string][]string{"test": []string{"index", "view"}}, true},
+		{"Test", map[string][]string{"test": []string{"index", "view"}}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		form := &models.RoleForm{
+			Title:  scenario.Title,
+			Access: scenario.Access,
+		}
+
+		createdModel, err := dao.Create(form)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if createdModel.Title != scenario.Title {
+			t.Errorf("Expected %s title, got %s (scenario %v)", scenario.Title, createdModel.Title, scenario)
+		}
+
+		if !reflect.DeepEqual(createdModel.Access, scenario.Access) {
+			t.Errorf("Expected %v access, got %v (scenario %v)", scenario.Access, createdModel.Access, scenario)
+		}
+
+		if count, _ := dao.Count(bson.M{"_id": createdModel.ID}); count != 1 {
+			t.Errorf("Expected the model to be persisted (scenario %v)", scenario)
+		}
+	}
+}
+
+func TestRoleDAO_Update(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		Title       string
+		Access      map[string][]string
+		ExpectError bool
+	}{
+		{"", nil, true},
+		// existing title
+		{"Viewer", map[string][]string{"test": []string{"index"}}, true},
+		{"Editor 2", map[string][]string{"test": []string{"index"}}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		role, _ := dao.GetByID("5b1a3c4de138234cd5a2e001")
+
+		form := &models.RoleForm{
+			Model:  role,
+			Title:  scenario.Title,
+			Access: scenario.Access,
+		}
+
+		updatedModel, err := dao.Update(form)
+
+		if scenario.ExpectError && err == nil {
+			t.Fatalf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if updatedModel.ID != role.ID {
+			t.Errorf("Expected %s id, got %s (scenario %v)", role.ID.Hex(), updatedModel.ID.Hex(), scenario)
+		}
+
+		persisted, _ := dao.GetByID(role.ID.Hex())
+		if persisted.Title != scenario.Title || !reflect.DeepEqual(persisted.Access, scenario.Access) {
+			t.Errorf("Expected the changes to be persisted, got %v (scenario %v)", persisted, scenario)
+		}
+	}
+}
+
+func TestRoleDAO_Delete(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	role, _ := dao.GetByID("5b1a3c4de138234cd5a2e001")
+	otherRoleID := bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")
+
+	// assign the roles
+	TestSession.DB("").C("user").UpdateId(
+		bson.ObjectIdHex("5a7b15cd3fb9dc041c55b45d"),
+		bson.M{"$set": bson.M{"roles": []bson.ObjectId{role.ID, otherRoleID}}},
+	)
+	TestSession.DB("").C("key").UpdateId(
+		bson.ObjectIdHex("5a75ee63e1382336728c2add"),
+		bson.M{"$set": bson.M{"roles": []bson.ObjectId{role.ID}}},
+	)
+
+	if err := dao.Delete(role); err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if _, err := dao.GetByID(role.ID.Hex()); err == nil {
+		t.Fatal("Expected the role to be deleted")
+	}
+
+	user, _ := NewUserDAO(TestSession).GetByID("5a7b15cd3fb9dc041c55b45d")
+	if !reflect.DeepEqual(user.Roles, []bson.ObjectId{otherRoleID}) {
+		t.Errorf("Expected only the %s user role to remain, got %v", otherRoleID.Hex(), user.Roles)
+	}
+
+	key, _ := NewKeyDAO(TestSession).GetByID("5a75ee63e1382336728c2add")
+	if len(key.Roles) != 0 {
+		t.Errorf("Expected the key role to be unassigned, got %v", key.Roles)
+	}
+}
+
+func TestRoleDAO_UnsetAccessGroup(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	if err := dao.UnsetAccessGroup("media"); err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if count, _ := dao.Count(bson.M{"access.media": bson.M{"$exists": true}}); count != 0 {
+		t.Errorf("Expected the media access group to be unset from all roles, got %d", count)
+	}
+
+	if count, _ := dao.Count(bson.M{"access.language": bson.M{"$exists": true}}); count != 1 {
+		t.Errorf("Expected the other access groups to remain, got %d", count)
+	}
+}
+
+func TestRoleDAO_validateRoles(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewRoleDAO(TestSession)
+
+	testScenarios := []struct {
+		IDs         []bson.ObjectId
+		ExpectError bool
+	}{
+		{nil, false},
+		{[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e009")}, true},
+		{[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e009")}, true},
+		{[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		err := dao.validateRoles(scenario.IDs)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+	}
+}
@@ -183,6 +183,10 @@ func (dao *UserDAO) Create(form *models.UserCreateForm) (*models.User, error) {
 		return &models.User{}, validateErr
 	}
 
+	if rolesErr := NewRoleDAO(session).validateRoles(form.Roles); rolesErr != nil {
+		return &models.User{}, rolesErr
+	}
+
 	user := form.ResolveModel()
 
 	// db write
@@ -192,6 +196,7 @@ func (dao *UserDAO) Create(form *models.UserCreateForm) (*models.User, error) {
 }
 
 // Update updates and returns existing user model.
+// If the password is changed, all previously issued user auth tokens and sessions are revoked.
 func (dao *UserDAO) Update(form *models.UserUpdateForm) (*models.User, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
@@ -202,15 +207,30 @@ func (dao *UserDAO) Update(form *models.UserUpdateForm) (*models.User, error) {
 		return &models.User{}, validateErr
 	}
 
+	if rolesErr := NewRoleDAO(session).validateRoles(form.Roles); rolesErr != nil {
+		return &models.User{}, rolesErr
+	}
+
 	model := form.ResolveModel()
 
+	passwordChanged := model.PasswordHash != form.Model.PasswordHash
+	if passwordChanged {
+		model.TokensRevoked = time.Now().Unix()
+	}
+
 	// db write
 	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
 
+	// sign out from all sessions
+	if dbErr == nil && passwordChanged {
+		dbErr = NewUserSessionDAO(dao.Session).RevokeAll(model.ID)
+	}
+
 	return model, dbErr
 }
 
 // ResetPassword resets and changes user's password.
+// All previously issued user auth tokens and sessions are revoked.
 func (dao *UserDAO) ResetPassword(form *models.UserResetPasswordForm) (*models.User, error) {
 	session := dao.Session.Copy()
 	defer session.Close()
@@ -222,13 +242,35 @@ func (dao *UserDAO) ResetPassword(form *models.UserResetPasswordForm) (*models.U
 	}
 
 	model := form.ResolveModel()
+	model.TokensRevoked = time.Now().Unix()
 
 	// db write
 	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, model)
 
+	// sign out from all sessions
+	if dbErr == nil {
+		dbErr = NewUserSessionDAO(dao.Session).RevokeAll(model.ID)
+	}
+
 	return model, dbErr
 }
 
+// RevokeTokens revokes all previously issued auth tokens and sessions of the provided user.
+func (dao *UserDAO) RevokeTokens(model *models.User) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.TokensRevoked = time.Now().Unix()
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{"tokens_revoked": model.TokensRevoked}})
+	if dbErr != nil {
+		return dbErr
+	}
+
+	return NewUserSessionDAO(dao.Session).RevokeAll(model.ID)
+}
+
 // RenewResetPasswordHash renews user reset password hash string.
 func (dao *UserDAO) RenewResetPasswordHash(model *models.User) (*models.User, error) {
 	session := dao.Session.Copy()
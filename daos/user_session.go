@@ -0,0 +1,188 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// UserSessionDAO gets and persists user session data in database.
+type UserSessionDAO struct {
+	Session    *mgo.Session
+	Collection string
+}
+
+// ensureIndexes makes sure that the required db indexes and constraints are set.
+func (dao *UserSessionDAO) ensureIndexes() {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	index := mgo.Index{
+		Key:        []string{"user_id"},
+		Background: true,
+	}
+
+	if err := c.EnsureIndex(index); err != nil {
+		panic(err)
+	}
+
+	// auto remove the expired (including the revoked ones) sessions
+	expireIndex := mgo.Index{
+		Key:         []string{"expire_at"},
+		Background:  true,
+		ExpireAfter: time.Second,
+	}
+
+	if err := c.EnsureIndex(expireIndex); err != nil {
+		panic(err)
+	}
+}
+
+// NewUserSessionDAO creates a new UserSessionDAO.
+func NewUserSessionDAO(session *mgo.Session) *UserSessionDAO {
+	dao := &UserSessionDAO{
+		Session:    session,
+		Collection: "user_session",
+	}
+
+	dao.ensureIndexes()
+
+	return dao
+}
+
+// -------------------------------------------------------------------
+// • Query methods
+// -------------------------------------------------------------------
+
+// GetOne returns single UserSession model based on the provided conditions.
+func (dao *UserSessionDAO) GetOne(conditions bson.M) (*models.UserSession, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := &models.UserSession{}
+
+	err := session.DB("").C(dao.Collection).
+		Find(conditions).
+		One(model)
+
+	return model, err
+}
+
+// GetByID returns single UserSession model by its id hex string.
+func (dao *UserSessionDAO) GetByID(id string, additionalConditions ...bson.M) (*models.UserSession, error) {
+	if !bson.IsObjectIdHex(id) {
+		err := errors.New("Invalid object id format")
+
+		return &models.UserSession{}, err
+	}
+
+	conditions := bson.M{}
+	if len(additionalConditions) > 0 && additionalConditions[0] != nil {
+		conditions = additionalConditions[0]
+	}
+	conditions["_id"] = bson.ObjectIdHex(id)
+
+	return dao.GetOne(conditions)
+}
+
+// -------------------------------------------------------------------
+// • DB persists methods
+// -------------------------------------------------------------------
+
+// Create inserts a new session for the provided user and returns it together with its refresh token.
+func (dao *UserSessionDAO) Create(user *models.User, exp int64) (*models.UserSession, string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model := models.NewUserSession(user, exp)
+
+	refreshToken, tokenErr := model.RenewRefreshToken()
+	if tokenErr != nil {
+		return nil, "", tokenErr
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).Insert(model)
+
+	return model, refreshToken, dbErr
+}
+
+// Refresh rotates the refresh token of the session that the provided one belongs to
+// and returns the session together with its new refresh token.
+//
+// Reusing an already rotated refresh token revokes the whole session,
+// because it is a sign that the token was leaked.
+func (dao *UserSessionDAO) Refresh(refreshToken string) (*models.UserSession, string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	id, _, ok := models.ParseRefreshToken(refreshToken)
+	if !ok {
+		return nil, "", errors.New("Invalid refresh token.")
+	}
+
+	model, fetchErr := dao.GetByID(id)
+	if fetchErr != nil || !model.IsActive() {
+		return nil, "", errors.New("Invalid or expired refresh token.")
+	}
+
+	if !model.ValidateRefreshToken(refreshToken) {
+		dao.Revoke(model)
+
+		return nil, "", errors.New("Invalid or expired refresh token.")
+	}
+
+	oldHash := model.RefreshHash
+
+	newRefreshToken, tokenErr := model.RenewRefreshToken()
+	if tokenErr != nil {
+		return nil, "", tokenErr
+	}
+
+	// db write
+	// (the old hash condition prevents rotating the same token more than once by concurrent requests)
+	dbErr := session.DB("").C(dao.Collection).Update(
+		bson.M{"_id": model.ID, "refresh_hash": oldHash, "revoked": false},
+		bson.M{"$set": bson.M{"refresh_hash": model.RefreshHash, "modified": model.Modified}},
+	)
+	if dbErr == mgo.ErrNotFound {
+		return nil, "", errors.New("Invalid or expired refresh token.")
+	}
+
+	return model, newRefreshToken, dbErr
+}
+
+// Revoke revokes the provided session model.
+func (dao *UserSessionDAO) Revoke(model *models.UserSession) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.Revoked = true
+	model.Modified = time.Now().Unix()
+
+	// db write
+	return session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"revoked":  model.Revoked,
+		"modified": model.Modified,
+	}})
+}
+
+// RevokeAll revokes all sessions of a single user.
+func (dao *UserSessionDAO) RevokeAll(userID bson.ObjectId) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	// db write
+	_, err := session.DB("").C(dao.Collection).UpdateAll(
+		bson.M{"user_id": userID, "revoked": false},
+		bson.M{"$set": bson.M{"revoked": true, "modified": time.Now().Unix()}},
+	)
+
+	return err
+}
@@ -0,0 +1,192 @@
+package daos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewUserSessionDAO(t *testing.T) {
+	dao := NewUserSessionDAO(TestSession)
+
+	if dao == nil {
+		t.Error("Expected UserSessionDAO pointer, got nil")
+	}
+
+	if dao.Collection != "user_session" {
+		t.Error("Expected user_session collection, got ", dao.Collection)
+	}
+}
+
+func TestUserSessionDAO_GetByID(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserSessionDAO(TestSession)
+
+	testScenarios := []struct {
+		ID          string
+		Conditions  bson.M
+		ExpectError bool
+	}{
+		{"", nil, true},
+		{"invalid", nil, true},
+		{"5b0d4d7ae138232e3b7d6a01", bson.M{"user_id": bson.ObjectIdHex("5a7c9017e138234e16e3dee6")}, true},
+		{"5b0d4d7ae138232e3b7d6a01", nil, false},
+		{"5b0d4d7ae138232e3b7d6a01", bson.M{"user_id": bson.ObjectIdHex("5a7b15cd3fb9dc041c55b45d")}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		model, err := dao.GetByID(scenario.ID, scenario.Conditions)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && (err != nil || model.ID.Hex() != scenario.ID) {
+			t.Errorf("Expected %s model, got %v (error %v)", scenario.ID, model, err)
+		}
+	}
+}
+
+func TestUserSessionDAO_Create(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserSessionDAO(TestSession)
+
+	user, _ := NewUserDAO(TestSession).GetByUsername("user1")
+
+	exp := time.Now().Unix() + 100
+
+	model, refreshToken, err := dao.Create(user, exp)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, fetchErr := dao.GetByID(model.ID.Hex())
+	if fetchErr != nil {
+		t.Fatal("Expected the session to be stored, got error", fetchErr)
+	}
+
+	if stored.UserID != user.ID || stored.Expires != exp || !stored.IsActive() {
+		t.Error("Expected active user session, got", stored)
+	}
+
+	if !stored.ValidateRefreshToken(refreshToken) {
+		t.Errorf("Expected %s to be valid session refresh token", refreshToken)
+	}
+}
+
+func TestUserSessionDAO_Refresh(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserSessionDAO(TestSession)
+
+	testScenarios := []struct {
+		Token       string
+		ExpectError bool
+	}{
+		{"", true},
+		{"invalid", true},
+		{"5b0d4d7ae138232e3b7d6a04.test_refresh_secret_1", true}, // missing session
+		{"5b0d4d7ae138232e3b7d6a02.test_refresh_secret_2", true}, // revoked session
+		{"5b0d4d7ae138232e3b7d6a03.test_refresh_secret_3", true}, // expired session
+		{"5b0d4d7ae138232e3b7d6a01.test_refresh_secret_1", false},
+		{"5b0d4d7ae138232e3b7d6a01.test_refresh_secret_1", true}, // already rotated
+	}
+
+	for i, scenario := range testScenarios {
+		model, refreshToken, err := dao.Refresh(scenario.Token)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %d)", i)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %d)", err, i)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if refreshToken == scenario.Token || !model.ValidateRefreshToken(refreshToken) {
+			t.Errorf("Expected new valid refresh token, got %s (scenario %d)", refreshToken, i)
+		}
+	}
+
+	// the reuse of the rotated token should revoke the session
+	model, _ := dao.GetByID("5b0d4d7ae138232e3b7d6a01")
+	if !model.Revoked {
+		t.Error("Expected the session to be revoked")
+	}
+}
+
+func TestUserSessionDAO_Revoke(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserSessionDAO(TestSession)
+
+	model, _ := dao.GetByID("5b0d4d7ae138232e3b7d6a01")
+
+	if err := dao.Revoke(model); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if !model.Revoked {
+		t.Error("Expected the model to be marked as revoked")
+	}
+
+	stored, _ := dao.GetByID("5b0d4d7ae138232e3b7d6a01")
+	if !stored.Revoked {
+		t.Error("Expected the stored session to be revoked")
+	}
+}
+
+func TestUserSessionDAO_RevokeAll(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserSessionDAO(TestSession)
+
+	otherUser, _ := NewUserDAO(TestSession).GetByUsername("user2")
+	otherSession, _, _ := dao.Create(otherUser, time.Now().Unix()+100)
+
+	if err := dao.RevokeAll(bson.ObjectIdHex("5a7b15cd3fb9dc041c55b45d")); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	for _, id := range []string{"5b0d4d7ae138232e3b7d6a01", "5b0d4d7ae138232e3b7d6a02", "5b0d4d7ae138232e3b7d6a03"} {
+		if model, _ := dao.GetByID(id); !model.Revoked {
+			t.Errorf("Expected %s session to be revoked", id)
+		}
+	}
+
+	if model, _ := dao.GetByID(otherSession.ID.Hex()); model.Revoked {
+		t.Error("Expected the other user session to not be revoked")
+	}
+}
+
+// assertUserSessionsRevoked checks whether all sessions of a single user are revoked.
+func assertUserSessionsRevoked(t *testing.T, userID bson.ObjectId) {
+	session := TestSession.Copy()
+	defer session.Close()
+
+	total, _ := session.DB("").C("user_session").Find(bson.M{"user_id": userID, "revoked": false}).Count()
+	if total > 0 {
+		t.Errorf("Expected all %s user sessions to be revoked, got %d active", userID.Hex(), total)
+	}
+}
+
+// newTestUserSession returns new active session of the provided user.
+func newTestUserSession(t *testing.T, user *models.User) *models.UserSession {
+	model, _, err := NewUserSessionDAO(TestSession).Create(user, time.Now().Unix()+100)
+	if err != nil {
+		t.Fatal("Failed to create user session", err)
+	}
+
+	return model
+}
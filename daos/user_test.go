@@ -382,6 +382,8 @@ func TestUserDAO_Update(t *testing.T) {
 
 	originalUser, _ := dao.GetByUsername("user1")
 
+	newTestUserSession(t, originalUser)
+
 	testScenarios := []struct {
 		Form        *models.UserUpdateForm
 		ExpectError bool
@@ -468,6 +470,11 @@ func TestUserDAO_Update(t *testing.T) {
 			t.Errorf("Expected %s password to be set (scenario %v)", scenario.Form.Password, scenario)
 		}
 
+		// only the password change should revoke the user tokens
+		if (scenario.Form.Password != "") != (updatedModel.TokensRevoked > 0) {
+			t.Errorf("Expected tokens revoked to be set only on password change, got %d (scenario %v)", updatedModel.TokensRevoked, scenario)
+		}
+
 		if len(updatedModel.Access) != 1 {
 			t.Error("Expected access to be set")
 		}
@@ -476,6 +483,8 @@ func TestUserDAO_Update(t *testing.T) {
 			t.Errorf("Expected modified date to be updated, got %d vs %d (scenario %v)", updatedModel.Modified, originalUser.Modified, scenario)
 		}
 	}
+
+	assertUserSessionsRevoked(t, originalUser.ID)
 }
 
 func TestUserDAO_ResetPassword(t *testing.T) {
@@ -523,7 +532,35 @@ func TestUserDAO_ResetPassword(t *testing.T) {
 		if updatedModel.Modified == originalUser.Modified {
 			t.Errorf("Expected modified date to be updated, got %d vs %d (scenario %v)", updatedModel.Modified, originalUser.Modified, scenario)
 		}
+
+		if updatedModel.TokensRevoked == 0 {
+			t.Errorf("Expected tokens revoked to be set (scenario %v)", scenario)
+		}
 	}
+
+	assertUserSessionsRevoked(t, originalUser.ID)
+}
+
+func TestUserDAO_RevokeTokens(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	user, _ := dao.GetByUsername("user2")
+
+	newTestUserSession(t, user)
+
+	if err := dao.RevokeTokens(user); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	stored, _ := dao.GetByUsername("user2")
+	if stored.TokensRevoked == 0 || stored.TokensRevoked != user.TokensRevoked {
+		t.Errorf("Expected tokens revoked to be set, got %d", stored.TokensRevoked)
+	}
+
+	assertUserSessionsRevoked(t, user.ID)
 }
 
 func TestUserDAO_RenewResetPasswordHash(t *testing.T) {
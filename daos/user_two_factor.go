@@ -0,0 +1,136 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/gofreta/gofreta-api/models"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// EnrollTwoFactor generates and persists new pending TOTP secret for the provided user model.
+// The secret is not used until it is confirmed via `EnableTwoFactor()`.
+func (dao *UserDAO) EnrollTwoFactor(model *models.User) (string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	secret, secretErr := model.RenewTwoFactorPendingSecret()
+	if secretErr != nil {
+		return "", secretErr
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"two_factor_pending_secret": model.TwoFactorPendingSecret,
+	}})
+
+	return secret, dbErr
+}
+
+// EnableTwoFactor confirms the pending TOTP secret of the provided user model with a valid code,
+// enables the user two-factor authentication and returns the new user recovery codes.
+func (dao *UserDAO) EnableTwoFactor(model *models.User, code string) ([]string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	codes, enableErr := model.EnableTwoFactor(code)
+	if enableErr != nil {
+		return nil, enableErr
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"two_factor_enabled":        model.TwoFactorEnabled,
+		"two_factor_secret":         model.TwoFactorSecret,
+		"two_factor_pending_secret": model.TwoFactorPendingSecret,
+		"two_factor_recovery_codes": model.TwoFactorRecoveryCodes,
+		"two_factor_last_counter":   model.TwoFactorLastCounter,
+	}})
+
+	return codes, dbErr
+}
+
+// DisableTwoFactor disables the two-factor authentication of the provided user model.
+func (dao *UserDAO) DisableTwoFactor(model *models.User) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	model.DisableTwoFactor()
+
+	// db write
+	return session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"two_factor_enabled":        model.TwoFactorEnabled,
+		"two_factor_secret":         model.TwoFactorSecret,
+		"two_factor_pending_secret": model.TwoFactorPendingSecret,
+		"two_factor_recovery_codes": model.TwoFactorRecoveryCodes,
+		"two_factor_last_counter":   model.TwoFactorLastCounter,
+	}})
+}
+
+// VerifyTwoFactor checks the provided TOTP or recovery code against the two-factor
+// settings of the provided user model. The used TOTP time steps and recovery codes
+// are persisted, so that each code could be used only once.
+func (dao *UserDAO) VerifyTwoFactor(model *models.User, code string) error {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	c := session.DB("").C(dao.Collection)
+
+	var dbErr error
+
+	// db write
+	// (the conditions prevent using the same code more than once by concurrent requests)
+	if counter, ok := model.ValidateTwoFactorCode(code); ok {
+		dbErr = c.Update(
+			bson.M{"_id": model.ID, "two_factor_last_counter": bson.M{"$lt": counter}},
+			bson.M{"$set": bson.M{"two_factor_last_counter": counter}},
+		)
+
+		model.TwoFactorLastCounter = counter
+	} else if hash, ok := model.MatchRecoveryCode(code); ok {
+		dbErr = c.Update(
+			bson.M{"_id": model.ID, "two_factor_recovery_codes": hash},
+			bson.M{"$pull": bson.M{"two_factor_recovery_codes": hash}},
+		)
+
+		remainingCodes := []string{}
+		for _, existingHash := range model.TwoFactorRecoveryCodes {
+			if existingHash != hash {
+				remainingCodes = append(remainingCodes, existingHash)
+			}
+		}
+		model.TwoFactorRecoveryCodes = remainingCodes
+	} else {
+		return errors.New("Invalid two-factor authentication code.")
+	}
+
+	if dbErr == mgo.ErrNotFound {
+		return errors.New("Invalid two-factor authentication code.")
+	}
+
+	return dbErr
+}
+
+// RenewRecoveryCodes replaces the recovery codes of the provided user model
+// with a new set and returns them.
+func (dao *UserDAO) RenewRecoveryCodes(model *models.User) ([]string, error) {
+	session := dao.Session.Copy()
+	defer session.Close()
+
+	if !model.TwoFactorEnabled {
+		return nil, errors.New("Two-factor authentication is not enabled.")
+	}
+
+	codes, codesErr := model.RenewRecoveryCodes()
+	if codesErr != nil {
+		return nil, codesErr
+	}
+
+	// db write
+	dbErr := session.DB("").C(dao.Collection).UpdateId(model.ID, bson.M{"$set": bson.M{
+		"two_factor_recovery_codes": model.TwoFactorRecoveryCodes,
+	}})
+
+	return codes, dbErr
+}
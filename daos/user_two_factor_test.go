@@ -0,0 +1,191 @@
+package daos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofreta/gofreta-api/fixtures"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
+)
+
+func TestUserDAO_EnrollTwoFactor(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	user, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	secret, err := dao.EnrollTwoFactor(user)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	updatedUser, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	if updatedUser.TwoFactorPendingSecret != secret {
+		t.Errorf("Expected %s pending secret, got %s", secret, updatedUser.TwoFactorPendingSecret)
+	}
+
+	if updatedUser.TwoFactorEnabled {
+		t.Error("Expected the two-factor authentication to be still disabled")
+	}
+}
+
+func TestUserDAO_EnableTwoFactor(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	user, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	// missing enrollment
+	if _, err := dao.EnableTwoFactor(user, "123456"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	secret, _ := dao.EnrollTwoFactor(user)
+
+	// invalid code
+	if _, err := dao.EnableTwoFactor(user, "invalid"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	code, _ := utils.TOTPCode(secret, utils.TOTPCounter(time.Now()))
+
+	codes, err := dao.EnableTwoFactor(user, code)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if len(codes) != models.UserRecoveryCodesCount {
+		t.Errorf("Expected %d recovery codes, got %v", models.UserRecoveryCodesCount, codes)
+	}
+
+	updatedUser, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	if !updatedUser.TwoFactorEnabled || updatedUser.TwoFactorSecret != secret || updatedUser.TwoFactorPendingSecret != "" {
+		t.Errorf("Expected the two-factor authentication to be enabled, got %v", updatedUser)
+	}
+
+	if len(updatedUser.TwoFactorRecoveryCodes) != models.UserRecoveryCodesCount {
+		t.Errorf("Expected %d stored recovery codes, got %v", models.UserRecoveryCodesCount, updatedUser.TwoFactorRecoveryCodes)
+	}
+}
+
+func TestUserDAO_VerifyTwoFactor(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	user, recoveryCodes := enableTestTwoFactor(t, dao, "5a7b15cd3fb9dc041c55b45d")
+
+	// the enable code time step is already used, so use the next one
+	nextCode, _ := utils.TOTPCode(user.TwoFactorSecret, user.TwoFactorLastCounter+1)
+
+	testScenarios := []struct {
+		Code        string
+		ExpectError bool
+	}{
+		{"", true},
+		{"invalid", true},
+		{nextCode, false},
+		// already used code
+		{nextCode, true},
+		{recoveryCodes[0], false},
+		// already used recovery code
+		{recoveryCodes[0], true},
+		{recoveryCodes[1], false},
+	}
+
+	for i, scenario := range testScenarios {
+		// verify with the db state
+		model, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+		err := dao.VerifyTwoFactor(model, scenario.Code)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %d)", i)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %d)", err, i)
+		}
+	}
+
+	updatedUser, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	if len(updatedUser.TwoFactorRecoveryCodes) != models.UserRecoveryCodesCount-2 {
+		t.Errorf("Expected %d remaining recovery codes, got %d", models.UserRecoveryCodesCount-2, len(updatedUser.TwoFactorRecoveryCodes))
+	}
+
+	if updatedUser.TwoFactorLastCounter != user.TwoFactorLastCounter+1 {
+		t.Errorf("Expected %d last counter, got %d", user.TwoFactorLastCounter+1, updatedUser.TwoFactorLastCounter)
+	}
+}
+
+func TestUserDAO_DisableTwoFactor(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	user, _ := enableTestTwoFactor(t, dao, "5a7b15cd3fb9dc041c55b45d")
+
+	if err := dao.DisableTwoFactor(user); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	updatedUser, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	if updatedUser.TwoFactorEnabled || updatedUser.TwoFactorSecret != "" || len(updatedUser.TwoFactorRecoveryCodes) != 0 {
+		t.Errorf("Expected the two-factor authentication to be disabled, got %v", updatedUser)
+	}
+}
+
+func TestUserDAO_RenewRecoveryCodes(t *testing.T) {
+	fixtures.InitFixtures(TestSession)
+	defer fixtures.CleanFixtures(TestSession)
+
+	dao := NewUserDAO(TestSession)
+
+	// disabled two-factor authentication
+	user2, _ := dao.GetByID("5a7c9017e138234e16e3dee6")
+	if _, err := dao.RenewRecoveryCodes(user2); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	user, oldCodes := enableTestTwoFactor(t, dao, "5a7b15cd3fb9dc041c55b45d")
+
+	newCodes, err := dao.RenewRecoveryCodes(user)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	updatedUser, _ := dao.GetByID("5a7b15cd3fb9dc041c55b45d")
+
+	if _, match := updatedUser.MatchRecoveryCode(oldCodes[0]); match {
+		t.Error("Expected the old recovery codes to be invalid")
+	}
+
+	if _, match := updatedUser.MatchRecoveryCode(newCodes[0]); !match {
+		t.Error("Expected the new recovery codes to be valid")
+	}
+}
+
+// enableTestTwoFactor enrolls and enables the two-factor authentication of a single user.
+func enableTestTwoFactor(t *testing.T, dao *UserDAO, id string) (*models.User, []string) {
+	user, _ := dao.GetByID(id)
+
+	secret, _ := dao.EnrollTwoFactor(user)
+
+	code, _ := utils.TOTPCode(secret, utils.TOTPCounter(time.Now()))
+
+	codes, err := dao.EnableTwoFactor(user, code)
+	if err != nil {
+		t.Fatal("Failed to enable the two-factor authentication", err)
+	}
+
+	return user, codes
+}
@@ -21,9 +21,13 @@ func InitFixtures(mgoSession *mgo.Session) {
 		"media",
 		"collection",
 		"entity",
+		"entity_revision",
+		"hook_delivery",
+		"user_session",
+		"role",
 	}
 
-	hexFields := []string{"_id", "collection_id"}
+	hexFields := []string{"_id", "collection_id", "entity_id", "user_id"}
 
 	for _, collection := range collections {
 		var items []map[string]interface{}
@@ -55,6 +59,13 @@ func CleanFixtures(mgoSession *mgo.Session) {
 		"media",
 		"collection",
 		"entity",
+		"entity_revision",
+		"hook_delivery",
+		"user_session",
+		"role",
+		"rate_limit",
+		"migration",
+		"role_stamp",
 	}
 
 	for _, collection := range collections {
@@ -3,10 +3,13 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofreta/gofreta-api/utils"
 
@@ -25,6 +28,14 @@ const (
 	FieldTypeEditor    = "editor"
 	FieldTypeMedia     = "media"
 	FieldTypeRelation  = "relation"
+	FieldTypeNumber    = "number"
+	FieldTypeEmail     = "email"
+	FieldTypeUrl       = "url"
+	FieldTypeColor     = "color"
+	FieldTypeJson      = "json"
+	FieldTypeGeoPoint  = "geopoint"
+	FieldTypeGroup     = "group"
+	FieldTypeSlug      = "slug"
 
 	// MetaEditor supported modes
 	MetaEditorModeSimple = "simple"
@@ -33,8 +44,24 @@ const (
 	// MetaDate supported modes
 	MetaDateModeDate     = "date"
 	MetaDateModeDateTime = "datetime"
+
+	// MetaMedia and MetaRelation supported on delete policies
+	// (applied to the referencing entities when the referenced item is deleted)
+	MetaOnDeleteNullify  = "nullify"  // removes the deleted item id (default)
+	MetaOnDeleteRestrict = "restrict" // prevents the deletion of referenced items
+	MetaOnDeleteCascade  = "cascade"  // deletes the referencing entities too
+
+	// MetaNumber max supported precision
+	MetaNumberMaxPrecision = 10
+
+	// CollectionField dynamic default values
+	FieldDefaultNow         = "@now"          // the current unix timestamp (date fields only)
+	FieldDefaultCurrentUser = "@current_user" // the authenticated identity id (plain fields only)
 )
 
+// colorRegex defines the color field value format (hex colors, eg. #fff, #ff0000).
+var colorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
 type (
 	// Collection defines the Collection model fields.
 	Collection struct {
@@ -45,6 +72,7 @@ type (
 		CreateHook string            `json:"create_hook" bson:"create_hook"`
 		UpdateHook string            `json:"update_hook" bson:"update_hook"`
 		DeleteHook string            `json:"delete_hook" bson:"delete_hook"`
+		HookSecret string            `json:"-" bson:"hook_secret"`
 		Created    int64             `json:"created" bson:"created"`
 		Modified   int64             `json:"modified" bson:"modified"`
 	}
@@ -58,6 +86,8 @@ type (
 		CreateHook string            `json:"create_hook" form:"create_hook"`
 		UpdateHook string            `json:"update_hook" form:"update_hook"`
 		DeleteHook string            `json:"delete_hook" form:"delete_hook"`
+		HookSecret string            `json:"hook_secret" form:"hook_secret"`
+		Renames    map[string]string `json:"renames" form:"renames"`
 	}
 
 	// CollectionField defines single CollectionField struct properties.
@@ -68,6 +98,7 @@ type (
 		Required     bool        `json:"required" bson:"required" form:"required"`
 		Unique       bool        `json:"unique" bson:"unique" form:"unique"`
 		Multilingual bool        `json:"multilingual" bson:"multilingual" form:"multilingual"`
+		Searchable   bool        `json:"searchable" bson:"searchable" form:"searchable"`
 		Default      interface{} `json:"default" bson:"default" form:"default"`
 		Meta         interface{} `json:"meta" bson:"meta" form:"meta"`
 	}
@@ -79,6 +110,9 @@ type (
 
 	// MetaPlain struct for the plain field meta data model
 	MetaPlain struct {
+		MinLength int    `json:"min_length" bson:"min_length" form:"min_length"`
+		MaxLength int    `json:"max_length" bson:"max_length" form:"max_length"`
+		Pattern   string `json:"pattern" bson:"pattern" form:"pattern"`
 	}
 
 	// MetaSwitch struct for the switch field meta data model
@@ -88,6 +122,8 @@ type (
 	// MetaChecklist struct for the checklist field meta data model
 	MetaChecklist struct {
 		Options []MetaChecklistOption `json:"options" bson:"options" form:"options"`
+		Min     int                   `json:"min" bson:"min" form:"min"`
+		Max     int                   `json:"max" bson:"max" form:"max"`
 	}
 
 	// MetaChecklistOption struct describing single checklist meta option field model
@@ -110,25 +146,117 @@ type (
 	// MetaDate struct for the date field meta data model
 	MetaDate struct {
 		Mode string `json:"mode" bson:"mode" form:"mode"`
+		Min  int    `json:"min" bson:"min" form:"min"`
+		Max  int    `json:"max" bson:"max" form:"max"`
 	}
 
 	// MetaEditor struct for the editor field meta data model
 	MetaEditor struct {
-		Mode string `json:"mode" bson:"mode" form:"mode"`
+		Mode      string `json:"mode" bson:"mode" form:"mode"`
+		MinLength int    `json:"min_length" bson:"min_length" form:"min_length"`
+		MaxLength int    `json:"max_length" bson:"max_length" form:"max_length"`
+		Pattern   string `json:"pattern" bson:"pattern" form:"pattern"`
 	}
 
 	// MetaMedia struct for the media field meta data model
 	MetaMedia struct {
-		Max uint8 `json:"max" bson:"max" form:"max"`
+		Max      uint8  `json:"max" bson:"max" form:"max"`
+		OnDelete string `json:"on_delete" bson:"on_delete" form:"on_delete"`
 	}
 
 	// MetaRelation struct for the relation field meta data model
 	MetaRelation struct {
 		Max          uint8         `json:"max" bson:"max" form:"max"`
 		CollectionID bson.ObjectId `json:"collection_id" bson:"collection_id" form:"collection_id"`
+		OnDelete     string        `json:"on_delete" bson:"on_delete" form:"on_delete"`
+	}
+
+	// MetaNumber struct for the number field meta data model
+	MetaNumber struct {
+		Precision int `json:"precision" bson:"precision" form:"precision"`
+	}
+
+	// MetaEmail struct for the email field meta data model
+	MetaEmail struct {
+	}
+
+	// MetaUrl struct for the url field meta data model
+	MetaUrl struct {
+	}
+
+	// MetaColor struct for the color field meta data model
+	MetaColor struct {
+	}
+
+	// MetaJson struct for the json field meta data model
+	MetaJson struct {
+	}
+
+	// MetaGeoPoint struct for the geopoint field meta data model
+	MetaGeoPoint struct {
+	}
+
+	// MetaGroup struct for the group (repeater) field meta data model
+	MetaGroup struct {
+		Fields []CollectionField `json:"fields" bson:"fields" form:"fields"`
+		Repeat bool              `json:"repeat" bson:"repeat" form:"repeat"`
+		Min    int               `json:"min" bson:"min" form:"min"`
+		Max    int               `json:"max" bson:"max" form:"max"`
+	}
+
+	// MetaSlug struct for the slug field meta data model
+	MetaSlug struct {
+		Source string `json:"source" bson:"source" form:"source"`
 	}
 )
 
+// SearchableFieldKeys returns the keys of all collection fields marked as searchable.
+func (m Collection) SearchableFieldKeys() []string {
+	keys := []string{}
+
+	for _, field := range m.Fields {
+		if field.Searchable {
+			keys = append(keys, field.Key)
+		}
+	}
+
+	return keys
+}
+
+// TranslatableFields returns all multilingual collection text (plain and editor) fields.
+func (m Collection) TranslatableFields() []CollectionField {
+	fields := []CollectionField{}
+
+	for _, field := range m.Fields {
+		if field.Multilingual && (field.Type == FieldTypePlain || field.Type == FieldTypeEditor) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// SharedDataPath rewrites an entity locale data path (eg. "data.en.price" or "draft.bg.price.amount")
+// of a non-multilingual collection field to its shared data group path (eg. "data._shared.price").
+// All other paths are returned as they are.
+func (m Collection) SharedDataPath(path string) string {
+	parts := strings.SplitN(path, ".", 4)
+
+	if len(parts) < 3 || (parts[0] != "data" && parts[0] != "draft") || parts[1] == EntitySharedDataKey {
+		return path
+	}
+
+	for _, field := range m.Fields {
+		if field.Key == parts[2] && !field.Multilingual {
+			parts[1] = EntitySharedDataKey
+
+			return strings.Join(parts, ".")
+		}
+	}
+
+	return path
+}
+
 // ResolveModel resolves and returns the form Collection model.
 // If the form doesn't have a Collection model, it will instantiate a new one.
 func (m CollectionForm) ResolveModel() *Collection {
@@ -153,6 +281,13 @@ func (m CollectionForm) ResolveModel() *Collection {
 	model.DeleteHook = m.DeleteHook
 	model.Modified = now
 
+	// keep the existing hooks secret or generate a new one if not set
+	if m.HookSecret != "" {
+		model.HookSecret = m.HookSecret
+	} else if model.HookSecret == "" {
+		model.HookSecret = utils.Random(32)
+	}
+
 	return &model
 }
 
@@ -161,13 +296,49 @@ func (m CollectionForm) Validate() error {
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.Title, validation.Required),
 		validation.Field(&m.Name, validation.Required, validation.Match(regexp.MustCompile(`^\w+$`))),
-		validation.Field(&m.Fields, validation.Required, validation.By(uniqueFieldKey)),
+		validation.Field(&m.Fields, validation.Required, validation.By(uniqueFieldKey), validation.By(checkSlugSources)),
 		validation.Field(&m.CreateHook, is.URL),
 		validation.Field(&m.UpdateHook, is.URL),
 		validation.Field(&m.DeleteHook, is.URL),
+		validation.Field(&m.HookSecret, validation.Length(16, 255)),
+		validation.Field(&m.Renames, validation.By(checkFieldRenames(m.Model, m.Fields))),
 	)
 }
 
+// SchemaDiff returns the fields schema diff between the form model and the form fields.
+func (m CollectionForm) SchemaDiff() *SchemaDiff {
+	oldFields := []CollectionField{}
+	if m.Model != nil {
+		oldFields = m.Model.Fields
+	}
+
+	return NewSchemaDiff(oldFields, m.Fields, m.Renames)
+}
+
+// checkFieldRenames checks whether the validating renames map
+// contains only existing old and new collection field keys.
+func checkFieldRenames(model *Collection, fields []CollectionField) validation.RuleFunc {
+	return func(value interface{}) error {
+		renames, _ := value.(map[string]string)
+
+		if len(renames) > 0 && model == nil {
+			return errors.New("Field renames are allowed only for existing collections.")
+		}
+
+		for oldKey, newKey := range renames {
+			if _, exist := findField(model.Fields, oldKey); !exist {
+				return errors.New("Field with key '" + oldKey + "' doesn't exist.")
+			}
+
+			if _, exist := findField(fields, newKey); !exist {
+				return errors.New("Missing new field with key '" + newKey + "'.")
+			}
+		}
+
+		return nil
+	}
+}
+
 // uniqueFieldKey checks whether a slice of collection fields have an uniqie key value.
 func uniqueFieldKey(value interface{}) error {
 	fields, _ := value.([]CollectionField)
@@ -185,6 +356,27 @@ func uniqueFieldKey(value interface{}) error {
 	return nil
 }
 
+// checkSlugSources checks whether the slug fields of a slice of collection fields
+// are generated from an existing plain field.
+func checkSlugSources(value interface{}) error {
+	fields, _ := value.([]CollectionField)
+
+	for _, field := range fields {
+		if field.Type != FieldTypeSlug {
+			continue
+		}
+
+		meta, _ := NewMetaSlug(field.Meta)
+
+		source, exist := findField(fields, meta.Source)
+		if !exist || source.Type != FieldTypePlain {
+			return errors.New("The source of slug field '" + field.Key + "' must be an existing plain field.")
+		}
+	}
+
+	return nil
+}
+
 // Validate validates the CollectionField struct properties.
 func (m CollectionField) Validate() error {
 	if err := m.metaInit(); err != nil {
@@ -203,11 +395,100 @@ func (m CollectionField) Validate() error {
 			FieldTypeEditor,
 			FieldTypeMedia,
 			FieldTypeRelation,
+			FieldTypeNumber,
+			FieldTypeEmail,
+			FieldTypeUrl,
+			FieldTypeColor,
+			FieldTypeJson,
+			FieldTypeGeoPoint,
+			FieldTypeGroup,
+			FieldTypeSlug,
 		)),
+		validation.Field(&m.Searchable, validation.By(checkSearchable(m.Type))),
 		validation.Field(&m.Meta),
+		validation.Field(&m.Default, validation.By(checkDefault(m))),
 	)
 }
 
+// checkDefault checks whether the validating default value is a valid `field` value
+// (or a dynamic default supported by the field type).
+func checkDefault(field CollectionField) validation.RuleFunc {
+	return func(value interface{}) error {
+		if isEmptyDefault(value) {
+			return nil
+		}
+
+		switch value {
+		case FieldDefaultNow:
+			if field.Type != FieldTypeDate {
+				return errors.New("The \"" + FieldDefaultNow + "\" default is supported only by date fields.")
+			}
+
+			return nil
+		case FieldDefaultCurrentUser:
+			if field.Type != FieldTypePlain {
+				return errors.New("The \"" + FieldDefaultCurrentUser + "\" default is supported only by plain fields.")
+			}
+
+			return nil
+		}
+
+		// non-empty value that is casted to an empty one doesn't match the field type
+		// (the switch fields are casted always to a non-empty bool)
+		_, isBool := value.(bool)
+		casted := field.CastValue(value)
+		if field.IsEmptyValue(casted) || (field.Type == FieldTypeSwitch && !isBool) {
+			return errors.New("The default value doesn't match the field type.")
+		}
+
+		return field.ValidateValue(casted)
+	}
+}
+
+// isEmptyDefault checks whether the provided default value is not set
+// (aka. nil, empty string or empty list/object).
+func isEmptyDefault(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// DefaultValue returns the casted field default value.
+// The dynamic defaults are resolved on call - `FieldDefaultNow` to the current
+// unix timestamp and `FieldDefaultCurrentUser` to the `author` identity id.
+func (m CollectionField) DefaultValue(author RevisionAuthor) interface{} {
+	switch m.Default {
+	case FieldDefaultNow:
+		return m.CastValue(int(time.Now().Unix()))
+	case FieldDefaultCurrentUser:
+		return m.CastValue(author.ID)
+	}
+
+	return m.CastValue(m.Default)
+}
+
+// checkSearchable checks whether a field with the specified type could be marked as searchable.
+func checkSearchable(fieldType string) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(bool)
+
+		if v && fieldType != FieldTypePlain && fieldType != FieldTypeEditor {
+			return errors.New("Only plain and editor fields could be searchable.")
+		}
+
+		return nil
+	}
+}
+
 // metaInit normalizes and initializes collection meta field properties.
 func (m *CollectionField) metaInit() (err error) {
 	if m.Type == FieldTypePlain {
@@ -226,6 +507,22 @@ func (m *CollectionField) metaInit() (err error) {
 		m.Meta, err = NewMetaMedia(m.Meta)
 	} else if m.Type == FieldTypeRelation {
 		m.Meta, err = NewMetaRelation(m.Meta)
+	} else if m.Type == FieldTypeNumber {
+		m.Meta, err = NewMetaNumber(m.Meta)
+	} else if m.Type == FieldTypeEmail {
+		m.Meta, err = NewMetaEmail(m.Meta)
+	} else if m.Type == FieldTypeUrl {
+		m.Meta, err = NewMetaUrl(m.Meta)
+	} else if m.Type == FieldTypeColor {
+		m.Meta, err = NewMetaColor(m.Meta)
+	} else if m.Type == FieldTypeJson {
+		m.Meta, err = NewMetaJson(m.Meta)
+	} else if m.Type == FieldTypeGeoPoint {
+		m.Meta, err = NewMetaGeoPoint(m.Meta)
+	} else if m.Type == FieldTypeGroup {
+		m.Meta, err = NewMetaGroup(m.Meta)
+	} else if m.Type == FieldTypeSlug {
+		m.Meta, err = NewMetaSlug(m.Meta)
 	}
 
 	return err
@@ -236,8 +533,59 @@ func (m CollectionField) CastValue(value interface{}) interface{} {
 	var result interface{}
 
 	switch m.Type {
-	case FieldTypePlain, FieldTypeSelect, FieldTypeEditor:
+	case FieldTypePlain, FieldTypeSelect, FieldTypeEditor, FieldTypeUrl:
 		result, _ = value.(string)
+	case FieldTypeEmail, FieldTypeColor:
+		v, _ := value.(string)
+		result = strings.ToLower(strings.TrimSpace(v))
+	case FieldTypeSlug:
+		v, _ := value.(string)
+		result = utils.Slugify(v)
+	case FieldTypeNumber:
+		result = nil
+		if v, ok := toFloat(value); ok {
+			meta, _ := NewMetaNumber(m.Meta)
+
+			if meta.Precision <= 0 {
+				result = int(math.Round(v))
+			} else {
+				pow := math.Pow(10, float64(meta.Precision))
+				result = math.Round(v*pow) / pow
+			}
+		}
+	case FieldTypeJson:
+		result = nil
+		switch v := value.(type) {
+		case map[string]interface{}:
+			result = v
+		case bson.M:
+			result = map[string]interface{}(v)
+		case string:
+			data := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(v), &data); err == nil {
+				result = data
+			}
+		}
+	case FieldTypeGeoPoint:
+		result = nil
+		if point, ok := ParseGeoPoint(value); ok {
+			result = *point
+		}
+	case FieldTypeGroup:
+		meta, _ := NewMetaGroup(m.Meta)
+
+		items := []map[string]interface{}{}
+		for _, item := range GroupItems(value) {
+			items = append(items, meta.CastItem(item))
+		}
+
+		if meta.Repeat {
+			result = items
+		} else if len(items) > 0 {
+			result = items[0]
+		} else {
+			result = meta.CastItem(nil)
+		}
 	case FieldTypeDate:
 		result = nil
 		switch v := value.(type) {
@@ -290,8 +638,25 @@ func (m CollectionField) IsEmptyValue(value interface{}) bool {
 	castedVal := m.CastValue(value)
 
 	switch m.Type {
-	case FieldTypePlain, FieldTypeSelect, FieldTypeEditor:
+	case FieldTypePlain, FieldTypeSelect, FieldTypeEditor, FieldTypeEmail, FieldTypeUrl, FieldTypeColor, FieldTypeSlug:
 		return castedVal.(string) == ""
+	case FieldTypeJson:
+		return castedVal == nil || len(castedVal.(map[string]interface{})) == 0
+	case FieldTypeGroup:
+		if items, isSlice := castedVal.([]map[string]interface{}); isSlice {
+			return len(items) == 0
+		}
+
+		// single group is empty only if all of its nested fields are empty
+		meta, _ := NewMetaGroup(m.Meta)
+		item := castedVal.(map[string]interface{})
+		for _, field := range meta.Fields {
+			if !field.IsEmptyValue(item[field.Key]) {
+				return false
+			}
+		}
+
+		return true
 	case FieldTypeDate:
 		return castedVal == nil || castedVal.(int) == 0
 	case FieldTypeMedia, FieldTypeRelation:
@@ -321,13 +686,172 @@ func (m CollectionField) IsEmptyValue(value interface{}) bool {
 	}
 }
 
+// ValidateValue checks whether the provided casted (!!!) field value satisfies the field meta rules
+// (length, pattern, date range, selected options, etc.). Empty values are not checked.
+func (m CollectionField) ValidateValue(value interface{}) error {
+	if m.IsEmptyValue(value) {
+		return nil
+	}
+
+	switch m.Type {
+	case FieldTypePlain:
+		meta, err := NewMetaPlain(m.Meta)
+		if err != nil {
+			return err
+		}
+
+		return validateTextValue(value.(string), meta.MinLength, meta.MaxLength, meta.Pattern)
+	case FieldTypeEditor:
+		meta, err := NewMetaEditor(m.Meta)
+		if err != nil {
+			return err
+		}
+
+		return validateTextValue(value.(string), meta.MinLength, meta.MaxLength, meta.Pattern)
+	case FieldTypeDate:
+		meta, err := NewMetaDate(m.Meta)
+		if err != nil {
+			return err
+		}
+
+		v := value.(int)
+
+		if meta.Min != 0 && v < meta.Min {
+			return fmt.Errorf("The date must be after %s.", formatMetaDate(meta.Min))
+		}
+
+		if meta.Max != 0 && v > meta.Max {
+			return fmt.Errorf("The date must be before %s.", formatMetaDate(meta.Max))
+		}
+	case FieldTypeChecklist:
+		meta, err := NewMetaChecklist(m.Meta)
+		if err != nil {
+			return err
+		}
+
+		total := len(value.([]string))
+
+		if meta.Min != 0 && total < meta.Min {
+			return fmt.Errorf("Select at least %d option(s).", meta.Min)
+		}
+
+		if meta.Max != 0 && total > meta.Max {
+			return fmt.Errorf("Select no more than %d option(s).", meta.Max)
+		}
+	case FieldTypeEmail:
+		if err := is.Email.Validate(value); err != nil {
+			return errors.New("The value must be a valid email address.")
+		}
+	case FieldTypeUrl:
+		if err := is.URL.Validate(value); err != nil {
+			return errors.New("The value must be a valid url.")
+		}
+	case FieldTypeColor:
+		if !colorRegex.MatchString(value.(string)) {
+			return errors.New("The value must be a valid hex color (eg. #ff0000).")
+		}
+	case FieldTypeGeoPoint:
+		if point, ok := value.(GeoPoint); !ok || !point.IsValid() {
+			return errors.New("The value must be a valid latitude and longitude pair.")
+		}
+	case FieldTypeGroup:
+		meta, err := NewMetaGroup(m.Meta)
+		if err != nil {
+			return err
+		}
+
+		if !meta.Repeat {
+			return nil
+		}
+
+		total := len(GroupItems(value))
+
+		if meta.Min != 0 && total < meta.Min {
+			return fmt.Errorf("Add at least %d item(s).", meta.Min)
+		}
+
+		if meta.Max != 0 && total > meta.Max {
+			return fmt.Errorf("Add no more than %d item(s).", meta.Max)
+		}
+	}
+
+	return nil
+}
+
+// GroupItems normalizes and returns the group field value item(s).
+// NB! The returned items share the same underlying data with the provided value.
+func GroupItems(value interface{}) []map[string]interface{} {
+	result := []map[string]interface{}{}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result = append(result, v)
+	case bson.M:
+		result = append(result, map[string]interface{}(v))
+	case []map[string]interface{}:
+		result = append(result, v...)
+	case []bson.M:
+		for _, item := range v {
+			result = append(result, map[string]interface{}(item))
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, GroupItems(item)...)
+		}
+	}
+
+	return result
+}
+
+// CastItem returns normalized single group item data based on the group nested fields.
+func (m MetaGroup) CastItem(item map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	for _, field := range m.Fields {
+		result[field.Key] = field.CastValue(item[field.Key])
+	}
+
+	return result
+}
+
+// validateTextValue checks the length and format of a plain/editor field value.
+func validateTextValue(value string, minLength int, maxLength int, pattern string) error {
+	length := utf8.RuneCountInString(value)
+
+	if minLength != 0 && length < minLength {
+		return fmt.Errorf("The value must be at least %d characters long.", minLength)
+	}
+
+	if maxLength != 0 && length > maxLength {
+		return fmt.Errorf("The value must be no more than %d characters long.", maxLength)
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(value) {
+			return errors.New("The value doesn't match the required format.")
+		}
+	}
+
+	return nil
+}
+
+// formatMetaDate formats date meta timestamp for error messages.
+func formatMetaDate(timestamp int) string {
+	return time.Unix(int64(timestamp), 0).UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
 // -------------------------------------------------------------------
 // • Meta field validations
 // -------------------------------------------------------------------
 
 // Validate validates the plain collection field meta properties.
 func (m MetaPlain) Validate() error {
-	return nil
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.MinLength, validation.Min(0)),
+		validation.Field(&m.MaxLength, validation.Min(0), validation.By(checkMinMax(m.MinLength))),
+		validation.Field(&m.Pattern, validation.By(checkRegexPattern)),
+	)
 }
 
 func (m MetaSwitch) Validate() error {
@@ -338,6 +862,8 @@ func (m MetaSwitch) Validate() error {
 func (m MetaChecklist) Validate() error {
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.Options, validation.Required),
+		validation.Field(&m.Min, validation.Min(0), validation.Max(len(m.Options))),
+		validation.Field(&m.Max, validation.Min(0), validation.Max(len(m.Options)), validation.By(checkMinMax(m.Min))),
 	)
 }
 
@@ -371,6 +897,7 @@ func (m MetaDate) Validate() error {
 			MetaDateModeDate,
 			MetaDateModeDateTime,
 		)),
+		validation.Field(&m.Max, validation.By(checkMinMax(m.Min))),
 	)
 }
 
@@ -381,21 +908,107 @@ func (m MetaEditor) Validate() error {
 			MetaEditorModeRich,
 			MetaEditorModeSimple,
 		)),
+		validation.Field(&m.MinLength, validation.Min(0)),
+		validation.Field(&m.MaxLength, validation.Min(0), validation.By(checkMinMax(m.MinLength))),
+		validation.Field(&m.Pattern, validation.By(checkRegexPattern)),
 	)
 }
 
 // Validate validates the media collection field meta properties.
 func (m MetaMedia) Validate() error {
-	return nil
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.OnDelete, validation.In(
+			MetaOnDeleteNullify,
+			MetaOnDeleteRestrict,
+			MetaOnDeleteCascade,
+		)),
+	)
 }
 
 // Validate validates the relation collection field meta properties.
 func (m MetaRelation) Validate() error {
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.CollectionID, validation.Required),
+		validation.Field(&m.OnDelete, validation.In(
+			MetaOnDeleteNullify,
+			MetaOnDeleteRestrict,
+			MetaOnDeleteCascade,
+		)),
+	)
+}
+
+// Validate validates the number collection field meta properties.
+func (m MetaNumber) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Precision, validation.Min(0), validation.Max(MetaNumberMaxPrecision)),
+	)
+}
+
+// Validate validates the email collection field meta properties.
+func (m MetaEmail) Validate() error {
+	return nil
+}
+
+// Validate validates the url collection field meta properties.
+func (m MetaUrl) Validate() error {
+	return nil
+}
+
+// Validate validates the color collection field meta properties.
+func (m MetaColor) Validate() error {
+	return nil
+}
+
+// Validate validates the json collection field meta properties.
+func (m MetaJson) Validate() error {
+	return nil
+}
+
+// Validate validates the geopoint collection field meta properties.
+func (m MetaGeoPoint) Validate() error {
+	return nil
+}
+
+// Validate validates the group collection field meta properties.
+func (m MetaGroup) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Fields, validation.Required, validation.By(uniqueFieldKey)),
+		validation.Field(&m.Min, validation.Min(0)),
+		validation.Field(&m.Max, validation.Min(0), validation.By(checkMinMax(m.Min))),
+	)
+}
+
+// Validate validates the slug collection field meta properties.
+func (m MetaSlug) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Source, validation.Required, validation.Match(regexp.MustCompile(`^\w+$`))),
 	)
 }
 
+// checkMinMax checks whether the validating max value is not less than `min` (if both are set).
+func checkMinMax(min int) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(int)
+
+		if v != 0 && min != 0 && v < min {
+			return errors.New("Must be greater or equal to the min value.")
+		}
+
+		return nil
+	}
+}
+
+// checkRegexPattern checks whether the validating value is a valid regular expression.
+func checkRegexPattern(value interface{}) error {
+	v, _ := value.(string)
+
+	if _, err := regexp.Compile(v); err != nil {
+		return errors.New("Must be a valid regular expression.")
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------
 // • Meta field constructors and helpers
 // -------------------------------------------------------------------
@@ -474,6 +1087,78 @@ func NewMetaRelation(data interface{}) (*MetaRelation, error) {
 	return meta, err
 }
 
+// NewMetaNumber creates and returns new MetaNumber instance.
+func NewMetaNumber(data interface{}) (*MetaNumber, error) {
+	meta := &MetaNumber{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaEmail creates and returns new MetaEmail instance.
+func NewMetaEmail(data interface{}) (*MetaEmail, error) {
+	meta := &MetaEmail{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaUrl creates and returns new MetaUrl instance.
+func NewMetaUrl(data interface{}) (*MetaUrl, error) {
+	meta := &MetaUrl{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaColor creates and returns new MetaColor instance.
+func NewMetaColor(data interface{}) (*MetaColor, error) {
+	meta := &MetaColor{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaJson creates and returns new MetaJson instance.
+func NewMetaJson(data interface{}) (*MetaJson, error) {
+	meta := &MetaJson{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaGeoPoint creates and returns new MetaGeoPoint instance.
+func NewMetaGeoPoint(data interface{}) (*MetaGeoPoint, error) {
+	meta := &MetaGeoPoint{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaGroup creates and returns new MetaGroup instance.
+func NewMetaGroup(data interface{}) (*MetaGroup, error) {
+	meta := &MetaGroup{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
+// NewMetaSlug creates and returns new MetaSlug instance.
+func NewMetaSlug(data interface{}) (*MetaSlug, error) {
+	meta := &MetaSlug{}
+
+	err := decodeMetaHandler(data, meta)
+
+	return meta, err
+}
+
 // decodeMetaHandler unmarshalizes `data` into the provided `meta` struct.
 func decodeMetaHandler(data interface{}, meta MetaFieldInterface) error {
 	if data == nil {
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/globalsign/mgo/bson"
 )
@@ -88,6 +92,10 @@ func TestCollectionForm_ResolveModel(t *testing.T) {
 			}
 		}
 
+		if resolvedModel.HookSecret == "" {
+			t.Error("Expected resolved model HookSecret to be generated")
+		}
+
 		if scenario.Model == nil { // new
 			if resolvedModel.ID.Hex() == "" {
 				t.Error("Expected resolved model id to be set")
@@ -131,6 +139,8 @@ func TestCollectionForm_Validate(t *testing.T) {
 		CreateHook: "invalid_url",
 		UpdateHook: "invalid_url",
 		DeleteHook: "invalid_url",
+		HookSecret: "short",
+		Renames:    map[string]string{"old_key": "duplicate_key"},
 	}
 
 	// valid populated form
@@ -146,10 +156,40 @@ func TestCollectionForm_Validate(t *testing.T) {
 		DeleteHook: "http://test.dev/delete",
 	}
 
+	existingModel := &Collection{
+		Fields: []CollectionField{
+			{Key: "old_key", Type: FieldTypePlain, Label: "test"},
+		},
+	}
+
+	// invalid renames
+	f4 := &CollectionForm{
+		Model: existingModel,
+		Title: "Test title",
+		Name:  "test",
+		Fields: []CollectionField{
+			{Key: "new_key", Type: FieldTypePlain, Label: "test"},
+		},
+		Renames: map[string]string{"missing": "new_key"},
+	}
+
+	// valid renames
+	f5 := &CollectionForm{
+		Model: existingModel,
+		Title: "Test title",
+		Name:  "test",
+		Fields: []CollectionField{
+			{Key: "new_key", Type: FieldTypePlain, Label: "test"},
+		},
+		Renames: map[string]string{"old_key": "new_key"},
+	}
+
 	testScenarios := []TestValidateScenario{
 		{f1, []string{"title", "name", "fields"}},
-		{f2, []string{"name", "create_hook", "update_hook", "delete_hook", "fields"}},
+		{f2, []string{"name", "create_hook", "update_hook", "delete_hook", "hook_secret", "fields", "renames"}},
 		{f3, []string{}},
+		{f4, []string{"renames"}},
+		{f5, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -181,6 +221,41 @@ func TestUniqueFieldKey(t *testing.T) {
 	}
 }
 
+func TestCheckSlugSources(t *testing.T) {
+	testScenarios := []struct {
+		Fields      []CollectionField
+		ExpectError bool
+	}{
+		{[]CollectionField{}, false},
+		{[]CollectionField{{Key: "title", Type: FieldTypePlain}}, false},
+		// missing source
+		{[]CollectionField{
+			{Key: "title", Type: FieldTypePlain},
+			{Key: "slug", Type: FieldTypeSlug, Meta: map[string]interface{}{"source": "missing"}},
+		}, true},
+		// non plain source
+		{[]CollectionField{
+			{Key: "content", Type: FieldTypeEditor},
+			{Key: "slug", Type: FieldTypeSlug, Meta: map[string]interface{}{"source": "content"}},
+		}, true},
+		// valid source
+		{[]CollectionField{
+			{Key: "slug", Type: FieldTypeSlug, Meta: map[string]interface{}{"source": "title"}},
+			{Key: "title", Type: FieldTypePlain},
+		}, false},
+	}
+
+	for i, scenario := range testScenarios {
+		err := checkSlugSources(scenario.Fields)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("(%d) Expected error, got nil", i)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("(%d) Expected nil, got error %v", i, err)
+		}
+	}
+}
+
 func TestCollectionField_Validate(t *testing.T) {
 	// empty model
 	m1 := &CollectionField{}
@@ -221,16 +296,91 @@ func TestCollectionField_Validate(t *testing.T) {
 		Meta:         nil,
 	}
 
+	// invalid searchable field type
+	m5 := &CollectionField{
+		Key:        "key",
+		Type:       FieldTypeSwitch,
+		Label:      "Test",
+		Searchable: true,
+	}
+
+	// valid searchable model
+	m6 := &CollectionField{
+		Key:        "key",
+		Type:       FieldTypeEditor,
+		Label:      "Test",
+		Searchable: true,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"key", "type", "label"}},
 		{m2, []string{"key", "type", "label"}},
 		{m3, []string{"meta"}},
 		{m4, []string{}},
+		{m5, []string{"searchable"}},
+		{m6, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
 }
 
+func TestCollectionField_Validate_Default(t *testing.T) {
+	dateMeta := map[string]interface{}{"mode": MetaDateModeDate, "min": 100}
+	plainMeta := map[string]interface{}{"max_length": 3}
+
+	testScenarios := []TestValidateScenario{
+		// empty defaults
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeSwitch, Default: ""}, []string{}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeMedia, Default: []interface{}{}}, []string{}},
+		// type mismatch
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeNumber, Default: "abc"}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeSwitch, Default: "abc"}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeNumber, Default: 0}, []string{}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeSwitch, Default: false}, []string{}},
+		// meta rules
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypePlain, Meta: plainMeta, Default: "abcd"}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypePlain, Meta: plainMeta, Default: "abc"}, []string{}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeDate, Meta: dateMeta, Default: 50}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeDate, Meta: dateMeta, Default: 150}, []string{}},
+		// dynamic defaults
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypePlain, Default: FieldDefaultNow}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeDate, Meta: dateMeta, Default: FieldDefaultNow}, []string{}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypeDate, Meta: dateMeta, Default: FieldDefaultCurrentUser}, []string{"default"}},
+		{&CollectionField{Key: "key", Label: "Test", Type: FieldTypePlain, Default: FieldDefaultCurrentUser}, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestCollectionField_DefaultValue(t *testing.T) {
+	author := RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
+
+	testScenarios := []struct {
+		Field    CollectionField
+		Expected interface{}
+	}{
+		{CollectionField{Type: FieldTypePlain}, ""},
+		{CollectionField{Type: FieldTypePlain, Default: "test"}, "test"},
+		{CollectionField{Type: FieldTypeNumber, Default: 1.6}, 2},
+		{CollectionField{Type: FieldTypeChecklist, Default: []interface{}{"a", "b"}}, []string{"a", "b"}},
+		{CollectionField{Type: FieldTypeSwitch, Default: true}, true},
+		{CollectionField{Type: FieldTypePlain, Default: FieldDefaultCurrentUser}, author.ID},
+	}
+
+	for i, scenario := range testScenarios {
+		if result := scenario.Field.DefaultValue(author); !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("(%d) Expected %v, got %v", i, scenario.Expected, result)
+		}
+	}
+
+	// now
+	before := int(time.Now().Unix())
+	result, _ := CollectionField{Type: FieldTypeDate, Default: FieldDefaultNow}.DefaultValue(author).(int)
+	if result < before || result > int(time.Now().Unix()) {
+		t.Errorf("Expected the current timestamp, got %d", result)
+	}
+}
+
 func TestCollectionField_CastValue(t *testing.T) {
 	fieldPlain := CollectionField{Type: FieldTypePlain}
 	fieldSwitch := CollectionField{Type: FieldTypeSwitch}
@@ -240,6 +390,26 @@ func TestCollectionField_CastValue(t *testing.T) {
 	fieldEditor := CollectionField{Type: FieldTypeEditor}
 	fieldMedia := CollectionField{Type: FieldTypeMedia}
 	fieldRelation := CollectionField{Type: FieldTypeRelation}
+	fieldNumber := CollectionField{Type: FieldTypeNumber}
+	fieldNumberPrecision := CollectionField{Type: FieldTypeNumber, Meta: map[string]interface{}{"precision": 2}}
+	fieldEmail := CollectionField{Type: FieldTypeEmail}
+	fieldUrl := CollectionField{Type: FieldTypeUrl}
+	fieldColor := CollectionField{Type: FieldTypeColor}
+	fieldSlug := CollectionField{Type: FieldTypeSlug}
+	fieldJson := CollectionField{Type: FieldTypeJson}
+	fieldGeoPoint := CollectionField{Type: FieldTypeGeoPoint}
+	fieldGroup := CollectionField{Type: FieldTypeGroup, Meta: map[string]interface{}{
+		"fields": []map[string]interface{}{
+			{"key": "title", "type": FieldTypePlain},
+			{"key": "count", "type": FieldTypeNumber},
+		},
+	}}
+	fieldRepeatGroup := CollectionField{Type: FieldTypeGroup, Meta: map[string]interface{}{
+		"fields": []map[string]interface{}{
+			{"key": "title", "type": FieldTypePlain},
+		},
+		"repeat": true,
+	}}
 
 	testScenarios := []struct {
 		Field    CollectionField
@@ -289,6 +459,49 @@ func TestCollectionField_CastValue(t *testing.T) {
 		{fieldRelation, []string{"507f191e810c19729de860ea"}, []bson.ObjectId{}},
 		{fieldRelation, []interface{}{"507f191e810c19729de860ea", ""}, []bson.ObjectId{bson.ObjectIdHex("507f191e810c19729de860ea")}},
 		{fieldRelation, []bson.ObjectId{bson.ObjectIdHex("507f191e810c19729de860ea")}, []bson.ObjectId{bson.ObjectIdHex("507f191e810c19729de860ea")}},
+		// number
+		{fieldNumber, nil, nil},
+		{fieldNumber, "test", nil},
+		{fieldNumber, "1.6", 2},
+		{fieldNumber, 12.4, 12},
+		{fieldNumber, 0, 0},
+		{fieldNumberPrecision, "1.555", 1.56},
+		{fieldNumberPrecision, 3, 3.0},
+		// email
+		{fieldEmail, nil, ""},
+		{fieldEmail, 123, ""},
+		{fieldEmail, " Test@Example.com ", "test@example.com"},
+		// url
+		{fieldUrl, nil, ""},
+		{fieldUrl, 123, ""},
+		{fieldUrl, "http://example.com", "http://example.com"},
+		// color
+		{fieldColor, nil, ""},
+		{fieldColor, 123, ""},
+		{fieldColor, "#FF0000", "#ff0000"},
+		// slug
+		{fieldSlug, nil, ""},
+		{fieldSlug, 123, ""},
+		{fieldSlug, " Hello World! ", "hello-world"},
+		// json
+		{fieldJson, nil, nil},
+		{fieldJson, 123, nil},
+		{fieldJson, "invalid", nil},
+		{fieldJson, `{"a": 1}`, map[string]interface{}{"a": 1.0}},
+		{fieldJson, bson.M{"a": "b"}, map[string]interface{}{"a": "b"}},
+		// geopoint
+		{fieldGeoPoint, nil, nil},
+		{fieldGeoPoint, "invalid", nil},
+		{fieldGeoPoint, "42.5, 23.3", *NewGeoPoint(42.5, 23.3)},
+		{fieldGeoPoint, map[string]interface{}{"lat": 42.5, "lng": 23.3}, *NewGeoPoint(42.5, 23.3)},
+		{fieldGeoPoint, bson.M{"type": "Point", "coordinates": []interface{}{23.3, 42.5}}, *NewGeoPoint(42.5, 23.3)},
+		// group
+		{fieldGroup, nil, map[string]interface{}{"title": "", "count": nil}},
+		{fieldGroup, "test", map[string]interface{}{"title": "", "count": nil}},
+		{fieldGroup, bson.M{"title": "test", "count": "2", "missing": 1}, map[string]interface{}{"title": "test", "count": 2}},
+		{fieldRepeatGroup, nil, []map[string]interface{}{}},
+		{fieldRepeatGroup, map[string]interface{}{"title": "test"}, []map[string]interface{}{{"title": "test"}}},
+		{fieldRepeatGroup, []interface{}{bson.M{"title": "test1"}, "invalid", bson.M{"title": 123}}, []map[string]interface{}{{"title": "test1"}, {"title": ""}}},
 	}
 
 	for _, scenario := range testScenarios {
@@ -338,6 +551,8 @@ func TestCollectionField_CastValue(t *testing.T) {
 			}
 		case FieldTypeSwitch:
 			hasError = result.(bool) != scenario.Expected.(bool)
+		case FieldTypeJson, FieldTypeGeoPoint, FieldTypeGroup:
+			hasError = !reflect.DeepEqual(result, scenario.Expected)
 		default:
 			hasError = result != scenario.Expected
 		}
@@ -357,6 +572,24 @@ func TestCollectionField_IsEmptyValue(t *testing.T) {
 	fieldEditor := CollectionField{Type: FieldTypeEditor}
 	fieldMedia := CollectionField{Type: FieldTypeMedia}
 	fieldRelation := CollectionField{Type: FieldTypeRelation}
+	fieldNumber := CollectionField{Type: FieldTypeNumber}
+	fieldEmail := CollectionField{Type: FieldTypeEmail}
+	fieldUrl := CollectionField{Type: FieldTypeUrl}
+	fieldColor := CollectionField{Type: FieldTypeColor}
+	fieldSlug := CollectionField{Type: FieldTypeSlug}
+	fieldJson := CollectionField{Type: FieldTypeJson}
+	fieldGeoPoint := CollectionField{Type: FieldTypeGeoPoint}
+	fieldGroup := CollectionField{Type: FieldTypeGroup, Meta: map[string]interface{}{
+		"fields": []map[string]interface{}{
+			{"key": "title", "type": FieldTypePlain},
+		},
+	}}
+	fieldRepeatGroup := CollectionField{Type: FieldTypeGroup, Meta: map[string]interface{}{
+		"fields": []map[string]interface{}{
+			{"key": "title", "type": FieldTypePlain},
+		},
+		"repeat": true,
+	}}
 
 	testScenarios := []struct {
 		Field    CollectionField
@@ -417,6 +650,43 @@ func TestCollectionField_IsEmptyValue(t *testing.T) {
 		{fieldRelation, []interface{}{}, true},
 		{fieldRelation, []interface{}{"507f191e810c19729de860ea"}, false},
 		{fieldRelation, []bson.ObjectId{bson.ObjectIdHex("507f191e810c19729de860ea")}, false},
+		// number
+		{fieldNumber, nil, true},
+		{fieldNumber, "test", true},
+		{fieldNumber, 0, false},
+		{fieldNumber, "1.5", false},
+		// email
+		{fieldEmail, nil, true},
+		{fieldEmail, "", true},
+		{fieldEmail, "test@example.com", false},
+		// url
+		{fieldUrl, nil, true},
+		{fieldUrl, "", true},
+		{fieldUrl, "http://example.com", false},
+		// color
+		{fieldColor, nil, true},
+		{fieldColor, "", true},
+		{fieldColor, "#fff", false},
+		// slug
+		{fieldSlug, nil, true},
+		{fieldSlug, " -- ", true},
+		{fieldSlug, "test", false},
+		// json
+		{fieldJson, nil, true},
+		{fieldJson, "{}", true},
+		{fieldJson, map[string]interface{}{}, true},
+		{fieldJson, map[string]interface{}{"a": 1}, false},
+		// geopoint
+		{fieldGeoPoint, nil, true},
+		{fieldGeoPoint, "test", true},
+		{fieldGeoPoint, "42.5,23.3", false},
+		// group
+		{fieldGroup, nil, true},
+		{fieldGroup, map[string]interface{}{"title": ""}, true},
+		{fieldGroup, map[string]interface{}{"title": "test"}, false},
+		{fieldRepeatGroup, nil, true},
+		{fieldRepeatGroup, []interface{}{}, true},
+		{fieldRepeatGroup, []interface{}{map[string]interface{}{"title": ""}}, false},
 	}
 
 	for _, scenario := range testScenarios {
@@ -428,11 +698,131 @@ func TestCollectionField_IsEmptyValue(t *testing.T) {
 	}
 }
 
+func TestCollectionField_ValidateValue(t *testing.T) {
+	fieldPlain := CollectionField{Type: FieldTypePlain, Meta: map[string]interface{}{
+		"min_length": 2,
+		"max_length": 5,
+		"pattern":    "^[a-z-]+$",
+	}}
+	fieldEditor := CollectionField{Type: FieldTypeEditor, Meta: map[string]interface{}{
+		"mode":       MetaEditorModeSimple,
+		"max_length": 3,
+	}}
+	fieldDate := CollectionField{Type: FieldTypeDate, Meta: map[string]interface{}{
+		"mode": MetaDateModeDate,
+		"min":  100,
+		"max":  200,
+	}}
+	fieldChecklist := CollectionField{Type: FieldTypeChecklist, Meta: map[string]interface{}{
+		"options": []map[string]interface{}{
+			{"name": "name1", "value": "value1"},
+			{"name": "name2", "value": "value2"},
+			{"name": "name3", "value": "value3"},
+		},
+		"min": 2,
+		"max": 2,
+	}}
+	fieldSwitch := CollectionField{Type: FieldTypeSwitch}
+	fieldEmail := CollectionField{Type: FieldTypeEmail}
+	fieldUrl := CollectionField{Type: FieldTypeUrl}
+	fieldColor := CollectionField{Type: FieldTypeColor}
+	fieldGeoPoint := CollectionField{Type: FieldTypeGeoPoint}
+	fieldRepeatGroup := CollectionField{Type: FieldTypeGroup, Meta: map[string]interface{}{
+		"fields": []map[string]interface{}{
+			{"key": "title", "type": FieldTypePlain},
+		},
+		"repeat": true,
+		"min":    2,
+		"max":    3,
+	}}
+
+	testScenarios := []struct {
+		Field       CollectionField
+		Value       interface{}
+		ExpectError bool
+	}{
+		// plain
+		{fieldPlain, "", false},
+		{fieldPlain, "a", true},
+		{fieldPlain, "abcdef", true},
+		{fieldPlain, "ab c", true},
+		{fieldPlain, "ab-c", false},
+		// editor
+		{fieldEditor, "", false},
+		{fieldEditor, "тест", true},
+		{fieldEditor, "тес", false},
+		// date
+		{fieldDate, 99, true},
+		{fieldDate, 201, true},
+		{fieldDate, 100, false},
+		{fieldDate, 200, false},
+		// checklist
+		{fieldChecklist, []string{}, false},
+		{fieldChecklist, []string{"value1"}, true},
+		{fieldChecklist, []string{"value1", "value2", "value3"}, true},
+		{fieldChecklist, []string{"value1", "value2"}, false},
+		// switch
+		{fieldSwitch, true, false},
+		// email
+		{fieldEmail, "invalid", true},
+		{fieldEmail, "test@example.com", false},
+		// url
+		{fieldUrl, "invalid", true},
+		{fieldUrl, "http://example.com", false},
+		// color
+		{fieldColor, "#ff00", true},
+		{fieldColor, "red", true},
+		{fieldColor, "#ff0000", false},
+		// geopoint
+		{fieldGeoPoint, *NewGeoPoint(91, 0), true},
+		{fieldGeoPoint, *NewGeoPoint(0, -181), true},
+		{fieldGeoPoint, *NewGeoPoint(42.5, 23.3), false},
+		// repeat group
+		{fieldRepeatGroup, []map[string]interface{}{}, false},
+		{fieldRepeatGroup, []map[string]interface{}{{"title": "1"}}, true},
+		{fieldRepeatGroup, []map[string]interface{}{{"title": "1"}, {"title": "2"}, {"title": "3"}, {"title": "4"}}, true},
+		{fieldRepeatGroup, []map[string]interface{}{{"title": "1"}, {"title": "2"}}, false},
+	}
+
+	for i, scenario := range testScenarios {
+		err := scenario.Field.ValidateValue(scenario.Value)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("(%d) Expected error, got nil", i)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("(%d) Expected nil, got error %v", i, err)
+		}
+	}
+}
+
 func TestMetaPlain_Validate(t *testing.T) {
 	m1 := &MetaPlain{} // no meta
 
+	// invalid populated model
+	m2 := &MetaPlain{
+		MinLength: -1,
+		MaxLength: 1,
+		Pattern:   "[a-z",
+	}
+
+	// invalid min/max range
+	m3 := &MetaPlain{
+		MinLength: 5,
+		MaxLength: 2,
+	}
+
+	// valid populated model
+	m4 := &MetaPlain{
+		MinLength: 2,
+		MaxLength: 5,
+		Pattern:   "^[a-z]+$",
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{}},
+		{m2, []string{"min_length", "pattern"}},
+		{m3, []string{"max_length"}},
+		{m4, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -468,10 +858,32 @@ func TestMetaChecklist_Validate(t *testing.T) {
 		},
 	}
 
+	// invalid min/max selected options
+	m4 := &MetaChecklist{
+		Options: []MetaChecklistOption{
+			{Name: "name1", Value: "value1"},
+			{Name: "name2", Value: "value2"},
+		},
+		Min: 3,
+		Max: 1,
+	}
+
+	// valid min/max selected options
+	m5 := &MetaChecklist{
+		Options: []MetaChecklistOption{
+			{Name: "name1", Value: "value1"},
+			{Name: "name2", Value: "value2"},
+		},
+		Min: 1,
+		Max: 2,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"options"}},
 		{m2, []string{"options"}},
 		{m3, []string{}},
+		{m4, []string{"min", "max"}},
+		{m5, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -556,10 +968,18 @@ func TestMetaDate_Validate(t *testing.T) {
 		Mode: MetaDateModeDateTime,
 	}
 
+	// invalid min/max range
+	m4 := &MetaDate{
+		Mode: MetaDateModeDateTime,
+		Min:  200,
+		Max:  100,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"mode"}},
 		{m2, []string{"mode"}},
 		{m3, []string{}},
+		{m4, []string{"max"}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -579,10 +999,19 @@ func TestMetaEditor_Validate(t *testing.T) {
 		Mode: MetaEditorModeRich,
 	}
 
+	// invalid length and pattern rules
+	m4 := &MetaEditor{
+		Mode:      MetaEditorModeRich,
+		MinLength: 10,
+		MaxLength: 5,
+		Pattern:   "(",
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"mode"}},
 		{m2, []string{"mode"}},
 		{m3, []string{}},
+		{m4, []string{"max_length", "pattern"}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -592,14 +1021,22 @@ func TestMetaMedia_Validate(t *testing.T) {
 	// empty model
 	m1 := &MetaMedia{}
 
-	// populated model
+	// invalid populated model
 	m2 := &MetaMedia{
-		Max: 1,
+		Max:      1,
+		OnDelete: "invalid",
+	}
+
+	// valid populated model
+	m3 := &MetaMedia{
+		Max:      1,
+		OnDelete: MetaOnDeleteRestrict,
 	}
 
 	testScenarios := []TestValidateScenario{
 		{m1, []string{}},
-		{m2, []string{}},
+		{m2, []string{"on_delete"}},
+		{m3, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -615,9 +1052,23 @@ func TestMetaRelation_Validate(t *testing.T) {
 		CollectionID: bson.ObjectIdHex("507f191e810c19729de860ea"),
 	}
 
+	// invalid on delete policy
+	m3 := &MetaRelation{
+		CollectionID: bson.ObjectIdHex("507f191e810c19729de860ea"),
+		OnDelete:     "invalid",
+	}
+
+	// valid on delete policy
+	m4 := &MetaRelation{
+		CollectionID: bson.ObjectIdHex("507f191e810c19729de860ea"),
+		OnDelete:     MetaOnDeleteCascade,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"collection_id"}},
 		{m2, []string{}},
+		{m3, []string{"on_delete"}},
+		{m4, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -940,3 +1391,252 @@ func TestNewMetaRelation(t *testing.T) {
 		}
 	}
 }
+
+func TestCollectionForm_ResolveModel_HookSecret(t *testing.T) {
+	model := &Collection{HookSecret: "existing_secret_value"}
+
+	// keep existing
+	form1 := &CollectionForm{Model: model}
+	if resolved := form1.ResolveModel(); resolved.HookSecret != "existing_secret_value" {
+		t.Errorf("Expected the existing hook secret to be kept, got %s", resolved.HookSecret)
+	}
+
+	// replace
+	form2 := &CollectionForm{Model: model, HookSecret: "new_secret_value_123"}
+	if resolved := form2.ResolveModel(); resolved.HookSecret != "new_secret_value_123" {
+		t.Errorf("Expected the hook secret to be replaced, got %s", resolved.HookSecret)
+	}
+}
+
+func TestCollection_HookSecretJSON(t *testing.T) {
+	model := &Collection{HookSecret: "existing_secret_value"}
+
+	encoded, _ := json.Marshal(model)
+
+	if strings.Contains(string(encoded), "existing_secret_value") {
+		t.Error("Expected the hook secret to be excluded from the json output, got", string(encoded))
+	}
+}
+
+func TestMetaNumber_Validate(t *testing.T) {
+	// empty model
+	m1 := &MetaNumber{}
+
+	// invalid populated model
+	m2 := &MetaNumber{Precision: -1}
+
+	// invalid populated model
+	m3 := &MetaNumber{Precision: MetaNumberMaxPrecision + 1}
+
+	// valid populated model
+	m4 := &MetaNumber{Precision: 2}
+
+	testScenarios := []TestValidateScenario{
+		{m1, []string{}},
+		{m2, []string{"precision"}},
+		{m3, []string{"precision"}},
+		{m4, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestNewMetaNumber(t *testing.T) {
+	meta, err := NewMetaNumber(map[string]interface{}{"precision": 3})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if meta.Precision != 3 {
+		t.Error("Expected precision 3, got", meta.Precision)
+	}
+
+	if _, err := NewMetaNumber("invalid"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestMetaSlug_Validate(t *testing.T) {
+	testScenarios := []TestValidateScenario{
+		{&MetaSlug{}, []string{"source"}},
+		{&MetaSlug{Source: "invalid source"}, []string{"source"}},
+		{&MetaSlug{Source: "title"}, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestNewMetaSlug(t *testing.T) {
+	meta, err := NewMetaSlug(map[string]interface{}{"source": "title"})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if meta.Source != "title" {
+		t.Error("Expected title source, got", meta.Source)
+	}
+
+	if _, err := NewMetaSlug("invalid"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestGroupItems(t *testing.T) {
+	testScenarios := []struct {
+		Value         interface{}
+		ExpectedTotal int
+	}{
+		{nil, 0},
+		{"test", 0},
+		{map[string]interface{}{"a": 1}, 1},
+		{bson.M{"a": 1}, 1},
+		{[]bson.M{{"a": 1}, {"a": 2}}, 2},
+		{[]map[string]interface{}{{"a": 1}, {"a": 2}}, 2},
+		{[]interface{}{bson.M{"a": 1}, "test", map[string]interface{}{"a": 2}}, 2},
+	}
+
+	for i, scenario := range testScenarios {
+		result := GroupItems(scenario.Value)
+
+		if len(result) != scenario.ExpectedTotal {
+			t.Errorf("(%d) Expected %d items, got %d", i, scenario.ExpectedTotal, len(result))
+		}
+	}
+
+	// check whether the items share the same underlying data
+	value := bson.M{"a": 1}
+	GroupItems(value)[0]["a"] = 2
+	if value["a"] != 2 {
+		t.Error("Expected the group item to be modified, got", value)
+	}
+}
+
+func TestMetaGroup_Validate(t *testing.T) {
+	// empty model
+	m1 := &MetaGroup{}
+
+	// invalid nested fields
+	m2 := &MetaGroup{
+		Fields: []CollectionField{
+			{Key: "title", Label: "Title", Type: FieldTypePlain},
+			{Key: "title", Label: "Title", Type: "invalid"},
+		},
+	}
+
+	// invalid min/max range
+	m3 := &MetaGroup{
+		Fields: []CollectionField{
+			{Key: "title", Label: "Title", Type: FieldTypePlain},
+		},
+		Repeat: true,
+		Min:    3,
+		Max:    1,
+	}
+
+	// valid nested group
+	m4 := &MetaGroup{
+		Fields: []CollectionField{
+			{Key: "title", Label: "Title", Type: FieldTypePlain},
+			{Key: "items", Label: "Items", Type: FieldTypeGroup, Meta: map[string]interface{}{
+				"fields": []map[string]interface{}{
+					{"key": "name", "label": "Name", "type": FieldTypePlain},
+				},
+				"repeat": true,
+			}},
+		},
+		Repeat: true,
+		Min:    1,
+		Max:    5,
+	}
+
+	testScenarios := []TestValidateScenario{
+		{m1, []string{"fields"}},
+		{m2, []string{"fields"}},
+		{m3, []string{"max"}},
+		{m4, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestMetaGroup_CastItem(t *testing.T) {
+	meta := MetaGroup{
+		Fields: []CollectionField{
+			{Key: "title", Type: FieldTypePlain},
+			{Key: "active", Type: FieldTypeSwitch},
+		},
+	}
+
+	result := meta.CastItem(map[string]interface{}{"title": "test", "missing": 123})
+
+	expected := map[string]interface{}{"title": "test", "active": false}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCollection_SearchableFieldKeys(t *testing.T) {
+	model := Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Searchable: true},
+		{Key: "image", Type: FieldTypeMedia},
+		{Key: "content", Type: FieldTypeEditor, Searchable: true},
+	}}
+
+	keys := model.SearchableFieldKeys()
+
+	if len(keys) != 2 || keys[0] != "title" || keys[1] != "content" {
+		t.Error("Expected [title content], got", keys)
+	}
+
+	if keys := (Collection{}).SearchableFieldKeys(); len(keys) != 0 {
+		t.Error("Expected empty keys slice, got", keys)
+	}
+}
+
+func TestCollection_TranslatableFields(t *testing.T) {
+	model := Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		{Key: "image", Type: FieldTypeMedia, Multilingual: true},
+		{Key: "code", Type: FieldTypePlain},
+		{Key: "content", Type: FieldTypeEditor, Multilingual: true},
+	}}
+
+	fields := model.TranslatableFields()
+
+	if len(fields) != 2 || fields[0].Key != "title" || fields[1].Key != "content" {
+		t.Error("Expected title and content fields, got", fields)
+	}
+
+	if fields := (Collection{}).TranslatableFields(); len(fields) != 0 {
+		t.Error("Expected empty fields slice, got", fields)
+	}
+}
+
+func TestCollection_SharedDataPath(t *testing.T) {
+	model := Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	testScenarios := []struct {
+		Path     string
+		Expected string
+	}{
+		{"status", "status"},
+		{"data.en", "data.en"},
+		{"data.en.title", "data.en.title"},
+		{"data.en.missing", "data.en.missing"},
+		{"search.en.code", "search.en.code"},
+		{"data.en.code", "data._shared.code"},
+		{"draft.bg.code", "draft._shared.code"},
+		{"data.en.code.type", "data._shared.code.type"},
+		{"data._shared.code", "data._shared.code"},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := model.SharedDataPath(scenario.Path); result != scenario.Expected {
+			t.Errorf("Expected %s, got %s", scenario.Expected, result)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"reflect"
 	"time"
 
 	"github.com/globalsign/mgo/bson"
@@ -13,6 +15,10 @@ const (
 
 	// EntityStatusInactive specifies the inactive entity model status state.
 	EntityStatusInactive = "inactive"
+
+	// EntitySharedDataKey specifies the entity data group that holds
+	// the non-multilingual field values (shared across all locales).
+	EntitySharedDataKey = "_shared"
 )
 
 type (
@@ -22,8 +28,33 @@ type (
 		CollectionID bson.ObjectId                     `json:"collection_id" bson:"collection_id"`
 		Status       string                            `json:"status" bson:"status"`
 		Data         map[string]map[string]interface{} `json:"data" bson:"data"`
+		Draft        map[string]map[string]interface{} `json:"draft,omitempty" bson:"draft,omitempty"`
+		PublishAt    int64                             `json:"publish_at,omitempty" bson:"publish_at"`
+		UnpublishAt  int64                             `json:"unpublish_at,omitempty" bson:"unpublish_at"`
+		Published    int64                             `json:"published,omitempty" bson:"published"`
 		Created      int64                             `json:"created" bson:"created"`
 		Modified     int64                             `json:"modified" bson:"modified"`
+		SearchText   []string                          `json:"-" bson:"search_text,omitempty"`
+
+		NeedsTranslation TranslationFlags `json:"needs_translation,omitempty" bson:"needs_translation,omitempty"`
+	}
+
+	// LocalizedEntity defines a single locale representation of an Entity model
+	// with data values resolved along a language fallback chain.
+	LocalizedEntity struct {
+		ID           bson.ObjectId          `json:"id"`
+		CollectionID bson.ObjectId          `json:"collection_id"`
+		Status       string                 `json:"status"`
+		Locale       string                 `json:"locale"`
+		Data         map[string]interface{} `json:"data"`
+		DataLocales  map[string]string      `json:"data_locales"`
+		Draft        map[string]interface{} `json:"draft,omitempty"`
+		DraftLocales map[string]string      `json:"draft_locales,omitempty"`
+		PublishAt    int64                  `json:"publish_at,omitempty"`
+		UnpublishAt  int64                  `json:"unpublish_at,omitempty"`
+		Published    int64                  `json:"published,omitempty"`
+		Created      int64                  `json:"created"`
+		Modified     int64                  `json:"modified"`
 	}
 
 	// EntityForm defines the update/create form model fields.
@@ -32,6 +63,10 @@ type (
 		CollectionID bson.ObjectId                     `json:"collection_id" form:"collection_id"`
 		Status       string                            `json:"status" form:"status"`
 		Data         map[string]map[string]interface{} `json:"data" form:"data"`
+		PublishAt    int64                             `json:"publish_at" form:"publish_at"`
+		UnpublishAt  int64                             `json:"unpublish_at" form:"unpublish_at"`
+		Publish      bool                              `json:"publish" form:"publish"`
+		Author       RevisionAuthor                    `json:"-" form:"-"`
 	}
 )
 
@@ -42,9 +77,24 @@ func (m EntityForm) Validate() error {
 		validation.Field(&m.Status, validation.Required, validation.In(EntityStatusActive, EntityStatusInactive)),
 		// @see daos/entity/validateAndNormalizeData()
 		// validation.Field(&m.Data, validation.Required),
+		validation.Field(&m.PublishAt, validation.Min(0)),
+		validation.Field(&m.UnpublishAt, validation.Min(0), validation.By(checkUnpublishAt(m.PublishAt))),
 	)
 }
 
+// checkUnpublishAt checks whether the validating unpublish time is after the scheduled `publishAt` one.
+func checkUnpublishAt(publishAt int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(int64)
+
+		if v > 0 && publishAt > 0 && v <= publishAt {
+			return errors.New("Must be after the publish time.")
+		}
+
+		return nil
+	}
+}
+
 // ResolveModel resolves and returns the form Entity model.
 // If the form doesn't have an Entity model, it will instantiate a new one.
 func (m EntityForm) ResolveModel() *Entity {
@@ -63,8 +113,358 @@ func (m EntityForm) ResolveModel() *Entity {
 
 	model.CollectionID = m.CollectionID
 	model.Status = m.Status
-	model.Data = m.Data
+	model.Draft = m.Data
+	model.PublishAt = m.PublishAt
+	model.UnpublishAt = m.UnpublishAt
 	model.Modified = now
 
 	return &model
 }
+
+// Publish promotes the entity draft data into the live (published) data.
+// The entity status is not changed.
+func (m *Entity) Publish() {
+	if len(m.Draft) > 0 {
+		m.Data = map[string]map[string]interface{}{}
+
+		for locale, data := range m.Draft {
+			m.Data[locale] = map[string]interface{}{}
+
+			for key, val := range data {
+				m.Data[locale][key] = val
+			}
+		}
+	}
+
+	m.PublishAt = 0
+	m.Published = time.Now().Unix()
+}
+
+// RefreshSearchText updates the entity full-text search values based on its live data.
+func (m *Entity) RefreshSearchText(collection *Collection) {
+	m.SearchText = NewEntitySearchText(m.Data, collection)
+}
+
+// RefreshTranslationFlags updates the entity "needs translation" flags by comparing
+// its latest data (the draft, if there is one) with the provided `oldData`.
+// A translatable field is flagged for a language when its value was changed
+// in any of the language source locales, but not in the language itself.
+// The source locales of a language are its fallback chain or, if it doesn't have one,
+// the default language (the first of `languages`, aka. the first created one).
+// Changing the field value in the language locale clears its flag.
+func (m *Entity) RefreshTranslationFlags(
+	oldData map[string]map[string]interface{},
+	collection *Collection,
+	languages []Language,
+) {
+	newData := m.Draft
+	if len(newData) == 0 {
+		newData = m.Data
+	}
+
+	isChanged := func(field CollectionField, locale string) bool {
+		return !reflect.DeepEqual(
+			field.CastValue(oldData[locale][field.Key]),
+			field.CastValue(newData[locale][field.Key]),
+		)
+	}
+
+	defaultLocale := ""
+	if len(languages) > 0 {
+		defaultLocale = languages[0].Locale
+	}
+
+	flags := TranslationFlags{}
+
+	for _, lang := range languages {
+		sources := lang.Fallback
+		if len(sources) == 0 && lang.Locale != defaultLocale {
+			sources = []string{defaultLocale}
+		}
+
+		keys := []string{}
+
+		for _, field := range collection.TranslatableFields() {
+			if isChanged(field, lang.Locale) {
+				continue
+			}
+
+			if m.NeedsTranslation.Has(lang.Locale, field.Key) {
+				keys = append(keys, field.Key)
+
+				continue
+			}
+
+			for _, source := range sources {
+				if isChanged(field, source) {
+					keys = append(keys, field.Key)
+
+					break
+				}
+			}
+		}
+
+		if len(keys) > 0 {
+			flags[lang.Locale] = keys
+		}
+	}
+
+	m.NeedsTranslation = flags
+}
+
+// ResolveLocale flattens the entity data (and draft) into a single locale representation.
+// The first `chain` locale is the resolved one and each of its empty field values
+// is filled with the first non-empty value from the rest of the chain locales.
+func (m Entity) ResolveLocale(chain []string, collection *Collection) *LocalizedEntity {
+	result := &LocalizedEntity{
+		ID:           m.ID,
+		CollectionID: m.CollectionID,
+		Status:       m.Status,
+		PublishAt:    m.PublishAt,
+		UnpublishAt:  m.UnpublishAt,
+		Published:    m.Published,
+		Created:      m.Created,
+		Modified:     m.Modified,
+	}
+
+	if len(chain) > 0 {
+		result.Locale = chain[0]
+	}
+
+	result.Data, result.DataLocales = resolveLocaleData(m.Data, chain, collection)
+
+	if m.Draft != nil {
+		result.Draft, result.DraftLocales = resolveLocaleData(m.Draft, chain, collection)
+	}
+
+	return result
+}
+
+// resolveLocaleData resolves the field values of a multilingual data map along the locales chain
+// and returns the flattened data together with the source locale of each value.
+func resolveLocaleData(
+	data map[string]map[string]interface{},
+	chain []string,
+	collection *Collection,
+) (map[string]interface{}, map[string]string) {
+	values := map[string]interface{}{}
+	locales := map[string]string{}
+
+	for _, field := range collection.Fields {
+		for _, locale := range chain {
+			val, ok := data[locale][field.Key]
+			if !ok {
+				// non-multilingual (shared) field value
+				val, ok = data[EntitySharedDataKey][field.Key]
+			}
+
+			if !ok {
+				continue
+			}
+
+			// keep the first existing value in case all chain values are empty
+			if _, exist := values[field.Key]; !exist {
+				values[field.Key] = val
+				locales[field.Key] = locale
+			}
+
+			if !field.IsEmptyValue(val) {
+				values[field.Key] = val
+				locales[field.Key] = locale
+
+				break
+			}
+		}
+	}
+
+	return values, locales
+}
+
+// ExpandSharedData copies the shared data group values into each entity data and draft locale group
+// and removes the shared group (aka. presents the entity data in its per locale format).
+func (m *Entity) ExpandSharedData() {
+	ExpandSharedData(m.Data)
+	ExpandSharedData(m.Draft)
+}
+
+// ExpandSharedData copies the shared group values of a multilingual data map
+// into each of its locale groups and removes the shared group.
+func ExpandSharedData(data map[string]map[string]interface{}) {
+	shared, ok := data[EntitySharedDataKey]
+	if !ok {
+		return
+	}
+
+	delete(data, EntitySharedDataKey)
+
+	for locale, _ := range data {
+		if data[locale] == nil {
+			data[locale] = map[string]interface{}{}
+		}
+
+		for key, val := range shared {
+			data[locale][key] = val
+		}
+	}
+}
+
+// CollapseSharedData moves the non-multilingual collection field values of a multilingual
+// data map from its `locales` groups into the shared group (and the multilingual ones - back
+// from the shared group into the locale groups where they are missing).
+// If the locales hold different values for a field, the first one that differs from
+// the `oldData` field value is stored (aka. the last written one), otherwise - the first locale value.
+func CollapseSharedData(
+	data map[string]map[string]interface{},
+	oldData map[string]map[string]interface{},
+	collection *Collection,
+	locales []string,
+) {
+	shared := data[EntitySharedDataKey]
+	if shared == nil {
+		shared = map[string]interface{}{}
+	}
+
+	for _, field := range collection.Fields {
+		// move back the multilingual field values
+		if field.Multilingual {
+			if val, ok := shared[field.Key]; ok {
+				for _, locale := range locales {
+					if _, exist := data[locale]; !exist {
+						data[locale] = map[string]interface{}{}
+					}
+
+					if _, exist := data[locale][field.Key]; !exist {
+						data[locale][field.Key] = val
+					}
+				}
+
+				delete(shared, field.Key)
+			}
+
+			continue
+		}
+
+		candidates := []interface{}{}
+		for _, locale := range locales {
+			if val, ok := data[locale][field.Key]; ok {
+				candidates = append(candidates, val)
+			}
+
+			delete(data[locale], field.Key)
+		}
+		if val, ok := shared[field.Key]; ok {
+			candidates = append(candidates, val)
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		value := candidates[0]
+
+		if oldValue, ok := sharedFieldValue(oldData, field.Key, locales); ok {
+			for _, candidate := range candidates {
+				if !reflect.DeepEqual(field.CastValue(candidate), field.CastValue(oldValue)) {
+					value = candidate
+
+					break
+				}
+			}
+		}
+
+		shared[field.Key] = value
+	}
+
+	if len(shared) > 0 {
+		data[EntitySharedDataKey] = shared
+	} else {
+		delete(data, EntitySharedDataKey)
+	}
+}
+
+// HasDifferentLocaleValues checks whether the `locales` groups (and the shared group)
+// of a multilingual data map hold different values for the provided field,
+// aka. whether the field values could be stored once without losing data.
+func HasDifferentLocaleValues(data map[string]map[string]interface{}, field CollectionField, locales []string) bool {
+	var first interface{}
+	found := false
+
+	for _, locale := range append([]string{EntitySharedDataKey}, locales...) {
+		val, ok := data[locale][field.Key]
+		if !ok {
+			continue
+		}
+
+		if !found {
+			first = field.CastValue(val)
+			found = true
+
+			continue
+		}
+
+		if !reflect.DeepEqual(first, field.CastValue(val)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplyFieldDefaults fills the collection field default values (see `CollectionField.DefaultValue()`)
+// for the fields without value in the multilingual data map `locales` groups.
+// The non-multilingual field defaults are filled in the shared group, but only if none of the locales has a value.
+func ApplyFieldDefaults(
+	data map[string]map[string]interface{},
+	collection *Collection,
+	locales []string,
+	author RevisionAuthor,
+) {
+	for _, field := range collection.Fields {
+		if isEmptyDefault(field.Default) {
+			continue
+		}
+
+		targets := locales
+
+		if !field.Multilingual {
+			hasValue := data[EntitySharedDataKey][field.Key] != nil
+			for _, locale := range locales {
+				hasValue = hasValue || data[locale][field.Key] != nil
+			}
+
+			if hasValue {
+				continue
+			}
+
+			targets = []string{EntitySharedDataKey}
+		}
+
+		for _, locale := range targets {
+			if data[locale][field.Key] != nil {
+				continue
+			}
+
+			if data[locale] == nil {
+				data[locale] = map[string]interface{}{}
+			}
+
+			data[locale][field.Key] = field.DefaultValue(author)
+		}
+	}
+}
+
+// sharedFieldValue returns the shared value of the `key` field from a multilingual data map
+// (or the first locale value if the data is not collapsed yet).
+func sharedFieldValue(data map[string]map[string]interface{}, key string, locales []string) (interface{}, bool) {
+	if val, ok := data[EntitySharedDataKey][key]; ok {
+		return val, true
+	}
+
+	for _, locale := range locales {
+		if val, ok := data[locale][key]; ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// EntityBulkActionCreate specifies the entity bulk create operation.
+	EntityBulkActionCreate = "create"
+
+	// EntityBulkActionUpdate specifies the entity bulk update operation.
+	EntityBulkActionUpdate = "update"
+
+	// EntityBulkActionDelete specifies the entity bulk delete operation.
+	EntityBulkActionDelete = "delete"
+
+	// EntityBulkActionStatus specifies the entity bulk status change operation.
+	EntityBulkActionStatus = "status"
+
+	// EntityBulkItemDone specifies a successfully executed bulk operation.
+	EntityBulkItemDone = "done"
+
+	// EntityBulkItemFailed specifies a failed bulk operation.
+	EntityBulkItemFailed = "failed"
+
+	// EntityBulkItemSkipped specifies a valid, but not executed bulk operation
+	// (eg. because of another invalid operation in an atomic batch).
+	EntityBulkItemSkipped = "skipped"
+)
+
+type (
+	// EntityBulkForm defines the entity bulk operations form model fields.
+	EntityBulkForm struct {
+		// Atomic specifies whether to validate the whole batch before writing anything
+		// (aka. "all-or-nothing" mode).
+		Atomic     bool                  `json:"atomic" form:"atomic"`
+		Operations []EntityBulkOperation `json:"operations" form:"operations"`
+	}
+
+	// EntityBulkOperation defines single entity bulk operation.
+	EntityBulkOperation struct {
+		Action      string                            `json:"action" form:"action"`
+		ID          string                            `json:"id" form:"id"`
+		Status      string                            `json:"status" form:"status"`
+		Data        map[string]map[string]interface{} `json:"data" form:"data"`
+		PublishAt   int64                             `json:"publish_at" form:"publish_at"`
+		UnpublishAt int64                             `json:"unpublish_at" form:"unpublish_at"`
+		Publish     bool                              `json:"publish" form:"publish"`
+	}
+
+	// EntityBulkResult defines the entity bulk operations result.
+	EntityBulkResult struct {
+		Atomic bool                   `json:"atomic"`
+		Items  []EntityBulkItemResult `json:"items"`
+	}
+
+	// EntityBulkItemResult defines single entity bulk operation result.
+	EntityBulkItemResult struct {
+		Action string        `json:"action"`
+		ID     bson.ObjectId `json:"id,omitempty"`
+		Status string        `json:"status"`
+		Error  interface{}   `json:"error"`
+		Model  *Entity       `json:"-"` // the written entity state
+	}
+)
+
+// Validate validates the EntityBulkForm struct fields.
+func (m EntityBulkForm) Validate() error {
+	return validation.ValidateStruct(&m,
+		// each operation is validated separately, so that it could be reported per item
+		// @see daos/entity_bulk/Bulk()
+		validation.Field(&m.Operations, validation.Required, validation.Length(1, app.Config.GetInt("bulk.maxOperations")), validation.Skip),
+	)
+}
+
+// Validate validates the EntityBulkOperation struct fields.
+// The operation data is validated separately based on its collection fields.
+func (m EntityBulkOperation) Validate() error {
+	idRules := []validation.Rule{}
+	if m.Action != EntityBulkActionCreate {
+		idRules = append(idRules, validation.Required, validation.By(checkObjectIdHex))
+	}
+
+	statusRules := []validation.Rule{validation.In(EntityStatusActive, EntityStatusInactive)}
+	if m.Action != EntityBulkActionDelete {
+		statusRules = append(statusRules, validation.Required)
+	}
+
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Action, validation.Required, validation.In(
+			EntityBulkActionCreate,
+			EntityBulkActionUpdate,
+			EntityBulkActionDelete,
+			EntityBulkActionStatus,
+		)),
+		validation.Field(&m.ID, idRules...),
+		validation.Field(&m.Status, statusRules...),
+	)
+}
+
+// checkObjectIdHex checks whether the validating value is a valid object id hex string.
+func checkObjectIdHex(value interface{}) error {
+	v, _ := value.(string)
+
+	if v != "" && !bson.IsObjectIdHex(v) {
+		return errors.New("Invalid object id.")
+	}
+
+	return nil
+}
+
+// AccessAction returns the collection access action required to execute the operation.
+func (m EntityBulkOperation) AccessAction() string {
+	if m.Action == EntityBulkActionStatus {
+		return "update"
+	}
+
+	return m.Action
+}
+
+// NewEntityBulkResult creates and returns new EntityBulkResult instance
+// with a skipped result item for each of the form operations.
+func NewEntityBulkResult(form *EntityBulkForm) *EntityBulkResult {
+	result := &EntityBulkResult{
+		Atomic: form.Atomic,
+		Items:  make([]EntityBulkItemResult, len(form.Operations)),
+	}
+
+	for i, op := range form.Operations {
+		result.Items[i] = EntityBulkItemResult{Action: op.Action, Status: EntityBulkItemSkipped}
+
+		if bson.IsObjectIdHex(op.ID) {
+			result.Items[i].ID = bson.ObjectIdHex(op.ID)
+		}
+	}
+
+	return result
+}
+
+// SetError marks the `index` result item as failed.
+func (m *EntityBulkResult) SetError(index int, err error) {
+	m.Items[index].Status = EntityBulkItemFailed
+
+	switch v := err.(type) {
+	case *utils.DataError:
+		m.Items[index].Error = v.Data
+	case validation.Errors:
+		m.Items[index].Error = v
+	default:
+		m.Items[index].Error = err.Error()
+	}
+}
+
+// SetDone marks the `index` result item as successfully executed.
+func (m *EntityBulkResult) SetDone(index int, model *Entity) {
+	m.Items[index].Status = EntityBulkItemDone
+	m.Items[index].ID = model.ID
+	m.Items[index].Model = model
+}
+
+// HasErrors checks whether any of the result items has failed.
+func (m *EntityBulkResult) HasErrors() bool {
+	for _, item := range m.Items {
+		if item.Status == EntityBulkItemFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasDone checks whether any of the result items was successfully executed.
+func (m *EntityBulkResult) HasDone() bool {
+	for _, item := range m.Items {
+		if item.Status == EntityBulkItemDone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Models returns the written entity models of the successful `action` operations.
+func (m *EntityBulkResult) Models(action string) []Entity {
+	result := []Entity{}
+
+	for _, item := range m.Items {
+		if item.Status == EntityBulkItemDone && item.Action == action && item.Model != nil {
+			result = append(result, *item.Model)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestEntityBulkForm_Validate(t *testing.T) {
+	app.InitConfig("")
+
+	defer app.Config.Set("bulk.maxOperations", app.Config.GetInt("bulk.maxOperations"))
+	app.Config.Set("bulk.maxOperations", 2)
+
+	// empty form
+	f1 := &EntityBulkForm{}
+
+	// too many operations
+	f2 := &EntityBulkForm{Operations: []EntityBulkOperation{{}, {}, {}}}
+
+	// invalid operations (validated separately)
+	f3 := &EntityBulkForm{Atomic: true, Operations: []EntityBulkOperation{{Action: "invalid"}}}
+
+	testScenarios := []TestValidateScenario{
+		{f1, []string{"operations"}},
+		{f2, []string{"operations"}},
+		{f3, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestEntityBulkOperation_Validate(t *testing.T) {
+	// empty operation
+	o1 := &EntityBulkOperation{}
+
+	// invalid create operation
+	o2 := &EntityBulkOperation{Action: EntityBulkActionCreate, Status: "invalid"}
+
+	// valid create operation
+	o3 := &EntityBulkOperation{Action: EntityBulkActionCreate, Status: EntityStatusActive}
+
+	// invalid update operation
+	o4 := &EntityBulkOperation{Action: EntityBulkActionUpdate, ID: "invalid"}
+
+	// valid update operation
+	o5 := &EntityBulkOperation{Action: EntityBulkActionUpdate, ID: "5a8bea3ae1382310bec8076b", Status: EntityStatusInactive}
+
+	// valid delete operation
+	o6 := &EntityBulkOperation{Action: EntityBulkActionDelete, ID: "5a8bea3ae1382310bec8076b"}
+
+	// invalid status operation
+	o7 := &EntityBulkOperation{Action: EntityBulkActionStatus, ID: "5a8bea3ae1382310bec8076b"}
+
+	// valid status operation
+	o8 := &EntityBulkOperation{Action: EntityBulkActionStatus, ID: "5a8bea3ae1382310bec8076b", Status: EntityStatusActive}
+
+	testScenarios := []TestValidateScenario{
+		{o1, []string{"action", "id", "status"}},
+		{o2, []string{"status"}},
+		{o3, []string{}},
+		{o4, []string{"id", "status"}},
+		{o5, []string{}},
+		{o6, []string{}},
+		{o7, []string{"status"}},
+		{o8, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestEntityBulkOperation_AccessAction(t *testing.T) {
+	testScenarios := map[string]string{
+		EntityBulkActionCreate: "create",
+		EntityBulkActionUpdate: "update",
+		EntityBulkActionDelete: "delete",
+		EntityBulkActionStatus: "update",
+	}
+
+	for action, expected := range testScenarios {
+		op := EntityBulkOperation{Action: action}
+
+		if result := op.AccessAction(); result != expected {
+			t.Errorf("Expected %s, got %s (action %s)", expected, result, action)
+		}
+	}
+}
+
+func TestNewEntityBulkResult(t *testing.T) {
+	form := &EntityBulkForm{
+		Atomic: true,
+		Operations: []EntityBulkOperation{
+			{Action: EntityBulkActionCreate},
+			{Action: EntityBulkActionDelete, ID: "5a8bea3ae1382310bec8076b"},
+			{Action: EntityBulkActionDelete, ID: "invalid"},
+		},
+	}
+
+	result := NewEntityBulkResult(form)
+
+	if !result.Atomic {
+		t.Error("Expected the result to be atomic")
+	}
+
+	if len(result.Items) != 3 {
+		t.Fatalf("Expected 3 result items, got %d", len(result.Items))
+	}
+
+	expectedIds := []bson.ObjectId{"", bson.ObjectIdHex("5a8bea3ae1382310bec8076b"), ""}
+
+	for i, item := range result.Items {
+		if item.Action != form.Operations[i].Action {
+			t.Errorf("Expected %s action, got %s (item %d)", form.Operations[i].Action, item.Action, i)
+		}
+
+		if item.Status != EntityBulkItemSkipped {
+			t.Errorf("Expected %s status, got %s (item %d)", EntityBulkItemSkipped, item.Status, i)
+		}
+
+		if item.ID != expectedIds[i] {
+			t.Errorf("Expected %v id, got %v (item %d)", expectedIds[i], item.ID, i)
+		}
+	}
+}
+
+func TestEntityBulkResult_SetErrorAndDone(t *testing.T) {
+	form := &EntityBulkForm{
+		Operations: []EntityBulkOperation{
+			{Action: EntityBulkActionCreate},
+			{Action: EntityBulkActionUpdate},
+			{Action: EntityBulkActionStatus},
+			{Action: EntityBulkActionUpdate},
+		},
+	}
+
+	result := NewEntityBulkResult(form)
+
+	if result.HasErrors() {
+		t.Error("Expected the result to not have errors")
+	}
+
+	if result.HasDone() {
+		t.Error("Expected the result to not have done items")
+	}
+
+	result.SetDone(0, &Entity{ID: bson.ObjectIdHex("5a8bea3ae1382310bec8076b")})
+	result.SetDone(1, &Entity{ID: bson.ObjectIdHex("5a8bea7ee1382310bec8076c")})
+	result.SetError(2, errors.New("test"))
+	result.SetError(3, utils.NewDataError(map[string]string{"title": "test"}))
+
+	if !result.HasErrors() {
+		t.Error("Expected the result to have errors")
+	}
+
+	if !result.HasDone() {
+		t.Error("Expected the result to have done items")
+	}
+
+	if result.Items[0].Status != EntityBulkItemDone || result.Items[0].ID.Hex() != "5a8bea3ae1382310bec8076b" {
+		t.Error("Expected the first item to be done, got", result.Items[0])
+	}
+
+	if result.Items[2].Status != EntityBulkItemFailed || result.Items[2].Error != "test" {
+		t.Error("Expected the third item to be failed with plain error message, got", result.Items[2])
+	}
+
+	if data, ok := result.Items[3].Error.(map[string]string); !ok || data["title"] != "test" {
+		t.Error("Expected the fourth item to be failed with data error, got", result.Items[3])
+	}
+
+	if models := result.Models(EntityBulkActionUpdate); len(models) != 1 || models[0].ID.Hex() != "5a8bea7ee1382310bec8076c" {
+		t.Error("Expected only the done update item model, got", models)
+	}
+
+	if models := result.Models(EntityBulkActionDelete); len(models) != 0 {
+		t.Error("Expected no delete item models, got", models)
+	}
+}
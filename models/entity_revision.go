@@ -0,0 +1,123 @@
+package models
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// EntityRevisionActionCreate specifies the entity create revision action.
+	EntityRevisionActionCreate = "create"
+
+	// EntityRevisionActionUpdate specifies the entity update revision action.
+	EntityRevisionActionUpdate = "update"
+
+	// EntityRevisionActionDelete specifies the entity delete revision action.
+	EntityRevisionActionDelete = "delete"
+
+	// EntityRevisionActionRestore specifies the entity restore revision action.
+	EntityRevisionActionRestore = "restore"
+
+	// EntityRevisionActionPublish specifies the entity publish revision action.
+	EntityRevisionActionPublish = "publish"
+
+	// EntityRevisionActionUnpublish specifies the entity unpublish revision action.
+	EntityRevisionActionUnpublish = "unpublish"
+)
+
+type (
+	// EntityRevision defines the immutable EntityRevision model fields.
+	EntityRevision struct {
+		ID           bson.ObjectId                     `json:"id" bson:"_id"`
+		EntityID     bson.ObjectId                     `json:"entity_id" bson:"entity_id"`
+		CollectionID bson.ObjectId                     `json:"collection_id" bson:"collection_id"`
+		Action       string                            `json:"action" bson:"action"`
+		Author       RevisionAuthor                    `json:"author" bson:"author"`
+		Status       string                            `json:"status" bson:"status"`
+		Data         map[string]map[string]interface{} `json:"data" bson:"data"`
+		Created      int64                             `json:"created" bson:"created"`
+
+		// the entity create time (used to recreate deleted entities)
+		EntityCreated int64 `json:"entity_created" bson:"entity_created"`
+	}
+
+	// RevisionAuthor defines the identity (user or api key) that has triggered a revision.
+	RevisionAuthor struct {
+		ID    string `json:"id" bson:"id"`
+		Model string `json:"model" bson:"model"`
+	}
+
+	// EntityRevisionDiff defines a single changed field value between two revisions.
+	EntityRevisionDiff struct {
+		Old interface{} `json:"old"`
+		New interface{} `json:"new"`
+	}
+)
+
+// NewEntityRevision creates and returns new EntityRevision model from the provided entity state.
+// The entity draft data is stored, if the entity has one, otherwise - its live data.
+func NewEntityRevision(entity *Entity, action string, author RevisionAuthor) *EntityRevision {
+	data := entity.Draft
+	if data == nil {
+		data = entity.Data
+	}
+
+	return &EntityRevision{
+		ID:           bson.NewObjectId(),
+		EntityID:     entity.ID,
+		CollectionID: entity.CollectionID,
+		Action:       action,
+		Author:       author,
+		Status:       entity.Status,
+		Data:         data,
+		Created:      time.Now().Unix(),
+
+		EntityCreated: entity.Created,
+	}
+}
+
+// Diff returns per locale map with all fields that differ between the current and the `compare` revision.
+// The `Old` diff value is taken from the current revision and the `New` one - from the `compare` revision.
+func (m EntityRevision) Diff(compare *EntityRevision) map[string]map[string]EntityRevisionDiff {
+	result := map[string]map[string]EntityRevisionDiff{}
+
+	locales := map[string]bool{}
+	for locale, _ := range m.Data {
+		locales[locale] = true
+	}
+	for locale, _ := range compare.Data {
+		locales[locale] = true
+	}
+
+	for locale, _ := range locales {
+		oldData := m.Data[locale]
+		newData := compare.Data[locale]
+
+		keys := map[string]bool{}
+		for key, _ := range oldData {
+			keys[key] = true
+		}
+		for key, _ := range newData {
+			keys[key] = true
+		}
+
+		localeDiff := map[string]EntityRevisionDiff{}
+
+		for key, _ := range keys {
+			oldVal := oldData[key]
+			newVal := newData[key]
+
+			if !reflect.DeepEqual(oldVal, newVal) {
+				localeDiff[key] = EntityRevisionDiff{Old: oldVal, New: newVal}
+			}
+		}
+
+		if len(localeDiff) > 0 {
+			result[locale] = localeDiff
+		}
+	}
+
+	return result
+}
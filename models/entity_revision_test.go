@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewEntityRevision(t *testing.T) {
+	entity := &Entity{
+		ID:           bson.ObjectIdHex("507f191e810c19729de860ea"),
+		CollectionID: bson.ObjectIdHex("5a911fec2e7e77d33858b043"),
+		Status:       EntityStatusActive,
+		Data:         map[string]map[string]interface{}{"en": map[string]interface{}{"title": "test"}},
+		Created:      1519118906,
+	}
+
+	author := RevisionAuthor{ID: "5a9120f97daf02b9ccaf0a86", Model: "user"}
+
+	revision := NewEntityRevision(entity, EntityRevisionActionUpdate, author)
+
+	if revision.ID.Hex() == "" {
+		t.Error("Expected revision id to be set")
+	}
+
+	if revision.EntityID != entity.ID {
+		t.Errorf("Expected %s entity id, got %s", entity.ID.Hex(), revision.EntityID.Hex())
+	}
+
+	if revision.CollectionID != entity.CollectionID {
+		t.Errorf("Expected %s collection id, got %s", entity.CollectionID.Hex(), revision.CollectionID.Hex())
+	}
+
+	if revision.Action != EntityRevisionActionUpdate {
+		t.Errorf("Expected %s action, got %s", EntityRevisionActionUpdate, revision.Action)
+	}
+
+	if revision.Author != author {
+		t.Errorf("Expected %v author, got %v", author, revision.Author)
+	}
+
+	if revision.Status != entity.Status {
+		t.Errorf("Expected %s status, got %s", entity.Status, revision.Status)
+	}
+
+	if revision.Data["en"]["title"] != "test" {
+		t.Error("Expected the entity data to be copied, got", revision.Data)
+	}
+
+	if revision.Created <= 0 {
+		t.Error("Expected created to be set")
+	}
+
+	if revision.EntityCreated != entity.Created {
+		t.Errorf("Expected %d entity created, got %d", entity.Created, revision.EntityCreated)
+	}
+
+	// entity with draft
+	entity.Draft = map[string]map[string]interface{}{"en": map[string]interface{}{"title": "draft"}}
+
+	draftRevision := NewEntityRevision(entity, EntityRevisionActionUpdate, author)
+
+	if draftRevision.Data["en"]["title"] != "draft" {
+		t.Error("Expected the entity draft data to be copied, got", draftRevision.Data)
+	}
+}
+
+func TestEntityRevision_Diff(t *testing.T) {
+	r1 := &EntityRevision{
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{
+				"title":   "old",
+				"options": []interface{}{"1", "2"},
+				"removed": "test",
+			},
+			"bg": map[string]interface{}{
+				"title": "same",
+			},
+		},
+	}
+
+	r2 := &EntityRevision{
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{
+				"title":   "new",
+				"options": []interface{}{"1", "2"},
+			},
+			"bg": map[string]interface{}{
+				"title": "same",
+			},
+			"de": map[string]interface{}{
+				"title": "added",
+			},
+		},
+	}
+
+	diff := r1.Diff(r2)
+
+	if len(diff) != 2 {
+		t.Fatalf("Expected 2 changed locales, got %d (%v)", len(diff), diff)
+	}
+
+	if len(diff["en"]) != 2 {
+		t.Fatalf("Expected 2 changed en fields, got %d (%v)", len(diff["en"]), diff["en"])
+	}
+
+	if diff["en"]["title"].Old != "old" || diff["en"]["title"].New != "new" {
+		t.Error("Invalid en title diff, got", diff["en"]["title"])
+	}
+
+	if diff["en"]["removed"].Old != "test" || diff["en"]["removed"].New != nil {
+		t.Error("Invalid en removed diff, got", diff["en"]["removed"])
+	}
+
+	if diff["de"]["title"].Old != nil || diff["de"]["title"].New != "added" {
+		t.Error("Invalid de title diff, got", diff["de"]["title"])
+	}
+
+	if same := r1.Diff(r1); len(same) != 0 {
+		t.Error("Expected empty diff, got", same)
+	}
+}
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// EntityRowFormatCsv specifies the CSV entity rows file format.
+	EntityRowFormatCsv = "csv"
+
+	// EntityRowFormatNdjson specifies the newline delimited JSON entity rows file format.
+	EntityRowFormatNdjson = "ndjson"
+
+	// EntityRowColumnID specifies the entity id row column.
+	EntityRowColumnID = "_id"
+
+	// EntityRowColumnStatus specifies the entity status row column.
+	EntityRowColumnStatus = "_status"
+
+	// EntityRowColumnCreated specifies the entity created row column (export only).
+	EntityRowColumnCreated = "_created"
+
+	// EntityRowColumnModified specifies the entity modified row column (export only).
+	EntityRowColumnModified = "_modified"
+)
+
+type (
+	// EntityRow defines a flat (spreadsheet like) entity representation
+	// with one column per multilingual `locale.field` and per non-multilingual `field` value.
+	EntityRow map[string]interface{}
+
+	// EntityRowColumn defines single entity row column.
+	EntityRowColumn struct {
+		Name   string
+		Locale string           // the data group of the field value (the shared one for the non-multilingual fields)
+		Field  *CollectionField // nil for the entity meta columns
+	}
+
+	// EntityImport defines the entity rows import result.
+	EntityImport struct {
+		Created []bson.ObjectId        `json:"created"`
+		Updated []bson.ObjectId        `json:"updated"`
+		Errors  map[string]interface{} `json:"errors"` // keyed by the 1-based row number
+
+		// the created and updated entity models (used for the create/update hooks)
+		CreatedEntities []Entity `json:"-"`
+		UpdatedEntities []Entity `json:"-"`
+	}
+)
+
+// NewEntityRowColumns returns the ordered entity row columns of the provided collection fields.
+func NewEntityRowColumns(collection *Collection, locales []string) []EntityRowColumn {
+	result := []EntityRowColumn{
+		{Name: EntityRowColumnID},
+		{Name: EntityRowColumnStatus},
+		{Name: EntityRowColumnCreated},
+		{Name: EntityRowColumnModified},
+	}
+
+	for i := range collection.Fields {
+		field := &collection.Fields[i]
+
+		if !field.Multilingual {
+			result = append(result, EntityRowColumn{Name: field.Key, Locale: EntitySharedDataKey, Field: field})
+
+			continue
+		}
+
+		for _, locale := range locales {
+			result = append(result, EntityRowColumn{Name: locale + "." + field.Key, Locale: locale, Field: field})
+		}
+	}
+
+	return result
+}
+
+// Value returns the column value of the provided entity live data.
+func (m EntityRowColumn) Value(entity *Entity) interface{} {
+	switch m.Name {
+	case EntityRowColumnID:
+		return entity.ID.Hex()
+	case EntityRowColumnStatus:
+		return entity.Status
+	case EntityRowColumnCreated:
+		return entity.Created
+	case EntityRowColumnModified:
+		return entity.Modified
+	}
+
+	if m.Field == nil {
+		return nil
+	}
+
+	if value, ok := entity.Data[m.Locale][m.Field.Key]; ok {
+		return value
+	}
+
+	// not collapsed shared value
+	if m.Locale == EntitySharedDataKey {
+		for locale, data := range entity.Data {
+			if value, ok := data[m.Field.Key]; ok && locale != EntitySharedDataKey {
+				return value
+			}
+		}
+	}
+
+	return nil
+}
+
+// Cell returns the plain text (eg. csv) representation of a column value.
+func (m EntityRowColumn) Cell(value interface{}) string {
+	if m.Field != nil {
+		return m.Field.FormatCell(value)
+	}
+
+	return formatCellScalar(value)
+}
+
+// NewEntityRow creates and returns new EntityRow from the provided entity live data.
+func NewEntityRow(entity *Entity, columns []EntityRowColumn) EntityRow {
+	result := EntityRow{}
+
+	for _, column := range columns {
+		result[column.Name] = column.Value(entity)
+	}
+
+	return result
+}
+
+// String returns the trimmed string value of a single row column.
+func (m EntityRow) String(column string) string {
+	v, _ := m[column].(string)
+
+	return strings.TrimSpace(v)
+}
+
+// Data resolves the entity data of the row field columns (the missing columns are ignored).
+// The plain text cell values are parsed based on their field type,
+// so that they could be later normalized with `CollectionField.CastValue()`.
+func (m EntityRow) Data(columns []EntityRowColumn) map[string]map[string]interface{} {
+	result := map[string]map[string]interface{}{}
+
+	for _, column := range columns {
+		if column.Field == nil {
+			continue
+		}
+
+		value, ok := m[column.Name]
+		if !ok {
+			continue
+		}
+
+		if cell, isString := value.(string); isString {
+			value = column.Field.ParseCell(cell)
+		}
+
+		if _, ok := result[column.Locale]; !ok {
+			result[column.Locale] = map[string]interface{}{}
+		}
+
+		result[column.Locale][column.Field.Key] = value
+	}
+
+	return result
+}
+
+// FormatCell returns the plain text (eg. csv) representation of a field value.
+// The media and relation ids are comma separated, while the other multi-value
+// field types (checklist, group and json) are json encoded.
+func (m CollectionField) FormatCell(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	switch m.Type {
+	case FieldTypeMedia, FieldTypeRelation:
+		ids := []string{}
+		for _, id := range utils.InterfaceToObjectIds(value) {
+			ids = append(ids, id.Hex())
+		}
+
+		return strings.Join(ids, ",")
+	case FieldTypeGeoPoint:
+		if point, ok := ParseGeoPoint(value); ok {
+			return formatCellScalar(point.Lat()) + "," + formatCellScalar(point.Lng())
+		}
+
+		return ""
+	case FieldTypeChecklist, FieldTypeGroup, FieldTypeJson:
+		encoded, _ := json.Marshal(value)
+
+		return string(encoded)
+	}
+
+	return formatCellScalar(value)
+}
+
+// ParseCell converts a plain text (eg. csv) cell into a field value
+// that could be normalized with `CastValue()` (see also `FormatCell()`).
+func (m CollectionField) ParseCell(cell string) interface{} {
+	switch m.Type {
+	case FieldTypeSwitch:
+		result, _ := strconv.ParseBool(strings.TrimSpace(cell))
+
+		return result
+	case FieldTypeNumber, FieldTypeDate:
+		if strings.TrimSpace(cell) == "" {
+			return nil
+		}
+
+		return strings.TrimSpace(cell)
+	case FieldTypeMedia, FieldTypeRelation, FieldTypeChecklist:
+		return parseCellList(cell)
+	case FieldTypeGroup:
+		var result interface{}
+		json.Unmarshal([]byte(cell), &result)
+
+		return result
+	}
+
+	return cell
+}
+
+// formatCellScalar returns the plain text representation of a single scalar value.
+func formatCellScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// parseCellList parses a json encoded array or a comma separated list cell.
+func parseCellList(cell string) []interface{} {
+	result := []interface{}{}
+
+	cell = strings.TrimSpace(cell)
+
+	if strings.HasPrefix(cell, "[") {
+		if err := json.Unmarshal([]byte(cell), &result); err == nil {
+			return result
+		}
+	}
+
+	for _, item := range strings.Split(cell, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
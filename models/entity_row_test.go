@@ -0,0 +1,196 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewEntityRowColumns(t *testing.T) {
+	collection := &Collection{
+		Fields: []CollectionField{
+			{Key: "title", Type: FieldTypePlain, Multilingual: true},
+			{Key: "price", Type: FieldTypeNumber},
+		},
+	}
+
+	columns := NewEntityRowColumns(collection, []string{"en", "bg"})
+
+	expectedNames := []string{"_id", "_status", "_created", "_modified", "en.title", "bg.title", "price"}
+
+	if len(columns) != len(expectedNames) {
+		t.Fatalf("Expected %d columns, got %d (%v)", len(expectedNames), len(columns), columns)
+	}
+
+	for i, column := range columns {
+		if column.Name != expectedNames[i] {
+			t.Errorf("Expected %s column, got %s", expectedNames[i], column.Name)
+		}
+	}
+
+	if columns[4].Locale != "en" || columns[4].Field.Key != "title" {
+		t.Error("Expected en title column, got", columns[4])
+	}
+
+	if columns[6].Locale != EntitySharedDataKey || columns[6].Field.Key != "price" {
+		t.Error("Expected shared price column, got", columns[6])
+	}
+}
+
+func TestEntityRowColumn_Value(t *testing.T) {
+	titleField := &CollectionField{Key: "title", Type: FieldTypePlain, Multilingual: true}
+	priceField := &CollectionField{Key: "price", Type: FieldTypeNumber}
+
+	entity := &Entity{
+		ID:       bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+		Status:   EntityStatusActive,
+		Created:  1,
+		Modified: 2,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "test", "price": 10},
+		},
+	}
+
+	testScenarios := []struct {
+		Column   EntityRowColumn
+		Expected interface{}
+	}{
+		{EntityRowColumn{Name: EntityRowColumnID}, "5a8bea3ae1382310bec8076b"},
+		{EntityRowColumn{Name: EntityRowColumnStatus}, EntityStatusActive},
+		{EntityRowColumn{Name: EntityRowColumnCreated}, int64(1)},
+		{EntityRowColumn{Name: EntityRowColumnModified}, int64(2)},
+		{EntityRowColumn{Name: "missing"}, nil},
+		{EntityRowColumn{Name: "en.title", Locale: "en", Field: titleField}, "test"},
+		{EntityRowColumn{Name: "bg.title", Locale: "bg", Field: titleField}, nil},
+		// not collapsed shared value
+		{EntityRowColumn{Name: "price", Locale: EntitySharedDataKey, Field: priceField}, 10},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := scenario.Column.Value(entity); !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v (%T), got %v (%T) (column %s)", scenario.Expected, scenario.Expected, result, result, scenario.Column.Name)
+		}
+	}
+}
+
+func TestNewEntityRow(t *testing.T) {
+	collection := &Collection{
+		Fields: []CollectionField{
+			{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		},
+	}
+
+	entity := &Entity{
+		ID:     bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+		Status: EntityStatusInactive,
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "test"},
+		},
+	}
+
+	row := NewEntityRow(entity, NewEntityRowColumns(collection, []string{"en", "bg"}))
+
+	if len(row) != 6 {
+		t.Errorf("Expected 6 row columns, got %d (%v)", len(row), row)
+	}
+
+	if row.String(EntityRowColumnID) != "5a8bea3ae1382310bec8076b" || row.String(EntityRowColumnStatus) != EntityStatusInactive {
+		t.Error("Expected the entity meta columns to be set, got", row)
+	}
+
+	if row["en.title"] != "test" || row["bg.title"] != nil {
+		t.Error("Expected only the en title column value, got", row)
+	}
+}
+
+func TestEntityRow_Data(t *testing.T) {
+	collection := &Collection{
+		Fields: []CollectionField{
+			{Key: "title", Type: FieldTypePlain, Multilingual: true},
+			{Key: "published", Type: FieldTypeSwitch},
+			{Key: "rels", Type: FieldTypeRelation},
+			{Key: "tags", Type: FieldTypeChecklist},
+		},
+	}
+
+	row := EntityRow{
+		"_id":       "5a8bea3ae1382310bec8076b",
+		"en.title":  "test",
+		"published": "true",
+		"rels":      "5a8bea3ae1382310bec8076b, 5a8bea7ee1382310bec8076c",
+		"tags":      []interface{}{"a", "b"}, // already parsed (eg. ndjson)
+		"unknown":   "test",
+	}
+
+	result := row.Data(NewEntityRowColumns(collection, []string{"en", "bg"}))
+
+	expected := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "test"},
+		EntitySharedDataKey: map[string]interface{}{
+			"published": true,
+			"rels":      []interface{}{"5a8bea3ae1382310bec8076b", "5a8bea7ee1382310bec8076c"},
+			"tags":      []interface{}{"a", "b"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCollectionField_FormatCell(t *testing.T) {
+	testScenarios := []struct {
+		Type     string
+		Value    interface{}
+		Expected string
+	}{
+		{FieldTypePlain, nil, ""},
+		{FieldTypePlain, "test", "test"},
+		{FieldTypeNumber, 1.5, "1.5"},
+		{FieldTypeNumber, 10, "10"},
+		{FieldTypeSwitch, true, "true"},
+		{FieldTypeRelation, []interface{}{bson.ObjectIdHex("5a8bea3ae1382310bec8076b"), bson.ObjectIdHex("5a8bea7ee1382310bec8076c")}, "5a8bea3ae1382310bec8076b,5a8bea7ee1382310bec8076c"},
+		{FieldTypeMedia, []interface{}{}, ""},
+		{FieldTypeGeoPoint, map[string]interface{}{"type": GeoPointType, "coordinates": []interface{}{24.5, 42.1}}, "42.1,24.5"},
+		{FieldTypeChecklist, []interface{}{"a", "b"}, `["a","b"]`},
+		{FieldTypeJson, map[string]interface{}{"a": 1}, `{"a":1}`},
+	}
+
+	for _, scenario := range testScenarios {
+		field := CollectionField{Type: scenario.Type}
+
+		if result := field.FormatCell(scenario.Value); result != scenario.Expected {
+			t.Errorf("Expected %q, got %q (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestCollectionField_ParseCell(t *testing.T) {
+	testScenarios := []struct {
+		Type     string
+		Cell     string
+		Expected interface{}
+	}{
+		{FieldTypePlain, " test ", " test "},
+		{FieldTypeSwitch, "1", true},
+		{FieldTypeSwitch, "invalid", false},
+		{FieldTypeNumber, " ", nil},
+		{FieldTypeNumber, " 1.5 ", "1.5"},
+		{FieldTypeDate, "1518000000", "1518000000"},
+		{FieldTypeRelation, "", []interface{}{}},
+		{FieldTypeRelation, "a, ,b", []interface{}{"a", "b"}},
+		{FieldTypeChecklist, `["a","b"]`, []interface{}{"a", "b"}},
+		{FieldTypeGroup, `{"a":"b"}`, map[string]interface{}{"a": "b"}},
+		{FieldTypeGroup, "invalid", nil},
+		{FieldTypeGeoPoint, "42.1,24.5", "42.1,24.5"},
+	}
+
+	for _, scenario := range testScenarios {
+		field := CollectionField{Type: scenario.Type}
+
+		if result := field.ParseCell(scenario.Cell); !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/globalsign/mgo/bson"
@@ -24,10 +25,28 @@ func TestEntityForm_Validate(t *testing.T) {
 		Data:         map[string]map[string]interface{}{"en": map[string]interface{}{"test": 1}},
 	}
 
+	// invalid publish schedule
+	f4 := &EntityForm{
+		CollectionID: bson.ObjectIdHex("507f191e810c19729de860ea"),
+		Status:       EntityStatusInactive,
+		PublishAt:    1519200000,
+		UnpublishAt:  1519100000,
+	}
+
+	// valid publish schedule
+	f5 := &EntityForm{
+		CollectionID: bson.ObjectIdHex("507f191e810c19729de860ea"),
+		Status:       EntityStatusInactive,
+		PublishAt:    1519100000,
+		UnpublishAt:  1519200000,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{f1, []string{"collection_id", "status"}},
 		{f2, []string{"status"}},
 		{f3, []string{}},
+		{f4, []string{"unpublish_at"}},
+		{f5, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -78,7 +97,7 @@ func TestEntityForm_ResolveModel(t *testing.T) {
 			t.Errorf("Expected resolved model collection id to be %s, got %s", scenario.CollectionID.Hex(), resolvedModel.CollectionID.Hex())
 		}
 
-		for locale, data := range resolvedModel.Data {
+		for locale, data := range resolvedModel.Draft {
 			if _, ok := scenario.Data[locale]; !ok {
 				t.Fatalf("Locale %s is not expected", locale)
 			}
@@ -91,6 +110,10 @@ func TestEntityForm_ResolveModel(t *testing.T) {
 			}
 		}
 
+		if resolvedModel.Data != nil && scenario.Model == nil {
+			t.Error("Expected the live data to be set only on publish")
+		}
+
 		if scenario.Model == nil { // new
 			if resolvedModel.ID.Hex() == "" {
 				t.Error("Expected resolved model id to be set")
@@ -118,3 +141,381 @@ func TestEntityForm_ResolveModel(t *testing.T) {
 		}
 	}
 }
+
+func TestEntity_Publish(t *testing.T) {
+	model := &Entity{
+		Data:      map[string]map[string]interface{}{"en": map[string]interface{}{"title": "live"}},
+		Draft:     map[string]map[string]interface{}{"en": map[string]interface{}{"title": "draft"}},
+		PublishAt: 1519200000,
+	}
+
+	model.Publish()
+
+	if model.Data["en"]["title"] != "draft" {
+		t.Error("Expected the draft data to be promoted, got", model.Data)
+	}
+
+	if model.PublishAt != 0 {
+		t.Error("Expected publish_at to be reset, got", model.PublishAt)
+	}
+
+	if model.Published <= 0 {
+		t.Error("Expected published to be set")
+	}
+
+	// ensure that the draft and the live data are not sharing the same maps
+	model.Draft["en"]["title"] = "changed"
+	if model.Data["en"]["title"] != "draft" {
+		t.Error("Expected the live data to be a copy of the draft, got", model.Data)
+	}
+
+	// empty draft
+	emptyDraftModel := &Entity{
+		Data: map[string]map[string]interface{}{"en": map[string]interface{}{"title": "live"}},
+	}
+
+	emptyDraftModel.Publish()
+
+	if emptyDraftModel.Data["en"]["title"] != "live" {
+		t.Error("Expected the live data to be unchanged, got", emptyDraftModel.Data)
+	}
+}
+
+func TestEntity_RefreshSearchText(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Searchable: true},
+		{Key: "content", Type: FieldTypeEditor, Searchable: true},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	model := &Entity{
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Title en", "content": "<p>Lorem &amp; <b>ipsum</b></p>", "code": "abc"},
+			"bg": map[string]interface{}{"title": "Title bg", "content": "", "code": "abc"},
+		},
+		Draft: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Draft title en"},
+		},
+	}
+
+	model.RefreshSearchText(collection)
+
+	expected := []string{"Title bg", "Title en", "Lorem & ipsum"}
+
+	if !reflect.DeepEqual(model.SearchText, expected) {
+		t.Errorf("Expected %v, got %v", expected, model.SearchText)
+	}
+}
+
+func TestEntity_ResolveLocale(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain},
+		{Key: "content", Type: FieldTypeEditor},
+		{Key: "active", Type: FieldTypeSwitch},
+		{Key: "missing", Type: FieldTypePlain},
+	}}
+
+	model := Entity{
+		ID:      bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+		Status:  EntityStatusActive,
+		Created: 1518773370,
+		Data: map[string]map[string]interface{}{
+			"de": map[string]interface{}{"title": "", "content": "", "active": false},
+			"bg": map[string]interface{}{"title": "", "content": "Content bg", "active": true},
+			"en": map[string]interface{}{"title": "Title en", "content": "Content en", "active": false},
+		},
+	}
+
+	// without draft
+	r1 := model.ResolveLocale([]string{"de", "bg", "en"}, collection)
+
+	if r1.ID != model.ID || r1.Status != model.Status || r1.Created != model.Created || r1.Locale != "de" {
+		t.Errorf("Expected the entity properties to be copied, got %v", r1)
+	}
+
+	// false is not an empty switch value
+	expectedData := map[string]interface{}{"title": "Title en", "content": "Content bg", "active": false}
+	if !reflect.DeepEqual(r1.Data, expectedData) {
+		t.Errorf("Expected %v data, got %v", expectedData, r1.Data)
+	}
+
+	expectedLocales := map[string]string{"title": "en", "content": "bg", "active": "de"}
+	if !reflect.DeepEqual(r1.DataLocales, expectedLocales) {
+		t.Errorf("Expected %v data locales, got %v", expectedLocales, r1.DataLocales)
+	}
+
+	if r1.Draft != nil || r1.DraftLocales != nil {
+		t.Errorf("Expected nil draft, got %v (%v)", r1.Draft, r1.DraftLocales)
+	}
+
+	// with draft and all empty chain values
+	model.Draft = map[string]map[string]interface{}{
+		"de": map[string]interface{}{"title": "Draft title de", "content": ""},
+		"en": map[string]interface{}{"title": "Draft title en", "content": ""},
+	}
+
+	r2 := model.ResolveLocale([]string{"de", "en"}, collection)
+
+	expectedDraft := map[string]interface{}{"title": "Draft title de", "content": ""}
+	if !reflect.DeepEqual(r2.Draft, expectedDraft) {
+		t.Errorf("Expected %v draft, got %v", expectedDraft, r2.Draft)
+	}
+
+	expectedDraftLocales := map[string]string{"title": "de", "content": "de"}
+	if !reflect.DeepEqual(r2.DraftLocales, expectedDraftLocales) {
+		t.Errorf("Expected %v draft locales, got %v", expectedDraftLocales, r2.DraftLocales)
+	}
+
+	// shared field value
+	model.Draft = nil
+	model.Data = map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"active": true},
+		"en":                map[string]interface{}{"title": "Title en", "content": "Content en"},
+	}
+
+	r3 := model.ResolveLocale([]string{"de", "en"}, collection)
+
+	expectedData3 := map[string]interface{}{"title": "Title en", "content": "Content en", "active": true}
+	if !reflect.DeepEqual(r3.Data, expectedData3) {
+		t.Errorf("Expected %v data, got %v", expectedData3, r3.Data)
+	}
+}
+
+func TestEntity_RefreshTranslationFlags(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		{Key: "content", Type: FieldTypeEditor, Multilingual: true},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	languages := []Language{
+		{Locale: "en", Fallback: []string{}},
+		{Locale: "bg", Fallback: []string{"en"}},
+		{Locale: "de", Fallback: []string{"en"}},
+	}
+
+	oldData := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Title en", "content": "Content en", "code": "abc"},
+		"bg": map[string]interface{}{"title": "Title bg", "content": "Content bg", "code": "abc"},
+		"de": map[string]interface{}{"title": "Title de", "content": "Content de", "code": "abc"},
+	}
+
+	model := &Entity{
+		Data: oldData,
+		Draft: map[string]map[string]interface{}{
+			// source changes
+			"en": map[string]interface{}{"title": "New title en", "content": "New content en", "code": "def"},
+			// translated title
+			"bg": map[string]interface{}{"title": "New title bg", "content": "Content bg", "code": "abc"},
+			// changed non translatable field
+			"de": map[string]interface{}{"title": "Title de", "content": "Content de", "code": "def"},
+		},
+		NeedsTranslation: TranslationFlags{"en": []string{"title"}},
+	}
+
+	model.RefreshTranslationFlags(oldData, collection, languages)
+
+	expected := TranslationFlags{
+		"bg": []string{"content"},
+		"de": []string{"title", "content"},
+	}
+
+	if !reflect.DeepEqual(model.NeedsTranslation, expected) {
+		t.Fatalf("Expected %v, got %v", expected, model.NeedsTranslation)
+	}
+
+	// translate the flagged fields
+	oldDraft := model.Draft
+	model.Draft = map[string]map[string]interface{}{
+		"en": oldDraft["en"],
+		"bg": map[string]interface{}{"title": "New title bg", "content": "New content bg", "code": "abc"},
+		"de": map[string]interface{}{"title": "New title de", "content": "Content de", "code": "def"},
+	}
+
+	model.RefreshTranslationFlags(oldDraft, collection, languages)
+
+	expected = TranslationFlags{"de": []string{"content"}}
+
+	if !reflect.DeepEqual(model.NeedsTranslation, expected) {
+		t.Errorf("Expected %v, got %v", expected, model.NeedsTranslation)
+	}
+
+	// languages without fallback (compared with the default language)
+	noFallbackLanguages := []Language{
+		{Locale: "en"},
+		{Locale: "bg"},
+		{Locale: "de"},
+	}
+
+	noFallbackModel := &Entity{
+		Data: oldData,
+		Draft: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "New title en", "content": "Content en", "code": "abc"},
+			"bg": map[string]interface{}{"title": "New title bg", "content": "Content bg", "code": "abc"},
+			"de": map[string]interface{}{"title": "Title de", "content": "Content de", "code": "abc"},
+		},
+	}
+
+	noFallbackModel.RefreshTranslationFlags(oldData, collection, noFallbackLanguages)
+
+	expected = TranslationFlags{"de": []string{"title"}}
+
+	if !reflect.DeepEqual(noFallbackModel.NeedsTranslation, expected) {
+		t.Errorf("Expected %v, got %v", expected, noFallbackModel.NeedsTranslation)
+	}
+}
+
+func TestEntity_ExpandSharedData(t *testing.T) {
+	model := Entity{
+		Data: map[string]map[string]interface{}{
+			EntitySharedDataKey: map[string]interface{}{"code": "abc"},
+			"en":                map[string]interface{}{"title": "Title en"},
+			"bg":                nil,
+		},
+		Draft: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Draft title en"},
+		},
+	}
+
+	model.ExpandSharedData()
+
+	expectedData := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Title en", "code": "abc"},
+		"bg": map[string]interface{}{"code": "abc"},
+	}
+	if !reflect.DeepEqual(model.Data, expectedData) {
+		t.Errorf("Expected %v data, got %v", expectedData, model.Data)
+	}
+
+	// draft without shared group
+	expectedDraft := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Draft title en"},
+	}
+	if !reflect.DeepEqual(model.Draft, expectedDraft) {
+		t.Errorf("Expected %v draft, got %v", expectedDraft, model.Draft)
+	}
+}
+
+func TestCollapseSharedData(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		{Key: "code", Type: FieldTypePlain},
+		{Key: "price", Type: FieldTypeNumber},
+	}}
+
+	locales := []string{"en", "bg", "de"}
+
+	// not collapsed data (the first locale value is used)
+	data1 := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Title en", "code": "abc"},
+		"bg": map[string]interface{}{"title": "Title bg", "code": "def", "price": 1},
+	}
+
+	CollapseSharedData(data1, nil, collection, locales)
+
+	expected1 := map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"code": "abc", "price": 1},
+		"en":                map[string]interface{}{"title": "Title en"},
+		"bg":                map[string]interface{}{"title": "Title bg"},
+	}
+	if !reflect.DeepEqual(data1, expected1) {
+		t.Errorf("Expected %v, got %v", expected1, data1)
+	}
+
+	// changed value in a single locale
+	oldData := map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"code": "abc", "price": 1},
+	}
+
+	data2 := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"code": "abc", "price": 1},
+		"bg": map[string]interface{}{"code": "abc", "price": 1},
+		"de": map[string]interface{}{"code": "xyz", "price": "1"},
+	}
+
+	CollapseSharedData(data2, oldData, collection, locales)
+
+	expected2 := map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"code": "xyz", "price": 1},
+		"en":                map[string]interface{}{},
+		"bg":                map[string]interface{}{},
+		"de":                map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(data2, expected2) {
+		t.Errorf("Expected %v, got %v", expected2, data2)
+	}
+
+	// multilingual field value stored in the shared group
+	data3 := map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"title": "Title"},
+		"en":                map[string]interface{}{"title": "Title en"},
+	}
+
+	CollapseSharedData(data3, nil, collection, locales)
+
+	expected3 := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Title en"},
+		"bg": map[string]interface{}{"title": "Title"},
+		"de": map[string]interface{}{"title": "Title"},
+	}
+	if !reflect.DeepEqual(data3, expected3) {
+		t.Errorf("Expected %v, got %v", expected3, data3)
+	}
+}
+
+func TestHasDifferentLocaleValues(t *testing.T) {
+	field := CollectionField{Key: "price", Type: FieldTypeNumber}
+
+	locales := []string{"en", "bg", "de"}
+
+	testScenarios := []struct {
+		Data     map[string]map[string]interface{}
+		Expected bool
+	}{
+		{nil, false},
+		{map[string]map[string]interface{}{"en": map[string]interface{}{"title": "Title"}}, false},
+		{map[string]map[string]interface{}{"en": map[string]interface{}{"price": 1}}, false},
+		{map[string]map[string]interface{}{"en": map[string]interface{}{"price": 1}, "bg": map[string]interface{}{"price": "1"}, "de": map[string]interface{}{}}, false},
+		{map[string]map[string]interface{}{EntitySharedDataKey: map[string]interface{}{"price": 1}, "de": map[string]interface{}{"price": 1.0}}, false},
+		{map[string]map[string]interface{}{"en": map[string]interface{}{"price": 1}, "de": map[string]interface{}{"price": 2}}, true},
+		{map[string]map[string]interface{}{EntitySharedDataKey: map[string]interface{}{"price": 1}, "bg": map[string]interface{}{"price": 2}}, true},
+		{map[string]map[string]interface{}{"en": map[string]interface{}{"price": 1}, "fr": map[string]interface{}{"price": 2}}, false},
+	}
+
+	for i, scenario := range testScenarios {
+		result := HasDifferentLocaleValues(scenario.Data, field, locales)
+
+		if result != scenario.Expected {
+			t.Errorf("(%d) Expected %v, got %v", i, scenario.Expected, result)
+		}
+	}
+}
+
+func TestApplyFieldDefaults(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true, Default: "Untitled"},
+		{Key: "author", Type: FieldTypePlain, Default: FieldDefaultCurrentUser},
+		{Key: "published", Type: FieldTypeSwitch, Default: true},
+		{Key: "price", Type: FieldTypeNumber, Default: 10},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	locales := []string{"en", "bg"}
+	author := RevisionAuthor{ID: "5a7b15cd3fb9dc041c55b45d", Model: "user"}
+
+	data := map[string]map[string]interface{}{
+		"en": map[string]interface{}{"title": "Title en", "published": false},
+		"bg": map[string]interface{}{"price": nil},
+	}
+
+	ApplyFieldDefaults(data, collection, locales, author)
+
+	expected := map[string]map[string]interface{}{
+		EntitySharedDataKey: map[string]interface{}{"author": author.ID, "price": 10},
+		"en":                map[string]interface{}{"title": "Title en", "published": false},
+		"bg":                map[string]interface{}{"title": "Untitled", "price": nil},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// GeoPointType specifies the GeoJSON type of the geopoint field values.
+const GeoPointType = "Point"
+
+// GeoPoint defines a GeoJSON point value (used by the geopoint collection fields).
+// NB! The coordinates are stored in [longitude, latitude] order as required by the 2dsphere indexes.
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// NewGeoPoint creates and returns new GeoPoint instance.
+func NewGeoPoint(lat float64, lng float64) *GeoPoint {
+	return &GeoPoint{
+		Type:        GeoPointType,
+		Coordinates: []float64{lng, lat},
+	}
+}
+
+// Lat returns the geo point latitude.
+func (m GeoPoint) Lat() float64 {
+	if len(m.Coordinates) != 2 {
+		return 0
+	}
+
+	return m.Coordinates[1]
+}
+
+// Lng returns the geo point longitude.
+func (m GeoPoint) Lng() float64 {
+	if len(m.Coordinates) != 2 {
+		return 0
+	}
+
+	return m.Coordinates[0]
+}
+
+// IsValid checks whether the geo point has valid latitude and longitude coordinates.
+func (m GeoPoint) IsValid() bool {
+	if m.Type != GeoPointType || len(m.Coordinates) != 2 {
+		return false
+	}
+
+	return m.Lat() >= -90 && m.Lat() <= 90 && m.Lng() >= -180 && m.Lng() <= 180
+}
+
+// ParseGeoPoint tries to resolve GeoPoint from the provided value.
+// Supported formats:
+// - `"lat,lng"` string
+// - `{"lat": 1, "lng": 2}` map
+// - `{"type": "Point", "coordinates": [lng, lat]}` GeoJSON map
+func ParseGeoPoint(value interface{}) (*GeoPoint, bool) {
+	switch v := value.(type) {
+	case GeoPoint:
+		return &v, true
+	case *GeoPoint:
+		return v, v != nil
+	case string:
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return nil, false
+		}
+
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if latErr != nil || lngErr != nil {
+			return nil, false
+		}
+
+		return NewGeoPoint(lat, lng), true
+	case bson.M:
+		return ParseGeoPoint(map[string]interface{}(v))
+	case map[string]interface{}:
+		if coordinates, ok := v["coordinates"].([]interface{}); ok && len(coordinates) == 2 {
+			lng, lngOk := toFloat(coordinates[0])
+			lat, latOk := toFloat(coordinates[1])
+
+			return NewGeoPoint(lat, lng), lngOk && latOk
+		}
+
+		lat, latOk := toFloat(v["lat"])
+		lng, lngOk := toFloat(v["lng"])
+
+		return NewGeoPoint(lat, lng), lngOk && latOk
+	}
+
+	return nil, false
+}
+
+// toFloat converts numeric and numeric string values to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		result, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+		return result, err == nil
+	}
+
+	return 0, false
+}
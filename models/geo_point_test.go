@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewGeoPoint(t *testing.T) {
+	point := NewGeoPoint(42.5, 23.3)
+
+	if point.Type != GeoPointType {
+		t.Errorf("Expected %s type, got %s", GeoPointType, point.Type)
+	}
+
+	if len(point.Coordinates) != 2 || point.Coordinates[0] != 23.3 || point.Coordinates[1] != 42.5 {
+		t.Error("Expected [lng, lat] coordinates, got", point.Coordinates)
+	}
+
+	if point.Lat() != 42.5 || point.Lng() != 23.3 {
+		t.Errorf("Expected 42.5 lat and 23.3 lng, got %v and %v", point.Lat(), point.Lng())
+	}
+}
+
+func TestGeoPoint_IsValid(t *testing.T) {
+	testScenarios := []struct {
+		Point    GeoPoint
+		Expected bool
+	}{
+		{GeoPoint{}, false},
+		{GeoPoint{Type: "Polygon", Coordinates: []float64{1, 1}}, false},
+		{GeoPoint{Type: GeoPointType, Coordinates: []float64{1}}, false},
+		{*NewGeoPoint(-91, 0), false},
+		{*NewGeoPoint(0, 181), false},
+		{*NewGeoPoint(90, -180), true},
+		{*NewGeoPoint(42.5, 23.3), true},
+	}
+
+	for i, scenario := range testScenarios {
+		if result := scenario.Point.IsValid(); result != scenario.Expected {
+			t.Errorf("(%d) Expected %t, got %t", i, scenario.Expected, result)
+		}
+	}
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	testScenarios := []struct {
+		Value      interface{}
+		ExpectedOk bool
+	}{
+		{nil, false},
+		{123, false},
+		{"invalid", false},
+		{"1,2,3", false},
+		{"a,b", false},
+		{map[string]interface{}{"lat": "a", "lng": 23.3}, false},
+		{bson.M{"coordinates": []interface{}{"a", 42.5}}, false},
+		{"42.5, 23.3", true},
+		{map[string]interface{}{"lat": 42.5, "lng": 23.3}, true},
+		{bson.M{"type": "Point", "coordinates": []interface{}{23.3, 42.5}}, true},
+		{*NewGeoPoint(42.5, 23.3), true},
+		{NewGeoPoint(42.5, 23.3), true},
+	}
+
+	for i, scenario := range testScenarios {
+		point, ok := ParseGeoPoint(scenario.Value)
+
+		if ok != scenario.ExpectedOk {
+			t.Errorf("(%d) Expected %t, got %t", i, scenario.ExpectedOk, ok)
+			continue
+		}
+
+		if ok && (point.Lat() != 42.5 || point.Lng() != 23.3) {
+			t.Errorf("(%d) Expected 42.5 lat and 23.3 lng, got %v", i, point.Coordinates)
+		}
+	}
+}
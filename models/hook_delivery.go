@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofreta/gofreta-api/utils"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// HookDeliveryStatusPending specifies the pending (waiting for a retry) hook delivery status state.
+	HookDeliveryStatusPending = "pending"
+
+	// HookDeliveryStatusSuccess specifies the successfully sent hook delivery status state.
+	HookDeliveryStatusSuccess = "success"
+
+	// HookDeliveryStatusFailed specifies the failed (no more retries) hook delivery status state.
+	HookDeliveryStatusFailed = "failed"
+)
+
+type (
+	// HookDelivery defines the HookDelivery model fields.
+	HookDelivery struct {
+		ID           bson.ObjectId         `json:"id" bson:"_id"`
+		CollectionID bson.ObjectId         `json:"collection_id" bson:"collection_id"`
+		Url          string                `json:"url" bson:"url"`
+		Type         string                `json:"type" bson:"type"`
+		Action       string                `json:"action" bson:"action"`
+		Payload      string                `json:"payload" bson:"payload"`
+		Status       string                `json:"status" bson:"status"`
+		Attempts     []HookDeliveryAttempt `json:"attempts" bson:"attempts"`
+		NextAttempt  int64                 `json:"next_attempt" bson:"next_attempt"`
+		Created      int64                 `json:"created" bson:"created"`
+		Modified     int64                 `json:"modified" bson:"modified"`
+	}
+
+	// HookDeliveryAttempt defines single hook delivery attempt result.
+	HookDeliveryAttempt struct {
+		Code    int    `json:"code" bson:"code"`
+		Error   string `json:"error" bson:"error"`
+		Created int64  `json:"created" bson:"created"`
+	}
+)
+
+// NewHookDelivery creates and returns new pending HookDelivery model.
+// The hook payload is signed on each delivery attempt (see `daos.HookDeliveryDAO.Deliver()`).
+func NewHookDelivery(collection *Collection, url string, hookType string, hookAction string, hookData interface{}) (*HookDelivery, error) {
+	payload, err := json.Marshal(utils.NewHook(hookType, hookAction, hookData))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+
+	model := &HookDelivery{
+		ID:           bson.NewObjectId(),
+		CollectionID: collection.ID,
+		Url:          url,
+		Type:         hookType,
+		Action:       hookAction,
+		Payload:      string(payload),
+		Status:       HookDeliveryStatusPending,
+		Attempts:     []HookDeliveryAttempt{},
+		NextAttempt:  now,
+		Created:      now,
+		Modified:     now,
+	}
+
+	return model, nil
+}
+
+// AddAttempt registers new delivery attempt and updates the delivery status.
+// Failed attempts are rescheduled with exponential backoff (`retryDelay`, `2*retryDelay`, `4*retryDelay`, ...)
+// until `maxAttempts` is reached.
+func (m *HookDelivery) AddAttempt(code int, err error, maxAttempts int, retryDelay time.Duration) {
+	now := time.Now()
+
+	attempt := HookDeliveryAttempt{Code: code, Created: now.Unix()}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	m.Attempts = append(m.Attempts, attempt)
+	m.Modified = now.Unix()
+	m.NextAttempt = 0
+
+	if err == nil {
+		m.Status = HookDeliveryStatusSuccess
+	} else if len(m.Attempts) >= maxAttempts {
+		m.Status = HookDeliveryStatusFailed
+	} else {
+		m.Status = HookDeliveryStatusPending
+		m.NextAttempt = now.Add(retryDelay * time.Duration(1<<uint(len(m.Attempts)-1))).Unix()
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewHookDelivery(t *testing.T) {
+	collection := &Collection{
+		ID:         bson.ObjectIdHex("5a911fec2e7e77d33858b043"),
+		HookSecret: "test_secret",
+	}
+
+	delivery, err := NewHookDelivery(collection, "http://test.dev", "entity", "create", map[string]string{"test": "123"})
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if delivery.ID.Hex() == "" {
+		t.Error("Expected delivery id to be set")
+	}
+
+	if delivery.CollectionID != collection.ID {
+		t.Errorf("Expected %s collection id, got %s", collection.ID.Hex(), delivery.CollectionID.Hex())
+	}
+
+	if delivery.Url != "http://test.dev" || delivery.Type != "entity" || delivery.Action != "create" {
+		t.Error("Expected url, type and action to be set, got", delivery)
+	}
+
+	if delivery.Payload != `{"type":"entity","action":"create","data":{"test":"123"}}` {
+		t.Error("Unexpected payload", delivery.Payload)
+	}
+
+	if delivery.Status != HookDeliveryStatusPending || delivery.NextAttempt != delivery.Created {
+		t.Error("Expected the delivery to be pending, got", delivery)
+	}
+}
+
+func TestHookDelivery_AddAttempt(t *testing.T) {
+	delivery := &HookDelivery{Status: HookDeliveryStatusPending}
+
+	retryDelay := 10 * time.Second
+
+	// 1st failed attempt
+	delivery.AddAttempt(500, errors.New("test"), 3, retryDelay)
+	if delivery.Status != HookDeliveryStatusPending || len(delivery.Attempts) != 1 {
+		t.Fatal("Expected 1 attempt and pending status, got", delivery)
+	}
+
+	if delivery.Attempts[0].Code != 500 || delivery.Attempts[0].Error != "test" {
+		t.Error("Expected the attempt response to be stored, got", delivery.Attempts[0])
+	}
+
+	if diff := delivery.NextAttempt - delivery.Attempts[0].Created; diff != 10 {
+		t.Error("Expected the next attempt to be after 10 seconds, got", diff)
+	}
+
+	// 2nd failed attempt
+	delivery.AddAttempt(0, errors.New("test"), 3, retryDelay)
+	if diff := delivery.NextAttempt - delivery.Attempts[1].Created; diff != 20 {
+		t.Error("Expected the next attempt to be after 20 seconds, got", diff)
+	}
+
+	// 3rd failed attempt
+	delivery.AddAttempt(0, errors.New("test"), 3, retryDelay)
+	if delivery.Status != HookDeliveryStatusFailed || delivery.NextAttempt != 0 {
+		t.Error("Expected failed status without next attempt, got", delivery)
+	}
+
+	// successful attempt
+	delivery.AddAttempt(200, nil, 3, retryDelay)
+	if delivery.Status != HookDeliveryStatusSuccess || delivery.NextAttempt != 0 || len(delivery.Attempts) != 4 {
+		t.Error("Expected success status without next attempt, got", delivery)
+	}
+}
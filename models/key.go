@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gofreta/gofreta-api/app"
@@ -18,39 +19,109 @@ type (
 		Title    string              `json:"title" bson:"title"`
 		Token    string              `json:"token" bson:"token"`
 		Access   map[string][]string `json:"access" bson:"access"`
+		Roles    []bson.ObjectId     `json:"roles" bson:"roles"`
+		Expires  int64               `json:"expires" bson:"expires"`
+		LastUsed int64               `json:"last_used" bson:"last_used"`
 		Created  int64               `json:"created" bson:"created"`
 		Modified int64               `json:"modified" bson:"modified"`
+
+		// the replaced token after a key rotation and its grace period end
+		PreviousToken        string `json:"-" bson:"previous_token"`
+		PreviousTokenExpires int64  `json:"previous_token_expires" bson:"previous_token_expires"`
 	}
 
 	// KeyForm defines the update/create form model fields.
 	KeyForm struct {
-		Model  *Key                `json:"-" form:"-"`
-		Title  string              `json:"title" form:"title"`
-		Access map[string][]string `json:"access" form:"access"`
+		Model   *Key                `json:"-" form:"-"`
+		Title   string              `json:"title" form:"title"`
+		Access  map[string][]string `json:"access" form:"access"`
+		Roles   []bson.ObjectId     `json:"roles" form:"roles"`
+		Expires int64               `json:"expires" form:"expires"`
 	}
 )
 
 // NewAuthToken generates and returns new api key authentication token.
-func (m Key) NewAuthToken(exp int64) (string, error) {
+// The optional jti claim is used to distinguish the rotated key tokens.
+func (m Key) NewAuthToken(exp int64, jti ...string) (string, error) {
 	claims := jwt.MapClaims{
 		"id":    m.ID.Hex(),
 		"model": "key",
 		"exp":   exp,
 	}
 
+	if len(jti) > 0 && jti[0] != "" {
+		claims["jti"] = jti[0]
+	}
+
 	signingKey := app.Config.GetString("jwt.signingKey")
 
 	return auth.NewJWT(claims, signingKey)
 }
 
+// IsExpired checks whether the key has an expiration date that has already passed.
+func (m Key) IsExpired() bool {
+	return m.Expires > 0 && m.Expires <= time.Now().Unix()
+}
+
+// IsValidToken checks whether the provided token is the current key token
+// or the previous one that is still in its rotation grace period.
+func (m Key) IsValidToken(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	if token == m.Token {
+		return true
+	}
+
+	return token == m.PreviousToken && m.PreviousTokenExpires > time.Now().Unix()
+}
+
+// RotateToken replaces the key token with a new one and keeps
+// the old token valid for the provided grace period (in seconds).
+func (m *Key) RotateToken(grace int64) error {
+	token, err := m.NewAuthToken(0, bson.NewObjectId().Hex())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	m.PreviousToken = m.Token
+	m.PreviousTokenExpires = 0
+	if grace > 0 {
+		m.PreviousTokenExpires = now + grace
+	}
+	m.Token = token
+	m.Modified = now
+
+	return nil
+}
+
 // Validate validates the KeyForm struct fields.
 func (m KeyForm) Validate() error {
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.Title, validation.Required),
-		validation.Field(&m.Access, validation.Required),
+		validation.Field(&m.Access, accessRules(m.Roles)...),
+		validation.Field(&m.Roles, validation.By(checkRoleIds)),
+		validation.Field(&m.Expires, validation.Min(0), validation.By(checkKeyExpires(m.Model))),
 	)
 }
 
+// checkKeyExpires checks whether the validating expires time is 0 (aka. never expires)
+// or in the future (an unchanged expires time of an existing key is also allowed).
+func checkKeyExpires(model *Key) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(int64)
+
+		if v <= 0 || v > time.Now().Unix() || (model != nil && model.Expires == v) {
+			return nil
+		}
+
+		return errors.New("Must be 0 or a future time.")
+	}
+}
+
 // ResolveModel resolves and returns the form Key model.
 // If the form doesn't have a Key model, it will instantiate a new one.
 func (m KeyForm) ResolveModel() *Key {
@@ -70,6 +141,11 @@ func (m KeyForm) ResolveModel() *Key {
 
 	model.Title = m.Title
 	model.Access = m.Access
+	model.Roles = m.Roles
+	if model.Roles == nil {
+		model.Roles = []bson.ObjectId{}
+	}
+	model.Expires = m.Expires
 	model.Modified = now
 
 	return &model
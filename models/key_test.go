@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofreta/gofreta-api/app"
 
@@ -39,6 +40,99 @@ func TestKey_NewAuthToken(t *testing.T) {
 	if err != nil || string(claims) != expected {
 		t.Errorf("%s claims were expected, got %s (error: %v)", expected, string(claims), err)
 	}
+
+	// with jti claim
+	jtiToken, jtiErr := model.NewAuthToken(0, "test_jti")
+	if jtiErr != nil {
+		t.Fatal("Did not expect error, got ", jtiErr)
+	}
+
+	jtiClaims, _ := base64.RawURLEncoding.DecodeString(strings.Split(jtiToken, ".")[1])
+	jtiExpected := `{"exp":0,"id":"507f191e810c19729de860ea","jti":"test_jti","model":"key"}`
+	if string(jtiClaims) != jtiExpected {
+		t.Errorf("%s claims were expected, got %s", jtiExpected, string(jtiClaims))
+	}
+}
+
+func TestKey_IsExpired(t *testing.T) {
+	now := time.Now().Unix()
+
+	testScenarios := []struct {
+		Expires  int64
+		Expected bool
+	}{
+		{0, false},
+		{now + 100, false},
+		{now, true},
+		{now - 100, true},
+	}
+
+	for _, scenario := range testScenarios {
+		model := &Key{Expires: scenario.Expires}
+
+		if result := model.IsExpired(); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestKey_IsValidToken(t *testing.T) {
+	now := time.Now().Unix()
+
+	testScenarios := []struct {
+		Model    *Key
+		Token    string
+		Expected bool
+	}{
+		{&Key{}, "", false},
+		{&Key{Token: "test"}, "", false},
+		{&Key{Token: "test"}, "missing", false},
+		{&Key{Token: "test"}, "test", true},
+		{&Key{Token: "test", PreviousToken: "old", PreviousTokenExpires: now - 100}, "old", false},
+		{&Key{Token: "test", PreviousToken: "old", PreviousTokenExpires: now + 100}, "old", true},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := scenario.Model.IsValidToken(scenario.Token); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestKey_RotateToken(t *testing.T) {
+	app.InitConfig("")
+
+	testScenarios := []struct {
+		Grace          int64
+		ExpectOldValid bool
+	}{
+		{0, false},
+		{3600, true},
+	}
+
+	for _, scenario := range testScenarios {
+		model := &Key{ID: bson.ObjectIdHex("507f191e810c19729de860ea"), Token: "test"}
+
+		if err := model.RotateToken(scenario.Grace); err != nil {
+			t.Fatalf("Did not expect error, got %v (scenario %v)", err, scenario)
+		}
+
+		if model.Token == "" || model.Token == "test" {
+			t.Errorf("Expected new token to be generated, got %q (scenario %v)", model.Token, scenario)
+		}
+
+		if model.PreviousToken != "test" {
+			t.Errorf("Expected the previous token to be test, got %q (scenario %v)", model.PreviousToken, scenario)
+		}
+
+		if model.IsValidToken("test") != scenario.ExpectOldValid {
+			t.Errorf("Expected the previous token validity to be %v (scenario %v)", scenario.ExpectOldValid, scenario)
+		}
+
+		if !model.IsValidToken(model.Token) {
+			t.Errorf("Expected the new token to be valid (scenario %v)", scenario)
+		}
+	}
 }
 
 func TestKeyForm_Validate(t *testing.T) {
@@ -51,9 +145,56 @@ func TestKeyForm_Validate(t *testing.T) {
 		Access: map[string][]string{"group1": []string{"action1", "action2"}},
 	}
 
+	// invalid expires
+	f3 := &KeyForm{
+		Title:   "test",
+		Access:  map[string][]string{"group1": []string{"action1", "action2"}},
+		Expires: -1,
+	}
+
+	// roles without own access
+	f4 := &KeyForm{
+		Title: "test",
+		Roles: []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")},
+	}
+
+	// invalid role id
+	f5 := &KeyForm{
+		Title: "test",
+		Roles: []bson.ObjectId{bson.ObjectId("invalid")},
+	}
+
+	// past expires
+	f6 := &KeyForm{
+		Title:   "test",
+		Access:  map[string][]string{"group1": []string{"action1", "action2"}},
+		Expires: time.Now().Unix() - 100,
+	}
+
+	// unchanged past expires of an existing key
+	f7 := &KeyForm{
+		Model:   &Key{Expires: 100},
+		Title:   "test",
+		Access:  map[string][]string{"group1": []string{"action1", "action2"}},
+		Expires: 100,
+	}
+
+	// future expires
+	f8 := &KeyForm{
+		Title:   "test",
+		Access:  map[string][]string{"group1": []string{"action1", "action2"}},
+		Expires: time.Now().Unix() + 100,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{f1, []string{"title", "access"}},
 		{f2, []string{}},
+		{f3, []string{"expires"}},
+		{f4, []string{}},
+		{f5, []string{"roles"}},
+		{f6, []string{"expires"}},
+		{f7, []string{}},
+		{f8, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
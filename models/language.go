@@ -1,9 +1,12 @@
 package models
 
 import (
+	"errors"
 	"regexp"
 	"time"
 
+	"github.com/gofreta/gofreta-api/utils"
+
 	"github.com/globalsign/mgo/bson"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
@@ -14,15 +17,17 @@ type (
 		ID       bson.ObjectId `json:"id" bson:"_id"`
 		Locale   string        `json:"locale" bson:"locale"`
 		Title    string        `json:"title" bson:"title"`
+		Fallback []string      `json:"fallback" bson:"fallback"`
 		Created  int64         `json:"created" bson:"created"`
 		Modified int64         `json:"modified" bson:"modified"`
 	}
 
 	// LanguageForm defines the update/create form model fields.
 	LanguageForm struct {
-		Model  *Language `json:"-" form:"-"`
-		Title  string    `json:"title" form:"title"`
-		Locale string    `json:"locale" form:"locale"`
+		Model    *Language `json:"-" form:"-"`
+		Title    string    `json:"title" form:"title"`
+		Locale   string    `json:"locale" form:"locale"`
+		Fallback []string  `json:"fallback" form:"fallback"`
 	}
 )
 
@@ -30,10 +35,70 @@ type (
 func (m LanguageForm) Validate() error {
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.Title, validation.Required),
-		validation.Field(&m.Locale, validation.Required, validation.Match(regexp.MustCompile(`^\w+$`))),
+		validation.Field(
+			&m.Locale,
+			validation.Required,
+			validation.Match(regexp.MustCompile(`^\w+$`)),
+			validation.NotIn(EntitySharedDataKey).Error("The locale is reserved."),
+		),
+		validation.Field(&m.Fallback, validation.By(checkFallbackLocales(m.Locale))),
 	)
 }
 
+// checkFallbackLocales checks whether the validating fallback locales
+// are unique and doesn't contain the language `locale` itself.
+func checkFallbackLocales(locale string) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.([]string)
+
+		checked := []string{}
+
+		for _, item := range v {
+			if item == locale {
+				return errors.New("The language can't fallback to itself.")
+			}
+
+			if utils.StringInSlice(item, checked) {
+				return errors.New("Fallback locale '" + item + "' exist more than once.")
+			}
+
+			checked = append(checked, item)
+		}
+
+		return nil
+	}
+}
+
+// FallbackChain returns the ordered list of locales that should be used to resolve
+// the language data values - the language locale followed by its fallback locales
+// (and their fallbacks, depth-first). Missing and already listed locales are skipped.
+func (m Language) FallbackChain(languages []Language) []string {
+	chain := []string{}
+
+	var walk func(lang Language)
+	walk = func(lang Language) {
+		if utils.StringInSlice(lang.Locale, chain) {
+			return
+		}
+
+		chain = append(chain, lang.Locale)
+
+		for _, locale := range lang.Fallback {
+			for _, item := range languages {
+				if item.Locale == locale {
+					walk(item)
+
+					break
+				}
+			}
+		}
+	}
+
+	walk(m)
+
+	return chain
+}
+
 // ResolveModel resolves and returns the form Language model.
 // If the form doesn't have a Language model, it will instantiate a new one.
 func (m LanguageForm) ResolveModel() *Language {
@@ -52,6 +117,10 @@ func (m LanguageForm) ResolveModel() *Language {
 
 	model.Title = m.Title
 	model.Locale = m.Locale
+	model.Fallback = m.Fallback
+	if model.Fallback == nil {
+		model.Fallback = []string{}
+	}
 	model.Modified = now
 
 	return &model
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/globalsign/mgo/bson"
@@ -22,10 +23,41 @@ func TestLanguageForm_Validate(t *testing.T) {
 		Locale: "test",
 	}
 
+	// self fallback
+	f4 := &LanguageForm{
+		Title:    "test",
+		Locale:   "test",
+		Fallback: []string{"en", "test"},
+	}
+
+	// duplicated fallback
+	f5 := &LanguageForm{
+		Title:    "test",
+		Locale:   "test",
+		Fallback: []string{"en", "bg", "en"},
+	}
+
+	// valid fallback
+	f6 := &LanguageForm{
+		Title:    "test",
+		Locale:   "test",
+		Fallback: []string{"en", "bg"},
+	}
+
+	// reserved locale
+	f7 := &LanguageForm{
+		Title:  "test",
+		Locale: EntitySharedDataKey,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{f1, []string{"locale", "title"}},
 		{f2, []string{"locale"}},
 		{f3, []string{}},
+		{f4, []string{"fallback"}},
+		{f5, []string{"fallback"}},
+		{f6, []string{}},
+		{f7, []string{"locale"}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -72,6 +104,10 @@ func TestLanguageForm_ResolveModel(t *testing.T) {
 			t.Errorf("Expected resolved model locale to be %s, got %s", scenario.Locale, resolvedModel.Locale)
 		}
 
+		if resolvedModel.Fallback == nil {
+			t.Error("Expected resolved model fallback to be initialized")
+		}
+
 		if scenario.Model == nil { // new
 			if resolvedModel.ID.Hex() == "" {
 				t.Error("Expected resolved model id to be set")
@@ -99,3 +135,32 @@ func TestLanguageForm_ResolveModel(t *testing.T) {
 		}
 	}
 }
+
+func TestLanguage_FallbackChain(t *testing.T) {
+	languages := []Language{
+		{Locale: "en", Fallback: []string{}},
+		{Locale: "de", Fallback: []string{"en"}},
+		{Locale: "deat", Fallback: []string{"de", "missing", "en"}},
+		{Locale: "bg", Fallback: []string{"ru"}},
+		{Locale: "ru", Fallback: []string{"bg", "en"}}, // circular
+	}
+
+	testScenarios := []struct {
+		Language Language
+		Expected []string
+	}{
+		{languages[0], []string{"en"}},
+		{languages[1], []string{"de", "en"}},
+		{languages[2], []string{"deat", "de", "en"}},
+		{languages[3], []string{"bg", "ru", "en"}},
+		{languages[4], []string{"ru", "bg", "en"}},
+	}
+
+	for _, scenario := range testScenarios {
+		result := scenario.Language.FallbackChain(languages)
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v chain for %s, got %v", scenario.Expected, scenario.Language.Locale, result)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type (
+	// Role defines the Role model fields (reusable set of access permissions).
+	Role struct {
+		ID       bson.ObjectId       `json:"id" bson:"_id"`
+		Title    string              `json:"title" bson:"title"`
+		Access   map[string][]string `json:"access" bson:"access"`
+		Created  int64               `json:"created" bson:"created"`
+		Modified int64               `json:"modified" bson:"modified"`
+	}
+
+	// RoleForm defines the update/create form model fields.
+	RoleForm struct {
+		Model  *Role               `json:"-" form:"-"`
+		Title  string              `json:"title" form:"title"`
+		Access map[string][]string `json:"access" form:"access"`
+	}
+)
+
+// Validate validates the RoleForm struct fields.
+func (m RoleForm) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Title, validation.Required),
+		validation.Field(&m.Access, validation.Required),
+	)
+}
+
+// ResolveModel resolves and returns the form Role model.
+// If the form doesn't have a Role model, it will instantiate a new one.
+func (m RoleForm) ResolveModel() *Role {
+	var model Role
+
+	now := time.Now().Unix()
+
+	// is new
+	if m.Model == nil {
+		model = Role{}
+		model.ID = bson.NewObjectId()
+		model.Created = now
+	} else {
+		model = *m.Model
+	}
+
+	model.Title = m.Title
+	model.Access = m.Access
+	model.Modified = now
+
+	return &model
+}
+
+// MergeAccess returns a new access map with the union of the groups and actions
+// of all provided access maps (eg. the identity roles and its own access).
+func MergeAccess(accessList ...map[string][]string) map[string][]string {
+	result := map[string][]string{}
+
+	for _, access := range accessList {
+		for group, actions := range access {
+			if _, ok := result[group]; !ok {
+				result[group] = []string{}
+			}
+
+		ACTIONS_LOOP:
+			for _, action := range actions {
+				for _, existing := range result[group] {
+					if existing == action {
+						continue ACTIONS_LOOP
+					}
+				}
+
+				result[group] = append(result[group], action)
+			}
+		}
+	}
+
+	return result
+}
+
+// accessRules returns the identity access field validation rules
+// (the access is optional if the identity has at least one role).
+func accessRules(roles []bson.ObjectId) []validation.Rule {
+	if len(roles) > 0 {
+		return nil
+	}
+
+	return []validation.Rule{validation.Required}
+}
+
+// checkRoleIds checks whether the validating role ids are valid and unique.
+func checkRoleIds(value interface{}) error {
+	v, _ := value.([]bson.ObjectId)
+
+	checked := []bson.ObjectId{}
+
+	for _, id := range v {
+		if !id.Valid() {
+			return errors.New("Invalid role id.")
+		}
+
+		for _, item := range checked {
+			if item == id {
+				return errors.New("Role '" + id.Hex() + "' exist more than once.")
+			}
+		}
+
+		checked = append(checked, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestRoleForm_Validate(t *testing.T) {
+	// empty form
+	f1 := &RoleForm{}
+
+	// populated form
+	f2 := &RoleForm{
+		Title:  "test",
+		Access: map[string][]string{"group1": []string{"action1", "action2"}},
+	}
+
+	testScenarios := []TestValidateScenario{
+		{f1, []string{"title", "access"}},
+		{f2, []string{}},
+	}
+
+	testValidateScenarios(t, testScenarios)
+}
+
+func TestRoleForm_ResolveModel(t *testing.T) {
+	testScenarios := []struct {
+		Model  *Role
+		Title  string
+		Access map[string][]string
+	}{
+		{nil, "test", map[string][]string{"group1": []string{"action1", "action2"}}},
+		{
+			&Role{
+				ID:       bson.ObjectIdHex("507f191e810c19729de860ea"),
+				Title:    "test",
+				Access:   map[string][]string{"test": []string{"action1"}},
+				Created:  1518773370,
+				Modified: 1518773370,
+			},
+			"test2",
+			map[string][]string{"group1": []string{"action1", "action2"}},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		form := &RoleForm{
+			Model:  scenario.Model,
+			Title:  scenario.Title,
+			Access: scenario.Access,
+		}
+
+		resolvedModel := form.ResolveModel()
+
+		if resolvedModel == nil {
+			t.Fatal("Expected Role model pointer, got nil")
+		}
+
+		if resolvedModel.Title != scenario.Title {
+			t.Errorf("Expected resolved model title to be %s, got %s", scenario.Title, resolvedModel.Title)
+		}
+
+		if !reflect.DeepEqual(resolvedModel.Access, scenario.Access) {
+			t.Errorf("Expected resolved model access to be %v, got %v", scenario.Access, resolvedModel.Access)
+		}
+
+		if scenario.Model == nil { // new
+			if resolvedModel.ID.Hex() == "" {
+				t.Error("Expected resolved model id to be set")
+			}
+
+			if resolvedModel.Created != resolvedModel.Modified || resolvedModel.Created == 0 {
+				t.Errorf("Expected equal > 0 values for the timestamp modifiers, got: %d, %d", resolvedModel.Created, resolvedModel.Modified)
+			}
+		} else { // update
+			if resolvedModel.ID != scenario.Model.ID {
+				t.Errorf("Expected %s id, got %s", scenario.Model.ID.Hex(), resolvedModel.ID.Hex())
+			}
+
+			if resolvedModel.Created != scenario.Model.Created {
+				t.Errorf("Expected the created timestamp to not be changed, got %d", resolvedModel.Created)
+			}
+
+			if resolvedModel.Modified <= scenario.Model.Modified {
+				t.Errorf("Expected the modified timestamp to be updated, got %d", resolvedModel.Modified)
+			}
+		}
+	}
+}
+
+func TestMergeAccess(t *testing.T) {
+	testScenarios := []struct {
+		AccessList []map[string][]string
+		Expected   map[string][]string
+	}{
+		{nil, map[string][]string{}},
+		{
+			[]map[string][]string{
+				map[string][]string{"media": []string{"index", "view"}, "key": []string{}},
+			},
+			map[string][]string{"media": []string{"index", "view"}, "key": []string{}},
+		},
+		{
+			[]map[string][]string{
+				map[string][]string{"media": []string{"index", "view"}, "collection": []string{"index"}},
+				nil,
+				map[string][]string{"media": []string{"view", "upload"}, "user": []string{"index"}},
+			},
+			map[string][]string{"media": []string{"index", "view", "upload"}, "collection": []string{"index"}, "user": []string{"index"}},
+		},
+	}
+
+	for i, scenario := range testScenarios {
+		result := MergeAccess(scenario.AccessList...)
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("Expected %v, got %v (scenario %d)", scenario.Expected, result, i)
+		}
+	}
+
+	// the source access maps shouldn't be modified
+	source := map[string][]string{"media": []string{"index"}}
+
+	MergeAccess(source, map[string][]string{"media": []string{"view"}})
+
+	if len(source["media"]) != 1 {
+		t.Errorf("Expected the source access to not be modified, got %v", source)
+	}
+}
+
+func TestCheckRoleIds(t *testing.T) {
+	testScenarios := []struct {
+		Value       interface{}
+		ExpectError bool
+	}{
+		{nil, false},
+		{[]bson.ObjectId{}, false},
+		{[]bson.ObjectId{bson.ObjectId("invalid")}, true},
+		{[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")}, true},
+		{[]bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e002")}, false},
+	}
+
+	for _, scenario := range testScenarios {
+		err := checkRoleIds(scenario.Value)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+// SchemaDiff defines the changes between two collection fields schemas
+// that need to be applied to the existing collection entities.
+type SchemaDiff struct {
+	Removed      []string          `json:"removed"`
+	Renamed      map[string]string `json:"renamed"`
+	Recast       []string          `json:"recast"`
+	Multilingual []string          `json:"multilingual"`
+	Affected     int               `json:"affected"`
+}
+
+// NewSchemaDiff compares the old and new collection fields and returns their schema diff.
+// `renames` holds the renamed field keys in `oldKey => newKey` format.
+// NB! Old field with a key that is used as a rename target (and is not renamed itself) is considered removed.
+func NewSchemaDiff(oldFields []CollectionField, newFields []CollectionField, renames map[string]string) *SchemaDiff {
+	diff := &SchemaDiff{
+		Removed:      []string{},
+		Renamed:      map[string]string{},
+		Recast:       []string{},
+		Multilingual: []string{},
+	}
+
+	renameTargets := map[string]bool{}
+	for oldKey, newKey := range renames {
+		if oldKey != newKey {
+			renameTargets[newKey] = true
+		}
+	}
+
+	for _, oldField := range oldFields {
+		newKey, isRenamed := renames[oldField.Key]
+		if !isRenamed || newKey == oldField.Key {
+			newKey = oldField.Key
+			isRenamed = false
+		}
+
+		newField, exist := findField(newFields, newKey)
+
+		if !exist || (!isRenamed && renameTargets[newKey]) {
+			diff.Removed = append(diff.Removed, oldField.Key)
+
+			continue
+		}
+
+		if isRenamed {
+			diff.Renamed[oldField.Key] = newKey
+		}
+
+		if oldField.Type != newField.Type || !isSameMeta(oldField.Meta, newField.Meta) {
+			diff.Recast = append(diff.Recast, newKey)
+		}
+
+		if oldField.Multilingual != newField.Multilingual {
+			diff.Multilingual = append(diff.Multilingual, newKey)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty checks whether the schema diff has any changes.
+func (m SchemaDiff) IsEmpty() bool {
+	return len(m.Removed) == 0 && len(m.Renamed) == 0 && len(m.Recast) == 0 && len(m.Multilingual) == 0
+}
+
+// findField returns the field with the specified key from the provided list.
+func findField(fields []CollectionField, key string) (CollectionField, bool) {
+	for _, field := range fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+
+	return CollectionField{}, false
+}
+
+// isSameMeta checks whether two field meta values are equal
+// (the values are compared by their json representation since they could be
+// stored either as bson, plain map or meta struct).
+func isSameMeta(a interface{}, b interface{}) bool {
+	normalize := func(meta interface{}) string {
+		raw, _ := json.Marshal(meta)
+
+		normalized := map[string]interface{}{}
+		json.Unmarshal(raw, &normalized)
+
+		result, _ := json.Marshal(normalized)
+
+		return string(result)
+	}
+
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewSchemaDiff(t *testing.T) {
+	oldFields := []CollectionField{
+		{Key: "title", Type: FieldTypePlain},
+		{Key: "summary", Type: FieldTypePlain},
+		{Key: "code", Type: FieldTypePlain, Multilingual: true},
+		{Key: "price", Type: FieldTypePlain},
+		{Key: "rating", Type: FieldTypeNumber, Meta: bson.M{"precision": 1}},
+		{Key: "body", Type: FieldTypeEditor, Meta: bson.M{"mode": "rich"}},
+		{Key: "a", Type: FieldTypePlain},
+		{Key: "b", Type: FieldTypePlain},
+		{Key: "obsolete", Type: FieldTypePlain},
+	}
+
+	newFields := []CollectionField{
+		{Key: "title", Type: FieldTypePlain},
+		{Key: "description", Type: FieldTypePlain},
+		{Key: "code", Type: FieldTypePlain},
+		{Key: "price", Type: FieldTypeNumber},
+		{Key: "rating", Type: FieldTypeNumber, Meta: map[string]interface{}{"precision": 2}},
+		{Key: "body", Type: FieldTypeEditor, Meta: map[string]interface{}{"mode": "rich"}},
+		{Key: "b", Type: FieldTypePlain},
+	}
+
+	renames := map[string]string{
+		"summary": "description",
+		"a":       "b",
+		"title":   "title",
+	}
+
+	diff := NewSchemaDiff(oldFields, newFields, renames)
+
+	expected := &SchemaDiff{
+		Removed:      []string{"b", "obsolete"},
+		Renamed:      map[string]string{"summary": "description", "a": "b"},
+		Recast:       []string{"price", "rating"},
+		Multilingual: []string{"code"},
+	}
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected %v, got %v", expected, diff)
+	}
+
+	if diff.IsEmpty() {
+		t.Error("Expected non empty diff")
+	}
+}
+
+func TestSchemaDiff_IsEmpty(t *testing.T) {
+	fields := []CollectionField{
+		{Key: "title", Type: FieldTypePlain},
+		{Key: "body", Type: FieldTypeEditor, Meta: bson.M{"mode": "rich"}},
+	}
+
+	if diff := NewSchemaDiff(fields, fields, nil); !diff.IsEmpty() {
+		t.Error("Expected empty diff, got", diff)
+	}
+
+	if diff := NewSchemaDiff(fields, fields[1:], nil); diff.IsEmpty() {
+		t.Error("Expected non empty diff")
+	}
+}
+
+func TestCollectionForm_SchemaDiff(t *testing.T) {
+	// new model
+	f1 := CollectionForm{
+		Fields: []CollectionField{{Key: "title", Type: FieldTypePlain}},
+	}
+
+	if diff := f1.SchemaDiff(); !diff.IsEmpty() {
+		t.Error("Expected empty diff, got", diff)
+	}
+
+	// existing model
+	f2 := CollectionForm{
+		Model: &Collection{
+			Fields: []CollectionField{{Key: "title", Type: FieldTypePlain}},
+		},
+		Fields:  []CollectionField{{Key: "name", Type: FieldTypePlain}},
+		Renames: map[string]string{"title": "name"},
+	}
+
+	if diff := f2.SchemaDiff(); diff.Renamed["title"] != "name" {
+		t.Error("Expected title to be renamed, got", diff)
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SearchSnippetRadius defines the number of characters around the first
+// matched search term word that are included in a search result snippet.
+const SearchSnippetRadius = 60
+
+// stripTagsRegex matches html tags (used to extract the plain text of an editor field).
+var stripTagsRegex = regexp.MustCompile(`<[^>]*>`)
+
+// SearchResult defines single full-text search result item.
+type SearchResult struct {
+	Entity   `bson:",inline"`
+	Score    float64                      `json:"score" bson:"score"`
+	Snippets map[string]map[string]string `json:"snippets" bson:"-"`
+}
+
+// ResolveSnippets generates highlighted snippets for each searchable
+// entity data field that contains any of the search term words.
+func (m *SearchResult) ResolveSnippets(collection *Collection, term string) {
+	m.Snippets = map[string]map[string]string{}
+
+	wordsRegex := searchWordsRegex(term)
+	if wordsRegex == nil {
+		return
+	}
+
+	for locale, data := range m.Data {
+		for _, field := range collection.Fields {
+			if !field.Searchable {
+				continue
+			}
+
+			snippet := NewSearchSnippet(searchableText(data[field.Key]), wordsRegex)
+			if snippet == "" {
+				continue
+			}
+
+			if _, ok := m.Snippets[locale]; !ok {
+				m.Snippets[locale] = map[string]string{}
+			}
+
+			m.Snippets[locale][field.Key] = snippet
+		}
+	}
+}
+
+// NewSearchSnippet returns a html escaped part of `text` around the first `wordsRegex` match
+// with all matched words wrapped in `<mark>` tags. Returns empty string if there are no matches.
+func NewSearchSnippet(text string, wordsRegex *regexp.Regexp) string {
+	loc := wordsRegex.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+
+	runes := []rune(text)
+	matchStart := len([]rune(text[:loc[0]]))
+	matchEnd := len([]rune(text[:loc[1]]))
+
+	start := matchStart - SearchSnippetRadius
+	if start < 0 {
+		start = 0
+	}
+
+	end := matchEnd + SearchSnippetRadius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	window := string(runes[start:end])
+
+	// highlight the matched words
+	result := ""
+	lastIndex := 0
+	for _, match := range wordsRegex.FindAllStringIndex(window, -1) {
+		result += html.EscapeString(window[lastIndex:match[0]])
+		result += "<mark>" + html.EscapeString(window[match[0]:match[1]]) + "</mark>"
+		lastIndex = match[1]
+	}
+	result += html.EscapeString(window[lastIndex:])
+
+	if start > 0 {
+		result = "..." + result
+	}
+
+	if end < len(runes) {
+		result += "..."
+	}
+
+	return result
+}
+
+// NewEntitySearchText returns the plain text values of all searchable entity data fields.
+func NewEntitySearchText(data map[string]map[string]interface{}, collection *Collection) []string {
+	result := []string{}
+
+	// sort the locales to keep the result consistent
+	locales := []string{}
+	for locale, _ := range data {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		for _, field := range collection.Fields {
+			if !field.Searchable {
+				continue
+			}
+
+			if text := searchableText(data[locale][field.Key]); text != "" {
+				result = append(result, text)
+			}
+		}
+	}
+
+	return result
+}
+
+// searchableText returns the plain text representation of a searchable field value.
+func searchableText(value interface{}) string {
+	str, _ := value.(string)
+
+	str = html.UnescapeString(stripTagsRegex.ReplaceAllString(str, " "))
+
+	return strings.Join(strings.Fields(str), " ")
+}
+
+// searchWordsRegex builds case insensitive regex that matches any of the search term words.
+func searchWordsRegex(term string) *regexp.Regexp {
+	words := []string{}
+
+	for _, word := range strings.Fields(term) {
+		// skip the negated words
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+
+		// strip the phrase quotes
+		word = strings.Trim(word, `"`)
+
+		if word != "" {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	return regexp.MustCompile(`(?i)` + strings.Join(words, "|"))
+}
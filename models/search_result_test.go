@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewSearchSnippet(t *testing.T) {
+	long := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+
+	scenarios := []struct {
+		Text     string
+		Term     string
+		Expected string
+	}{
+		{"", "test", ""},
+		{"no matches here", "test", ""},
+		{"Test <b> content", "test", "<mark>Test</mark> &lt;b&gt; content"},
+		{"Lorem ipsum dolor ipsum", "IPSUM dolor", "Lorem <mark>ipsum</mark> <mark>dolor</mark> <mark>ipsum</mark>"},
+		{long, "needle", "..." + strings.Repeat("a", SearchSnippetRadius-1) + " <mark>needle</mark> " + strings.Repeat("b", SearchSnippetRadius-1) + "..."},
+	}
+
+	for i, s := range scenarios {
+		result := NewSearchSnippet(s.Text, searchWordsRegex(s.Term))
+
+		if result != s.Expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.Expected, result)
+		}
+	}
+}
+
+func TestSearchWordsRegex(t *testing.T) {
+	if r := searchWordsRegex("   "); r != nil {
+		t.Error("Expected nil regex for empty term, got", r)
+	}
+
+	if r := searchWordsRegex("-excluded"); r != nil {
+		t.Error("Expected nil regex for negated only term, got", r)
+	}
+
+	r := searchWordsRegex(`"quoted" a.b -excluded`)
+	if r == nil {
+		t.Fatal("Expected non-nil regex")
+	}
+
+	if !r.MatchString("QUOTED") || !r.MatchString("a.b") {
+		t.Error("Expected the term words to match, got", r)
+	}
+
+	if r.MatchString("axb") || r.MatchString("excluded") {
+		t.Error("Expected the term words to be quoted and the negated ones to be skipped, got", r)
+	}
+}
+
+func TestSearchResult_ResolveSnippets(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Searchable: true},
+		{Key: "content", Type: FieldTypeEditor, Searchable: true},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	model := &SearchResult{Entity: Entity{
+		Data: map[string]map[string]interface{}{
+			"en": map[string]interface{}{"title": "Test title", "content": "<p>Some test content</p>", "code": "test"},
+			"bg": map[string]interface{}{"title": "Other title", "content": "", "code": "test"},
+		},
+	}}
+
+	model.ResolveSnippets(collection, "test")
+
+	if len(model.Snippets) != 1 {
+		t.Fatal("Expected only en snippets, got", model.Snippets)
+	}
+
+	if len(model.Snippets["en"]) != 2 {
+		t.Fatal("Expected 2 en snippets, got", model.Snippets["en"])
+	}
+
+	if model.Snippets["en"]["title"] != "<mark>Test</mark> title" {
+		t.Error("Unexpected title snippet", model.Snippets["en"]["title"])
+	}
+
+	if model.Snippets["en"]["content"] != "Some <mark>test</mark> content" {
+		t.Error("Unexpected content snippet", model.Snippets["en"]["content"])
+	}
+}
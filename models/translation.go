@@ -0,0 +1,186 @@
+package models
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// TranslationFormatJson specifies the flat JSON translation file format.
+	TranslationFormatJson = "json"
+
+	// TranslationFormatXliff specifies the XLIFF 1.2 translation file format.
+	TranslationFormatXliff = "xliff"
+
+	// XliffNamespace defines the XLIFF 1.2 document namespace.
+	XliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+)
+
+type (
+	// TranslationFlags defines the entity field keys that need translation grouped by locale.
+	TranslationFlags map[string][]string
+
+	// TranslationUnit defines single translatable entity field value.
+	TranslationUnit struct {
+		ID     string
+		Source string
+		Target string
+	}
+
+	// TranslationImport defines the translation file import result.
+	TranslationImport struct {
+		Updated []bson.ObjectId        `json:"updated"`
+		Errors  map[string]interface{} `json:"errors"`
+
+		// the updated entity models (used for the update hooks)
+		Entities []Entity `json:"-"`
+	}
+
+	// Xliff defines the XLIFF 1.2 document structure.
+	Xliff struct {
+		XMLName xml.Name    `xml:"xliff"`
+		Xmlns   string      `xml:"xmlns,attr,omitempty"`
+		Version string      `xml:"version,attr"`
+		Files   []XliffFile `xml:"file"`
+	}
+
+	// XliffFile defines single XLIFF 1.2 document file element.
+	XliffFile struct {
+		Original       string      `xml:"original,attr"`
+		SourceLanguage string      `xml:"source-language,attr"`
+		TargetLanguage string      `xml:"target-language,attr,omitempty"`
+		Datatype       string      `xml:"datatype,attr"`
+		Units          []XliffUnit `xml:"body>trans-unit"`
+	}
+
+	// XliffUnit defines single XLIFF 1.2 document trans-unit element.
+	XliffUnit struct {
+		ID     string `xml:"id,attr"`
+		Source string `xml:"source"`
+		Target string `xml:"target"`
+	}
+)
+
+// Has checks whether the `key` field is flagged as needing translation in `locale`.
+func (m TranslationFlags) Has(locale string, key string) bool {
+	for _, item := range m[locale] {
+		if item == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewTranslationUnitID returns the translation unit identifier of an entity field value.
+func NewTranslationUnitID(entityID bson.ObjectId, fieldKey string) string {
+	return entityID.Hex() + "." + fieldKey
+}
+
+// ParseTranslationUnitID extracts the entity id and field key from a translation unit identifier.
+func ParseTranslationUnitID(id string) (bson.ObjectId, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+
+	if len(parts) != 2 || !bson.IsObjectIdHex(parts[0]) || parts[1] == "" {
+		return "", "", errors.New("Invalid translation unit id '" + id + "'.")
+	}
+
+	return bson.ObjectIdHex(parts[0]), parts[1], nil
+}
+
+// NewTranslationUnits extracts the `source` and `target` locale values of the collection
+// translatable fields from the latest entities data (the draft, if there is one).
+// If `pendingOnly` is set, only the fields that need translation in `target` are extracted.
+func NewTranslationUnits(
+	entities []Entity,
+	collection *Collection,
+	source string,
+	target string,
+	pendingOnly bool,
+) []TranslationUnit {
+	result := []TranslationUnit{}
+
+	fields := collection.TranslatableFields()
+
+	for _, entity := range entities {
+		data := entity.Draft
+		if len(data) == 0 {
+			data = entity.Data
+		}
+
+		for _, field := range fields {
+			if pendingOnly && !entity.NeedsTranslation.Has(target, field.Key) {
+				continue
+			}
+
+			sourceVal, _ := field.CastValue(data[source][field.Key]).(string)
+			if sourceVal == "" {
+				continue
+			}
+
+			targetVal, _ := field.CastValue(data[target][field.Key]).(string)
+
+			result = append(result, TranslationUnit{
+				ID:     NewTranslationUnitID(entity.ID, field.Key),
+				Source: sourceVal,
+				Target: targetVal,
+			})
+		}
+	}
+
+	return result
+}
+
+// NewXliff creates a new XLIFF 1.2 document from the provided translation units.
+func NewXliff(original string, source string, target string, units []TranslationUnit) *Xliff {
+	file := XliffFile{
+		Original:       original,
+		SourceLanguage: source,
+		TargetLanguage: target,
+		Datatype:       "plaintext",
+		Units:          []XliffUnit{},
+	}
+
+	for _, unit := range units {
+		file.Units = append(file.Units, XliffUnit{
+			ID:     unit.ID,
+			Source: unit.Source,
+			Target: unit.Target,
+		})
+	}
+
+	return &Xliff{
+		Xmlns:   XliffNamespace,
+		Version: "1.2",
+		Files:   []XliffFile{file},
+	}
+}
+
+// TargetLanguage returns the target language of the first document file.
+func (m Xliff) TargetLanguage() string {
+	if len(m.Files) == 0 {
+		return ""
+	}
+
+	return m.Files[0].TargetLanguage
+}
+
+// TranslationUnits returns the translation units of all document files.
+func (m Xliff) TranslationUnits() []TranslationUnit {
+	result := []TranslationUnit{}
+
+	for _, file := range m.Files {
+		for _, unit := range file.Units {
+			result = append(result, TranslationUnit{
+				ID:     unit.ID,
+				Source: unit.Source,
+				Target: unit.Target,
+			})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestTranslationFlags_Has(t *testing.T) {
+	flags := TranslationFlags{"bg": []string{"title", "description"}}
+
+	testScenarios := []struct {
+		Locale   string
+		Key      string
+		Expected bool
+	}{
+		{"en", "title", false},
+		{"bg", "missing", false},
+		{"bg", "title", true},
+		{"bg", "description", true},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := flags.Has(scenario.Locale, scenario.Key); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+
+	// nil flags
+	var nilFlags TranslationFlags
+	if nilFlags.Has("bg", "title") {
+		t.Error("Expected false for nil flags, got true")
+	}
+}
+
+func TestParseTranslationUnitID(t *testing.T) {
+	id := bson.ObjectIdHex("5a8bea3ae1382310bec8076b")
+
+	unitID := NewTranslationUnitID(id, "title")
+	if unitID != "5a8bea3ae1382310bec8076b.title" {
+		t.Fatalf("Expected 5a8bea3ae1382310bec8076b.title unit id, got %s", unitID)
+	}
+
+	testScenarios := []struct {
+		ID          string
+		ExpectError bool
+		ExpectedID  bson.ObjectId
+		ExpectedKey string
+	}{
+		{"", true, "", ""},
+		{"invalid.title", true, "", ""},
+		{"5a8bea3ae1382310bec8076b", true, "", ""},
+		{"5a8bea3ae1382310bec8076b.", true, "", ""},
+		{unitID, false, id, "title"},
+	}
+
+	for _, scenario := range testScenarios {
+		resultID, resultKey, err := ParseTranslationUnitID(scenario.ID)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if resultID != scenario.ExpectedID || resultKey != scenario.ExpectedKey {
+			t.Errorf("Expected %v and %s, got %v and %s (scenario %v)", scenario.ExpectedID, scenario.ExpectedKey, resultID, resultKey, scenario)
+		}
+	}
+}
+
+func TestNewTranslationUnits(t *testing.T) {
+	collection := &Collection{Fields: []CollectionField{
+		{Key: "title", Type: FieldTypePlain, Multilingual: true},
+		{Key: "content", Type: FieldTypeEditor, Multilingual: true},
+		{Key: "code", Type: FieldTypePlain},
+	}}
+
+	entities := []Entity{
+		{
+			ID: bson.ObjectIdHex("5a8bea3ae1382310bec8076b"),
+			Data: map[string]map[string]interface{}{
+				"en": map[string]interface{}{"title": "Title en", "content": "", "code": "abc"},
+				"bg": map[string]interface{}{"title": "Title bg", "content": "", "code": "abc"},
+			},
+		},
+		{
+			ID: bson.ObjectIdHex("5a8bea7ee1382310bec8076c"),
+			Data: map[string]map[string]interface{}{
+				"en": map[string]interface{}{"title": "Title en", "content": "<p>Content en</p>"},
+			},
+			Draft: map[string]map[string]interface{}{
+				"en": map[string]interface{}{"title": "Draft title en", "content": "<p>Draft content en</p>"},
+			},
+			NeedsTranslation: TranslationFlags{"bg": []string{"content"}},
+		},
+	}
+
+	// all
+	result1 := NewTranslationUnits(entities, collection, "en", "bg", false)
+	expected1 := []TranslationUnit{
+		{"5a8bea3ae1382310bec8076b.title", "Title en", "Title bg"},
+		{"5a8bea7ee1382310bec8076c.title", "Draft title en", ""},
+		{"5a8bea7ee1382310bec8076c.content", "<p>Draft content en</p>", ""},
+	}
+	if !reflect.DeepEqual(result1, expected1) {
+		t.Errorf("Expected %v, got %v", expected1, result1)
+	}
+
+	// pending only
+	result2 := NewTranslationUnits(entities, collection, "en", "bg", true)
+	expected2 := []TranslationUnit{
+		{"5a8bea7ee1382310bec8076c.content", "<p>Draft content en</p>", ""},
+	}
+	if !reflect.DeepEqual(result2, expected2) {
+		t.Errorf("Expected %v, got %v", expected2, result2)
+	}
+}
+
+func TestXliff(t *testing.T) {
+	units := []TranslationUnit{
+		{"5a8bea3ae1382310bec8076b.title", "Title en", "Title bg"},
+		{"5a8bea3ae1382310bec8076b.content", "<p>Content & more</p>", ""},
+	}
+
+	doc := NewXliff("col1", "en", "bg", units)
+
+	if doc.Version != "1.2" || doc.Xmlns != XliffNamespace {
+		t.Errorf("Expected XLIFF 1.2 document, got %v", doc)
+	}
+
+	raw, err := xml.Marshal(doc)
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	expected := `<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">` +
+		`<file original="col1" source-language="en" target-language="bg" datatype="plaintext"><body>` +
+		`<trans-unit id="5a8bea3ae1382310bec8076b.title"><source>Title en</source><target>Title bg</target></trans-unit>` +
+		`<trans-unit id="5a8bea3ae1382310bec8076b.content"><source>&lt;p&gt;Content &amp; more&lt;/p&gt;</source><target></target></trans-unit>` +
+		`</body></file></xliff>`
+	if string(raw) != expected {
+		t.Errorf("Expected %s, got %s", expected, raw)
+	}
+
+	parsed := Xliff{}
+	if err := xml.Unmarshal(raw, &parsed); err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if parsed.TargetLanguage() != "bg" {
+		t.Errorf("Expected bg target language, got %s", parsed.TargetLanguage())
+	}
+
+	if result := parsed.TranslationUnits(); !reflect.DeepEqual(result, units) {
+		t.Errorf("Expected %v units, got %v", units, result)
+	}
+
+	// empty document
+	if (Xliff{}).TargetLanguage() != "" {
+		t.Error("Expected empty target language")
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"regexp"
 	"strconv"
@@ -24,8 +26,17 @@ const (
 
 	// UserStatusActive specifies the inactive user model status state.
 	UserStatusInactive = "inactive"
+
+	// UserTwoFactorChallengeModel specifies the two-factor authentication challenge token model.
+	UserTwoFactorChallengeModel = "2fa"
+
+	// UserRecoveryCodesCount specifies the number of the generated two-factor recovery codes.
+	UserRecoveryCodesCount = 10
 )
 
+// twoFactorAccessGroups specifies the access groups which holders could be required to use two-factor authentication.
+var twoFactorAccessGroups = []string{"user", "key"}
+
 // -------------------------------------------------------------------
 // • User model
 // -------------------------------------------------------------------
@@ -38,9 +49,19 @@ type User struct {
 	Status            string              `json:"status" bson:"status"`
 	PasswordHash      string              `json:"-" bson:"password_hash"`
 	ResetPasswordHash string              `json:"-" bson:"reset_password_hash"`
+	TokensRevoked     int64               `json:"-" bson:"tokens_revoked"`
 	Access            map[string][]string `json:"access" bson:"access"`
+	Roles             []bson.ObjectId     `json:"roles" bson:"roles"`
 	Created           int64               `json:"created" bson:"created"`
 	Modified          int64               `json:"modified" bson:"modified"`
+
+	// two-factor authentication settings
+	RequireTwoFactor       bool     `json:"require_two_factor" bson:"require_two_factor"`
+	TwoFactorEnabled       bool     `json:"two_factor_enabled" bson:"two_factor_enabled"`
+	TwoFactorSecret        string   `json:"-" bson:"two_factor_secret"`
+	TwoFactorPendingSecret string   `json:"-" bson:"two_factor_pending_secret"`
+	TwoFactorRecoveryCodes []string `json:"-" bson:"two_factor_recovery_codes"`
+	TwoFactorLastCounter   int64    `json:"-" bson:"two_factor_last_counter"`
 }
 
 // ValidatePassword validates User model `PasswordHash` string against a plain password
@@ -70,18 +91,31 @@ func (m *User) SetPassword(password string) {
 }
 
 // NewAuthToken generates and returns new user authentication token.
-func (m User) NewAuthToken(exp int64) (string, error) {
+// Optionally you could bind the token to a user session (see `UserSession`),
+// so that it could be revoked before its expiration.
+func (m User) NewAuthToken(exp int64, session ...*UserSession) (string, error) {
 	claims := jwt.MapClaims{
 		"id":    m.ID.Hex(),
 		"model": "user",
 		"exp":   exp,
 	}
 
+	if len(session) > 0 && session[0] != nil {
+		claims["jti"] = session[0].ID.Hex()
+		claims["iat"] = time.Now().Unix()
+	}
+
 	signingKey := app.Config.GetString("jwt.signingKey")
 
 	return auth.NewJWT(claims, signingKey)
 }
 
+// IsTokenRevoked checks whether a token issued at `iat` was revoked
+// by a later password change or sign out from all sessions.
+func (m User) IsTokenRevoked(iat int64) bool {
+	return iat < m.TokensRevoked
+}
+
 // HasValidResetPasswordHash checks whether the model reset password hash is valid.
 func (m User) HasValidResetPasswordHash() bool {
 	parts := strings.SplitN(m.ResetPasswordHash, "_", 2)
@@ -105,18 +139,153 @@ func (m *User) SetResetPasswordHash(exp int64) {
 	m.ResetPasswordHash = utils.MD5(m.ID.Hex()+secret) + "_" + strconv.FormatInt(exp, 10)
 }
 
+// -------------------------------------------------------------------
+// • User two-factor authentication
+// -------------------------------------------------------------------
+
+// NeedsTwoFactor checks whether the user has to complete a two-factor
+// authentication challenge (or enrollment) after a successful password check.
+func (m User) NeedsTwoFactor() bool {
+	return m.TwoFactorEnabled || m.RequireTwoFactor
+}
+
+// NewTwoFactorChallengeToken generates and returns new short-lived token
+// that identifies the user during the two-factor authentication step.
+func (m User) NewTwoFactorChallengeToken(exp int64) (string, error) {
+	claims := jwt.MapClaims{
+		"id":    m.ID.Hex(),
+		"model": UserTwoFactorChallengeModel,
+		"exp":   exp,
+	}
+
+	signingKey := app.Config.GetString("jwt.signingKey")
+
+	return auth.NewJWT(claims, signingKey)
+}
+
+// RenewTwoFactorPendingSecret generates and sets new TOTP secret that has to be
+// confirmed with a valid code before enabling the two-factor authentication.
+func (m *User) RenewTwoFactorPendingSecret() (string, error) {
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+
+	m.TwoFactorPendingSecret = secret
+
+	return secret, nil
+}
+
+// TwoFactorProvisioningURI returns the authenticator apps key URI of the provided TOTP secret.
+func (m User) TwoFactorProvisioningURI(secret string) string {
+	return utils.TOTPProvisioningURI(secret, app.Config.GetString("twoFactor.issuer"), m.Username)
+}
+
+// EnableTwoFactor confirms the pending TOTP secret with the provided code,
+// enables the two-factor authentication and returns new set of recovery codes.
+func (m *User) EnableTwoFactor(code string) ([]string, error) {
+	if m.TwoFactorPendingSecret == "" {
+		return nil, errors.New("Missing two-factor authentication enrollment.")
+	}
+
+	counter, valid := utils.ValidateTOTP(m.TwoFactorPendingSecret, code, time.Now(), 1)
+	if !valid {
+		return nil, errors.New("Invalid two-factor authentication code.")
+	}
+
+	codes, codesErr := m.RenewRecoveryCodes()
+	if codesErr != nil {
+		return nil, codesErr
+	}
+
+	m.TwoFactorEnabled = true
+	m.TwoFactorSecret = m.TwoFactorPendingSecret
+	m.TwoFactorPendingSecret = ""
+	m.TwoFactorLastCounter = counter
+
+	return codes, nil
+}
+
+// DisableTwoFactor disables the two-factor authentication and clears all related secrets.
+func (m *User) DisableTwoFactor() {
+	m.TwoFactorEnabled = false
+	m.TwoFactorSecret = ""
+	m.TwoFactorPendingSecret = ""
+	m.TwoFactorRecoveryCodes = []string{}
+	m.TwoFactorLastCounter = 0
+}
+
+// ValidateTwoFactorCode checks whether the provided TOTP code is valid and wasn't used before.
+// Returns the matched TOTP time step counter on success.
+func (m User) ValidateTwoFactorCode(code string) (int64, bool) {
+	if !m.TwoFactorEnabled || m.TwoFactorSecret == "" {
+		return 0, false
+	}
+
+	counter, valid := utils.ValidateTOTP(m.TwoFactorSecret, code, time.Now(), 1)
+	if !valid || counter <= m.TwoFactorLastCounter {
+		return 0, false
+	}
+
+	return counter, true
+}
+
+// MatchRecoveryCode checks whether the provided code is one of the unused
+// user recovery codes and returns its stored hash on success.
+func (m User) MatchRecoveryCode(code string) (string, bool) {
+	hash := hashRecoveryCode(code)
+
+	if m.TwoFactorEnabled && utils.StringInSlice(hash, m.TwoFactorRecoveryCodes) {
+		return hash, true
+	}
+
+	return "", false
+}
+
+// RenewRecoveryCodes generates and returns new set of one-time recovery codes
+// (only their hashes are stored, so the previous codes are no longer valid).
+func (m *User) RenewRecoveryCodes() ([]string, error) {
+	codes := make([]string, UserRecoveryCodesCount)
+	hashes := make([]string, UserRecoveryCodesCount)
+
+	for i := range codes {
+		random := make([]byte, 5)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+
+		code := hex.EncodeToString(random)
+
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	m.TwoFactorRecoveryCodes = hashes
+
+	return codes, nil
+}
+
+// hashRecoveryCode normalizes and returns the sha256 hash of a recovery code.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+
+	return utils.SHA256(code)
+}
+
 // -------------------------------------------------------------------
 // • User create form model
 // -------------------------------------------------------------------
 
 // UserCreateForm defines struct to create a new user.
 type UserCreateForm struct {
-	Username        string              `json:"username" form:"username"`
-	Email           string              `json:"email" form:"email"`
-	Status          string              `json:"status" form:"status"`
-	Password        string              `json:"password" form:"password"`
-	PasswordConfirm string              `json:"password_confirm" form:"password_confirm"`
-	Access          map[string][]string `json:"access" form:"access"`
+	Username         string              `json:"username" form:"username"`
+	Email            string              `json:"email" form:"email"`
+	Status           string              `json:"status" form:"status"`
+	Password         string              `json:"password" form:"password"`
+	PasswordConfirm  string              `json:"password_confirm" form:"password_confirm"`
+	Access           map[string][]string `json:"access" form:"access"`
+	Roles            []bson.ObjectId     `json:"roles" form:"roles"`
+	RequireTwoFactor bool                `json:"require_two_factor" form:"require_two_factor"`
 }
 
 // Validate validates user create form fields.
@@ -127,7 +296,9 @@ func (m UserCreateForm) Validate() error {
 		validation.Field(&m.Status, validation.Required, validation.In(UserStatusActive, UserStatusInactive)),
 		validation.Field(&m.Password, validation.Required),
 		validation.Field(&m.PasswordConfirm, validation.Required, validation.By(checkPasswordConfirm(m.Password))),
-		validation.Field(&m.Access, validation.Required),
+		validation.Field(&m.Access, accessRules(m.Roles)...),
+		validation.Field(&m.Roles, validation.By(checkRoleIds)),
+		validation.Field(&m.RequireTwoFactor, validation.By(checkTwoFactorRequirement(m.Access, m.Roles))),
 	)
 }
 
@@ -136,13 +307,19 @@ func (m UserCreateForm) ResolveModel() *User {
 	now := time.Now().Unix()
 
 	user := &User{
-		ID:       bson.NewObjectId(),
-		Username: m.Username,
-		Email:    m.Email,
-		Access:   m.Access,
-		Status:   m.Status,
-		Created:  now,
-		Modified: now,
+		ID:               bson.NewObjectId(),
+		Username:         m.Username,
+		Email:            m.Email,
+		Access:           m.Access,
+		Roles:            m.Roles,
+		Status:           m.Status,
+		RequireTwoFactor: m.RequireTwoFactor,
+		Created:          now,
+		Modified:         now,
+	}
+
+	if user.Roles == nil {
+		user.Roles = []bson.ObjectId{}
 	}
 
 	user.SetPassword(m.Password)
@@ -156,13 +333,15 @@ func (m UserCreateForm) ResolveModel() *User {
 
 // UserUpdateForm defines struct to update an user.
 type UserUpdateForm struct {
-	Model           *User               `json:"-" form:"-"`
-	Username        string              `json:"username" form:"username"`
-	Email           string              `json:"email" form:"email"`
-	Status          string              `json:"status" form:"status"`
-	Access          map[string][]string `json:"access" form:"access"`
-	Password        string              `json:"password" form:"password"`
-	PasswordConfirm string              `json:"password_confirm" form:"password_confirm"`
+	Model            *User               `json:"-" form:"-"`
+	Username         string              `json:"username" form:"username"`
+	Email            string              `json:"email" form:"email"`
+	Status           string              `json:"status" form:"status"`
+	Access           map[string][]string `json:"access" form:"access"`
+	Roles            []bson.ObjectId     `json:"roles" form:"roles"`
+	Password         string              `json:"password" form:"password"`
+	PasswordConfirm  string              `json:"password_confirm" form:"password_confirm"`
+	RequireTwoFactor bool                `json:"require_two_factor" form:"require_two_factor"`
 }
 
 // Validate validates user update form fields.
@@ -171,8 +350,10 @@ func (m UserUpdateForm) Validate() error {
 		validation.Field(&m.Username, validation.Required, validation.Length(3, 255), validation.Match(regexp.MustCompile(`^[\w\.]+$`))),
 		validation.Field(&m.Email, validation.Required, is.Email),
 		validation.Field(&m.Status, validation.Required, validation.In(UserStatusActive, UserStatusInactive)),
-		validation.Field(&m.Access, validation.Required),
+		validation.Field(&m.Access, accessRules(m.Roles)...),
+		validation.Field(&m.Roles, validation.By(checkRoleIds)),
 		validation.Field(&m.PasswordConfirm, validation.By(checkOptionalRequirement(m.Password)), validation.By(checkPasswordConfirm(m.Password))),
+		validation.Field(&m.RequireTwoFactor, validation.By(checkTwoFactorRequirement(m.Access, m.Roles))),
 	)
 }
 
@@ -188,6 +369,11 @@ func (m UserUpdateForm) ResolveModel() *User {
 	model.Email = m.Email
 	model.Status = m.Status
 	model.Access = m.Access
+	model.Roles = m.Roles
+	if model.Roles == nil {
+		model.Roles = []bson.ObjectId{}
+	}
+	model.RequireTwoFactor = m.RequireTwoFactor
 	model.Modified = time.Now().Unix()
 
 	// set new password
@@ -263,3 +449,23 @@ func checkOptionalRequirement(compareValue string) validation.RuleFunc {
 		return nil
 	}
 }
+
+// checkTwoFactorRequirement checks whether the two-factor authentication
+// is required only for users holding at least one of the `twoFactorAccessGroups`.
+// Users with roles are always allowed since the role permissions could be changed at any time.
+func checkTwoFactorRequirement(access map[string][]string, roles []bson.ObjectId) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(bool)
+		if !v || len(roles) > 0 {
+			return nil
+		}
+
+		for _, group := range twoFactorAccessGroups {
+			if len(access[group]) > 0 {
+				return nil
+			}
+		}
+
+		return errors.New("Two-factor authentication could be required only for users with user or key access.")
+	}
+}
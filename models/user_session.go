@@ -0,0 +1,88 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// UserSession defines the UserSession model fields.
+// A session is created on each user authentication and it holds the hash
+// of the currently valid refresh token (the token is rotated on each refresh).
+type UserSession struct {
+	ID          bson.ObjectId `json:"id" bson:"_id"`
+	UserID      bson.ObjectId `json:"user_id" bson:"user_id"`
+	RefreshHash string        `json:"-" bson:"refresh_hash"`
+	Expires     int64         `json:"expires" bson:"expires"`
+	Revoked     bool          `json:"revoked" bson:"revoked"`
+	Created     int64         `json:"created" bson:"created"`
+	Modified    int64         `json:"modified" bson:"modified"`
+	ExpireAt    time.Time     `json:"-" bson:"expire_at"` // used for the db auto removal of the expired sessions
+}
+
+// NewUserSession creates and returns new UserSession model for the provided user.
+func NewUserSession(user *User, exp int64) *UserSession {
+	now := time.Now().Unix()
+
+	return &UserSession{
+		ID:       bson.NewObjectId(),
+		UserID:   user.ID,
+		Expires:  exp,
+		Created:  now,
+		Modified: now,
+		ExpireAt: time.Unix(exp, 0),
+	}
+}
+
+// IsActive checks whether the session is not revoked or expired.
+func (m UserSession) IsActive() bool {
+	return !m.Revoked && m.Expires > time.Now().Unix()
+}
+
+// RenewRefreshToken generates and returns new session refresh token
+// (only its hash is stored, so the previous refresh token is no longer valid).
+func (m *UserSession) RenewRefreshToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	secretHex := hex.EncodeToString(secret)
+
+	m.RefreshHash = hashRefreshSecret(secretHex)
+	m.Modified = time.Now().Unix()
+
+	return m.ID.Hex() + "." + secretHex, nil
+}
+
+// ValidateRefreshToken checks whether the provided refresh token is the current session one.
+func (m UserSession) ValidateRefreshToken(token string) bool {
+	id, secret, ok := ParseRefreshToken(token)
+	if !ok || id != m.ID.Hex() || m.RefreshHash == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hashRefreshSecret(secret)), []byte(m.RefreshHash)) == 1
+}
+
+// ParseRefreshToken splits the provided refresh token into its session id and secret parts.
+func ParseRefreshToken(token string) (string, string, bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 || !bson.IsObjectIdHex(parts[0]) || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// hashRefreshSecret returns the sha256 hex hash of a refresh token secret.
+func hashRefreshSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(hash[:])
+}
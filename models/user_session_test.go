@@ -0,0 +1,124 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestNewUserSession(t *testing.T) {
+	user := &User{ID: bson.ObjectIdHex("5a7b15cd3fb9dc041c55b45d")}
+
+	session := NewUserSession(user, 100)
+
+	if !session.ID.Valid() {
+		t.Error("Expected the session id to be set")
+	}
+
+	if session.UserID != user.ID {
+		t.Errorf("Expected %v user id, got %v", user.ID, session.UserID)
+	}
+
+	if session.Expires != 100 || session.Revoked {
+		t.Error("Expected not revoked session with 100 expires, got", session)
+	}
+
+	if session.Created == 0 || session.Modified == 0 {
+		t.Error("Expected the session created and modified dates to be set")
+	}
+
+	if session.ExpireAt.Unix() != 100 {
+		t.Error("Expected the session expire_at date to match its expires, got", session.ExpireAt)
+	}
+}
+
+func TestUserSession_IsActive(t *testing.T) {
+	now := time.Now().Unix()
+
+	testScenarios := []struct {
+		Session  UserSession
+		Expected bool
+	}{
+		{UserSession{Expires: now + 100}, true},
+		{UserSession{Expires: now - 100}, false},
+		{UserSession{Expires: now + 100, Revoked: true}, false},
+	}
+
+	for i, scenario := range testScenarios {
+		if result := scenario.Session.IsActive(); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (scenario %d)", scenario.Expected, result, i)
+		}
+	}
+}
+
+func TestUserSession_RenewAndValidateRefreshToken(t *testing.T) {
+	session := &UserSession{ID: bson.ObjectIdHex("5b0d4d7ae138232e3b7d6a01")}
+
+	if session.ValidateRefreshToken("5b0d4d7ae138232e3b7d6a01.") {
+		t.Error("Expected the refresh token to be invalid for session without hash")
+	}
+
+	token1, err := session.RenewRefreshToken()
+	if err != nil {
+		t.Fatal("Expected nil, got error", err)
+	}
+
+	if !strings.HasPrefix(token1, "5b0d4d7ae138232e3b7d6a01.") {
+		t.Errorf("Expected the refresh token to start with the session id, got %s", token1)
+	}
+
+	if strings.Contains(session.RefreshHash, strings.SplitN(token1, ".", 2)[1]) {
+		t.Error("Expected only the refresh token secret hash to be stored")
+	}
+
+	if !session.ValidateRefreshToken(token1) {
+		t.Errorf("Expected %s refresh token to be valid", token1)
+	}
+
+	token2, _ := session.RenewRefreshToken()
+
+	if token1 == token2 {
+		t.Error("Expected new refresh token to be generated")
+	}
+
+	testScenarios := []struct {
+		Token    string
+		Expected bool
+	}{
+		{"", false},
+		{token1, false},
+		{token2, true},
+		{strings.Replace(token2, "5b0d4d7ae138232e3b7d6a01", "5b0d4d7ae138232e3b7d6a02", 1), false},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := session.ValidateRefreshToken(scenario.Token); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (token %s)", scenario.Expected, result, scenario.Token)
+		}
+	}
+}
+
+func TestParseRefreshToken(t *testing.T) {
+	testScenarios := []struct {
+		Token          string
+		ExpectedID     string
+		ExpectedSecret string
+		ExpectedOk     bool
+	}{
+		{"", "", "", false},
+		{"invalid.secret", "", "", false},
+		{"5b0d4d7ae138232e3b7d6a01", "", "", false},
+		{"5b0d4d7ae138232e3b7d6a01.", "", "", false},
+		{"5b0d4d7ae138232e3b7d6a01.secret.test", "5b0d4d7ae138232e3b7d6a01", "secret.test", true},
+	}
+
+	for _, scenario := range testScenarios {
+		id, secret, ok := ParseRefreshToken(scenario.Token)
+
+		if id != scenario.ExpectedID || secret != scenario.ExpectedSecret || ok != scenario.ExpectedOk {
+			t.Errorf("Expected (%s, %s, %v), got (%s, %s, %v)", scenario.ExpectedID, scenario.ExpectedSecret, scenario.ExpectedOk, id, secret, ok)
+		}
+	}
+}
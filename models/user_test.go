@@ -73,6 +73,43 @@ func TestUser_NewAuthToken(t *testing.T) {
 	if err != nil || string(claims) != expected {
 		t.Errorf("%s claims were expected, got %s (error: %v)", expected, string(claims), err)
 	}
+
+	// session token
+	session := &UserSession{ID: bson.ObjectIdHex("5b0d4d7ae138232e3b7d6a01")}
+
+	sessionToken, sessionErr := user.NewAuthToken(0, session)
+	if sessionErr != nil {
+		t.Fatal("Did not expect error, got ", sessionErr)
+	}
+
+	sessionClaims, _ := base64.RawURLEncoding.DecodeString(strings.Split(sessionToken, ".")[1])
+	for _, part := range []string{`"iat":`, `"jti":"5b0d4d7ae138232e3b7d6a01"`} {
+		if !strings.Contains(string(sessionClaims), part) {
+			t.Errorf("Expected %s claims to contain %s", string(sessionClaims), part)
+		}
+	}
+}
+
+func TestUser_IsTokenRevoked(t *testing.T) {
+	user := &User{TokensRevoked: 100}
+
+	testScenarios := map[int64]bool{
+		0:   true,
+		99:  true,
+		100: false,
+		101: false,
+	}
+
+	for iat, expected := range testScenarios {
+		if result := user.IsTokenRevoked(iat); result != expected {
+			t.Errorf("Expected %v, got %v (iat %d)", expected, result, iat)
+		}
+	}
+
+	// never revoked
+	if (&User{}).IsTokenRevoked(0) {
+		t.Error("Expected false, got true")
+	}
 }
 
 func TestUser_HasValidResetPasswordHash(t *testing.T) {
@@ -99,6 +136,198 @@ func TestUser_SetResetPasswordHash(t *testing.T) {
 	}
 }
 
+func TestUser_NeedsTwoFactor(t *testing.T) {
+	testScenarios := []struct {
+		Model    *User
+		Expected bool
+	}{
+		{&User{}, false},
+		{&User{TwoFactorEnabled: true}, true},
+		{&User{RequireTwoFactor: true}, true},
+	}
+
+	for _, scenario := range testScenarios {
+		if result := scenario.Model.NeedsTwoFactor(); result != scenario.Expected {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+}
+
+func TestUser_NewTwoFactorChallengeToken(t *testing.T) {
+	app.InitConfig("")
+
+	user := &User{ID: bson.ObjectIdHex("5a7b15cd3fb9dc041c55b45d")}
+
+	token, err := user.NewTwoFactorChallengeToken(1893456000)
+	if err != nil {
+		t.Fatal("Did not expect error, got ", err)
+	}
+
+	claims, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	expected := `{"exp":1893456000,"id":"5a7b15cd3fb9dc041c55b45d","model":"2fa"}`
+	if string(claims) != expected {
+		t.Errorf("%s claims were expected, got %s", expected, string(claims))
+	}
+}
+
+func TestUser_TwoFactorProvisioningURI(t *testing.T) {
+	app.InitConfig("")
+
+	user := &User{Username: "user1"}
+
+	expected := "otpauth://totp/Gofreta:user1?algorithm=SHA1&digits=6&issuer=Gofreta&period=30&secret=ABC"
+
+	if result := user.TwoFactorProvisioningURI("ABC"); result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestUser_EnableTwoFactor(t *testing.T) {
+	user := &User{}
+
+	// missing enrollment
+	if _, err := user.EnableTwoFactor("123456"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	secret, secretErr := user.RenewTwoFactorPendingSecret()
+	if secretErr != nil || secret == "" || user.TwoFactorPendingSecret != secret {
+		t.Fatalf("Expected the pending secret to be set, got %q (error %v)", user.TwoFactorPendingSecret, secretErr)
+	}
+
+	// invalid code
+	if _, err := user.EnableTwoFactor("invalid"); err == nil || user.TwoFactorEnabled {
+		t.Fatal("Expected error, got nil")
+	}
+
+	counter := utils.TOTPCounter(time.Now())
+	code, _ := utils.TOTPCode(secret, counter)
+
+	codes, err := user.EnableTwoFactor(code)
+	if err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if len(codes) != UserRecoveryCodesCount || len(user.TwoFactorRecoveryCodes) != UserRecoveryCodesCount {
+		t.Errorf("Expected %d recovery codes, got %v (%v)", UserRecoveryCodesCount, codes, user.TwoFactorRecoveryCodes)
+	}
+
+	if !user.TwoFactorEnabled || user.TwoFactorSecret != secret || user.TwoFactorPendingSecret != "" {
+		t.Errorf("Expected the pending secret to be enabled, got %v", user)
+	}
+
+	if user.TwoFactorLastCounter < counter {
+		t.Errorf("Expected the last counter to be at least %d, got %d", counter, user.TwoFactorLastCounter)
+	}
+}
+
+func TestUser_ValidateTwoFactorCode(t *testing.T) {
+	secret, _ := utils.NewTOTPSecret()
+
+	counter := utils.TOTPCounter(time.Now())
+	code, _ := utils.TOTPCode(secret, counter)
+
+	testScenarios := []struct {
+		Model       *User
+		Code        string
+		ExpectValid bool
+	}{
+		// disabled
+		{&User{TwoFactorSecret: secret}, code, false},
+		{&User{TwoFactorEnabled: true, TwoFactorSecret: secret}, "000000", false},
+		// already used time step
+		{&User{TwoFactorEnabled: true, TwoFactorSecret: secret, TwoFactorLastCounter: counter}, code, false},
+		{&User{TwoFactorEnabled: true, TwoFactorSecret: secret, TwoFactorLastCounter: counter - 2}, code, true},
+	}
+
+	for i, scenario := range testScenarios {
+		result, valid := scenario.Model.ValidateTwoFactorCode(scenario.Code)
+
+		if valid != scenario.ExpectValid {
+			t.Errorf("Expected %v, got %v (scenario %d)", scenario.ExpectValid, valid, i)
+		}
+
+		if valid && result != counter {
+			t.Errorf("Expected %d counter, got %d (scenario %d)", counter, result, i)
+		}
+	}
+}
+
+func TestUser_RenewRecoveryCodes(t *testing.T) {
+	user := &User{TwoFactorEnabled: true}
+
+	codes, err := user.RenewRecoveryCodes()
+	if err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if len(codes) != UserRecoveryCodesCount {
+		t.Fatalf("Expected %d codes, got %v", UserRecoveryCodesCount, codes)
+	}
+
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Invalid recovery code format %q", code)
+		}
+
+		// only the hashes should be stored
+		if user.TwoFactorRecoveryCodes[i] == code {
+			t.Errorf("Expected the recovery code %q to be hashed", code)
+		}
+	}
+}
+
+func TestUser_MatchRecoveryCode(t *testing.T) {
+	user := &User{TwoFactorEnabled: true}
+
+	codes, _ := user.RenewRecoveryCodes()
+
+	testScenarios := []struct {
+		Code        string
+		ExpectMatch bool
+	}{
+		{"", false},
+		{"missing", false},
+		{codes[0], true},
+		// normalized code
+		{" " + strings.ToUpper(strings.Replace(codes[1], "-", "", -1)) + " ", true},
+	}
+
+	for _, scenario := range testScenarios {
+		hash, match := user.MatchRecoveryCode(scenario.Code)
+
+		if match != scenario.ExpectMatch {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.ExpectMatch, match, scenario)
+		}
+
+		if match && !utils.StringInSlice(hash, user.TwoFactorRecoveryCodes) {
+			t.Errorf("Expected %s to be one of the user recovery codes hashes (scenario %v)", hash, scenario)
+		}
+	}
+
+	// disabled two-factor authentication
+	user.TwoFactorEnabled = false
+	if _, match := user.MatchRecoveryCode(codes[0]); match {
+		t.Error("Expected the recovery codes to be invalid")
+	}
+}
+
+func TestUser_DisableTwoFactor(t *testing.T) {
+	user := &User{
+		TwoFactorEnabled:       true,
+		TwoFactorSecret:        "test",
+		TwoFactorPendingSecret: "test",
+		TwoFactorRecoveryCodes: []string{"test"},
+		TwoFactorLastCounter:   1,
+	}
+
+	user.DisableTwoFactor()
+
+	if user.TwoFactorEnabled || user.TwoFactorSecret != "" || user.TwoFactorPendingSecret != "" || len(user.TwoFactorRecoveryCodes) != 0 || user.TwoFactorLastCounter != 0 {
+		t.Errorf("Expected the two-factor settings to be cleared, got %v", user)
+	}
+}
+
 func TestUserCreateForm_Validate(t *testing.T) {
 	// empty model
 	m1 := &UserCreateForm{}
@@ -123,10 +352,44 @@ func TestUserCreateForm_Validate(t *testing.T) {
 		Access:          map[string][]string{"test": []string{"update"}},
 	}
 
+	// two-factor requirement without user or key access
+	m4 := &UserCreateForm{
+		Username:         "admin",
+		Email:            "support@test.com",
+		Status:           "active",
+		Password:         "123456",
+		PasswordConfirm:  "123456",
+		Access:           map[string][]string{"test": []string{"update"}, "user": []string{}},
+		RequireTwoFactor: true,
+	}
+
+	// roles without own access
+	m5 := &UserCreateForm{
+		Username:        "admin",
+		Email:           "support@test.com",
+		Status:          "active",
+		Password:        "123456",
+		PasswordConfirm: "123456",
+		Roles:           []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")},
+	}
+
+	// duplicated roles
+	m6 := &UserCreateForm{
+		Username:        "admin",
+		Email:           "support@test.com",
+		Status:          "active",
+		Password:        "123456",
+		PasswordConfirm: "123456",
+		Roles:           []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001"), bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")},
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"username", "email", "status", "password", "password_confirm", "access"}},
 		{m2, []string{"username", "email", "status", "password_confirm", "access"}},
 		{m3, []string{}},
+		{m4, []string{"require_two_factor"}},
+		{m5, []string{}},
+		{m6, []string{"roles"}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -134,12 +397,14 @@ func TestUserCreateForm_Validate(t *testing.T) {
 
 func TestUserCreateForm_ResolveModel(t *testing.T) {
 	form := &UserCreateForm{
-		Username:        "admin",
-		Email:           "support@test.com",
-		Status:          "active",
-		Password:        "123456",
-		PasswordConfirm: "123456",
-		Access:          map[string][]string{"test": []string{"update"}},
+		Username:         "admin",
+		Email:            "support@test.com",
+		Status:           "active",
+		Password:         "123456",
+		PasswordConfirm:  "123456",
+		Access:           map[string][]string{"user": []string{"update"}},
+		Roles:            []bson.ObjectId{bson.ObjectIdHex("5b1a3c4de138234cd5a2e001")},
+		RequireTwoFactor: true,
 	}
 
 	user := form.ResolveModel()
@@ -164,6 +429,14 @@ func TestUserCreateForm_ResolveModel(t *testing.T) {
 		t.Errorf("Expected equal > 0 values for the timestamp modifiers, got: %d, %d", user.Created, user.Modified)
 	}
 
+	if !user.RequireTwoFactor {
+		t.Error("Expected the two-factor authentication to be required")
+	}
+
+	if len(user.Roles) != 1 || user.Roles[0] != form.Roles[0] {
+		t.Errorf("Expected exported roles to match with the form ones, got: %v VS %v", user.Roles, form.Roles)
+	}
+
 	equallAccessGroups(t, user.Access, form.Access)
 }
 
@@ -195,10 +468,21 @@ func TestUserUpdateForm_Validate(t *testing.T) {
 		PasswordConfirm: "1234",
 	}
 
+	// two-factor requirement with key access
+	m4 := &UserUpdateForm{
+		Model:            user,
+		Username:         "admin",
+		Email:            "support@test.com",
+		Status:           "active",
+		Access:           map[string][]string{"key": []string{"index"}},
+		RequireTwoFactor: true,
+	}
+
 	testScenarios := []TestValidateScenario{
 		{m1, []string{"username", "email", "status", "access"}},
 		{m2, []string{"username", "email", "status", "access", "password_confirm"}},
 		{m3, []string{}},
+		{m4, []string{}},
 	}
 
 	testValidateScenarios(t, testScenarios)
@@ -213,12 +497,13 @@ func TestUserUpdateForm_ResolveModel(t *testing.T) {
 			PasswordHash: "$2a$12$rdX7N6gpAzKJ/7DzCMyVdeRaTUv6faL6GxhTODzlJcuDHRf4hedoO",
 			Access:       map[string][]string{},
 		},
-		Username:        "admin",
-		Email:           "support@test.com",
-		Status:          "active",
-		Access:          map[string][]string{"test": []string{"update"}},
-		Password:        "1234",
-		PasswordConfirm: "1234",
+		Username:         "admin",
+		Email:            "support@test.com",
+		Status:           "active",
+		Access:           map[string][]string{"user": []string{"update"}},
+		Password:         "1234",
+		PasswordConfirm:  "1234",
+		RequireTwoFactor: true,
 	}
 
 	user := form.ResolveModel()
@@ -247,6 +532,10 @@ func TestUserUpdateForm_ResolveModel(t *testing.T) {
 		t.Error("Invalid password ", form.Password)
 	}
 
+	if !user.RequireTwoFactor {
+		t.Error("Expected the two-factor authentication to be required")
+	}
+
 	equallAccessGroups(t, user.Access, form.Access)
 }
 
@@ -331,6 +620,32 @@ func TestCheckOptionalRequirement(t *testing.T) {
 	}
 }
 
+func TestCheckTwoFactorRequirement(t *testing.T) {
+	testScenarios := []struct {
+		Access      map[string][]string
+		Roles       []bson.ObjectId
+		Value       bool
+		ExpectError bool
+	}{
+		{nil, nil, false, false},
+		{nil, nil, true, true},
+		{nil, []bson.ObjectId{bson.NewObjectId()}, true, false},
+		{map[string][]string{"media": []string{"index"}, "key": []string{}}, nil, true, true},
+		{map[string][]string{"user": []string{"index"}}, nil, true, false},
+		{map[string][]string{"key": []string{"view"}}, nil, true, false},
+	}
+
+	for _, scenario := range testScenarios {
+		err := checkTwoFactorRequirement(scenario.Access, scenario.Roles)(scenario.Value)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+	}
+}
+
 // -------------------------------------------------------------------
 // • Hepers
 // -------------------------------------------------------------------
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gofreta/gofreta-api/apis"
 	"github.com/gofreta/gofreta-api/app"
+	"github.com/gofreta/gofreta-api/daos"
+	"github.com/gofreta/gofreta-api/models"
+	"github.com/gofreta/gofreta-api/utils"
 
 	"github.com/globalsign/mgo"
 	routing "github.com/go-ozzo/ozzo-routing"
@@ -15,9 +20,49 @@ func bindRoutes(rg *routing.Router, session *mgo.Session) {
 	apis.InitUserApi(rg, session)
 	apis.InitCollectionApi(rg, session)
 	apis.InitEntityApi(rg, session)
+	apis.InitEntityRevisionApi(rg, session)
 	apis.InitMediaApi(rg, session)
 	apis.InitLanguageApi(rg, session)
 	apis.InitKeyApi(rg, session)
+	apis.InitRoleApi(rg, session)
+	apis.InitHookDeliveryApi(rg, session)
+	apis.InitSearchApi(rg, session)
+	apis.InitTranslationApi(rg, session)
+}
+
+// runPublishScheduler periodically publishes and unpublishes the scheduled entity items.
+func runPublishScheduler(session *mgo.Session, interval time.Duration) {
+	entityDAO := daos.NewEntityDAO(session)
+	hookDeliveryDAO := daos.NewHookDeliveryDAO(session)
+
+	author := models.RevisionAuthor{Model: "scheduler"}
+
+	for range time.Tick(interval) {
+		items, err := entityDAO.PublishScheduled(author)
+		if err != nil {
+			log.Printf("Publish scheduler error: %v", err)
+		}
+
+		for i, _ := range items {
+			collection, collectionErr := entityDAO.GetEntityCollection(&items[i])
+			if collectionErr != nil {
+				continue
+			}
+
+			go hookDeliveryDAO.Send(collection, collection.UpdateHook, utils.HookTypeEntity, utils.HookActionUpdate, &items[i])
+		}
+	}
+}
+
+// runHookDeliveryWorker periodically retries the pending hook deliveries.
+func runHookDeliveryWorker(session *mgo.Session, interval time.Duration) {
+	hookDeliveryDAO := daos.NewHookDeliveryDAO(session)
+
+	for range time.Tick(interval) {
+		if _, err := hookDeliveryDAO.DeliverPending(100); err != nil {
+			log.Printf("Hook delivery worker error: %v", err)
+		}
+	}
 }
 
 func main() {
@@ -25,8 +70,25 @@ func main() {
 
 	defer app.MongoSession.Close()
 
+	// apply the pending db upgrade steps
+	applied, migrateErr := daos.NewMigrationDAO(app.MongoSession).Run()
+	for _, name := range applied {
+		log.Printf("Applied migration %s", name)
+	}
+	if migrateErr != nil {
+		panic(migrateErr)
+	}
+
 	bindRoutes(app.Router, app.MongoSession)
 
+	if interval := app.Config.GetInt("hooks.interval"); interval > 0 {
+		go runHookDeliveryWorker(app.MongoSession, time.Duration(interval)*time.Second)
+	}
+
+	if interval := app.Config.GetInt("publishScheduler.interval"); interval > 0 {
+		go runPublishScheduler(app.MongoSession, time.Duration(interval)*time.Second)
+	}
+
 	http.Handle("/", app.Router)
 
 	http.ListenAndServe(app.Config.GetString("host"), nil)
@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+// cursorData defines the decoded keyset pagination cursor structure.
+type cursorData struct {
+	Sort   []string      `bson:"s"`
+	Values []interface{} `bson:"v"`
+}
+
+// IsCursorPagination checks whether the request opts in the keyset (cursor) pagination mode.
+// The mode is enabled by the presence of the `after` query parameter
+// (an empty `after` value fetches the first page).
+func IsCursorPagination(c *routing.Context) bool {
+	_, ok := c.Request.URL.Query()["after"]
+
+	return ok
+}
+
+// IsCountRequested checks whether the request asks for the total items count in cursor pagination mode.
+func IsCountRequested(c *routing.Context) bool {
+	return c.Query("count") == "1" || c.Query("count") == "true"
+}
+
+// CursorSortFields returns the sort fields that are used to build a cursor,
+// aka. the provided sort fields with `_id` as final tie-breaker.
+func CursorSortFields(sortData []string) []string {
+	result := []string{}
+
+	for _, field := range sortData {
+		if field == "" {
+			continue
+		}
+
+		result = append(result, field)
+
+		if cursorSortField(field) == "_id" {
+			return result
+		}
+	}
+
+	return append(result, "_id")
+}
+
+// NewCursor creates an opaque cursor from the `sortData` field values of the provided item.
+func NewCursor(item interface{}, sortData []string) (string, error) {
+	raw, err := bson.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+
+	data := cursorData{Sort: sortData, Values: []interface{}{}}
+
+	for _, field := range sortData {
+		data.Values = append(data.Values, pathValue(doc, cursorSortField(field)))
+	}
+
+	encoded, err := bson.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// GetCursorConditions decodes the request `after` cursor and returns the keyset conditions
+// that match all items after the cursor position. Returns nil if the cursor is empty.
+func GetCursorConditions(c *routing.Context, sortData []string) (bson.M, error) {
+	cursor := strings.TrimSpace(c.Query("after"))
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("Invalid cursor.")
+	}
+
+	data := cursorData{}
+	if err := bson.Unmarshal(raw, &data); err != nil || len(data.Values) != len(data.Sort) {
+		return nil, errors.New("Invalid cursor.")
+	}
+
+	if strings.Join(data.Sort, ",") != strings.Join(sortData, ",") {
+		return nil, errors.New("The cursor doesn't match the current sort order.")
+	}
+
+	// prevent query operators injection (eg. {"$ne": null})
+	for _, value := range data.Values {
+		if !isCursorValue(value) {
+			return nil, errors.New("Invalid cursor.")
+		}
+	}
+
+	// (f1 > v1) || (f1 = v1 && f2 > v2) || ... (direction sensitive)
+	or := []bson.M{}
+
+	for i, field := range sortData {
+		condition := bson.M{}
+
+		for j := 0; j < i; j++ {
+			condition[cursorSortField(sortData[j])] = data.Values[j]
+		}
+
+		after := cursorAfterCondition(field, data.Values[i])
+		if after == nil {
+			continue
+		}
+
+		for key, val := range after {
+			condition[key] = val
+		}
+
+		or = append(or, condition)
+	}
+
+	if len(or) == 0 {
+		// there are no items after the cursor
+		return bson.M{"_id": bson.M{"$exists": false}}, nil
+	}
+
+	return bson.M{"$or": or}, nil
+}
+
+// SetCursorHeaders sets the keyset pagination related headers.
+// The total count header is set only if `total` is not negative (aka. was requested).
+func SetCursorHeaders(c *routing.Context, limit int, total int, nextCursor string) {
+	// items per page
+	c.Response.Header().Set("X-Pagination-Per-Page", strconv.Itoa(limit))
+
+	// total items
+	if total >= 0 {
+		c.Response.Header().Set("X-Pagination-Total-Count", strconv.Itoa(total))
+	}
+
+	// next page
+	if nextCursor != "" {
+		nextUrl := *c.Request.URL
+		query := nextUrl.Query()
+		query.Set("after", nextCursor)
+		nextUrl.RawQuery = query.Encode()
+
+		c.Response.Header().Set("X-Pagination-Next-Cursor", nextCursor)
+		c.Response.Header().Set("Link", "<"+nextUrl.String()+">; rel=\"next\"")
+	}
+}
+
+// AppendAndCondition appends `condition` to the `$and` list of the provided conditions.
+func AppendAndCondition(conditions bson.M, condition bson.M) {
+	and, _ := conditions["$and"].([]bson.M)
+
+	conditions["$and"] = append(and, condition)
+}
+
+// cursorSortField strips the order sign ("+", "-") of a sort field.
+func cursorSortField(field string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(field, "-"), "+")
+}
+
+// cursorAfterCondition returns the condition that matches the values after `value`
+// based on the sort field direction. Returns nil if there could be no such values.
+// NB! null (and missing) values are sorted before all other values.
+func cursorAfterCondition(field string, value interface{}) bson.M {
+	key := cursorSortField(field)
+	isDesc := strings.HasPrefix(field, "-")
+
+	if value == nil {
+		if isDesc {
+			return nil
+		}
+
+		return bson.M{key: bson.M{"$ne": nil}}
+	}
+
+	if isDesc {
+		return bson.M{"$or": []bson.M{
+			{key: bson.M{"$lt": value}},
+			{key: nil},
+		}}
+	}
+
+	return bson.M{key: bson.M{"$gt": value}}
+}
+
+// isCursorValue checks whether the provided decoded cursor value is
+// a plain scalar, ObjectId or time (aka. it couldn't be a query operator).
+func isCursorValue(value interface{}) bool {
+	switch value.(type) {
+	case nil, string, bool, int, int32, int64, float64, bson.ObjectId, time.Time:
+		return true
+	}
+
+	return false
+}
+
+// pathValue returns the value of a dot separated document path (or nil if missing).
+func pathValue(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(bson.M)
+		if !ok {
+			return nil
+		}
+
+		current = m[part]
+	}
+
+	return current
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	routing "github.com/go-ozzo/ozzo-routing"
+)
+
+func TestIsCursorPagination(t *testing.T) {
+	scenarios := []struct {
+		Url      string
+		Expected bool
+	}{
+		{"http://localhost:8080", false},
+		{"http://localhost:8080?page=2", false},
+		{"http://localhost:8080?after", true},
+		{"http://localhost:8080?after=", true},
+		{"http://localhost:8080?after=abc", true},
+	}
+
+	for _, s := range scenarios {
+		req := httptest.NewRequest("GET", s.Url, nil)
+		c := routing.NewContext(httptest.NewRecorder(), req)
+
+		if result := IsCursorPagination(c); result != s.Expected {
+			t.Errorf("Expected %v, got %v (%s)", s.Expected, result, s.Url)
+		}
+	}
+}
+
+func TestIsCountRequested(t *testing.T) {
+	scenarios := []struct {
+		Url      string
+		Expected bool
+	}{
+		{"http://localhost:8080", false},
+		{"http://localhost:8080?count=0", false},
+		{"http://localhost:8080?count=1", true},
+		{"http://localhost:8080?count=true", true},
+	}
+
+	for _, s := range scenarios {
+		req := httptest.NewRequest("GET", s.Url, nil)
+		c := routing.NewContext(httptest.NewRecorder(), req)
+
+		if result := IsCountRequested(c); result != s.Expected {
+			t.Errorf("Expected %v, got %v (%s)", s.Expected, result, s.Url)
+		}
+	}
+}
+
+func TestCursorSortFields(t *testing.T) {
+	scenarios := []struct {
+		SortData []string
+		Expected []string
+	}{
+		{nil, []string{"_id"}},
+		{[]string{"-created"}, []string{"-created", "_id"}},
+		{[]string{"-_id", "created"}, []string{"-_id"}},
+		{[]string{"title", "+_id"}, []string{"title", "+_id"}},
+	}
+
+	for i, s := range scenarios {
+		if result := CursorSortFields(s.SortData); !reflect.DeepEqual(result, s.Expected) {
+			t.Errorf("(%d) Expected %v, got %v", i, s.Expected, result)
+		}
+	}
+}
+
+func TestCursorConditions(t *testing.T) {
+	id := bson.ObjectIdHex("5a8bea3ae1382310bec8076b")
+
+	item := struct {
+		ID      bson.ObjectId                     `bson:"_id"`
+		Created int64                             `bson:"created"`
+		Data    map[string]map[string]interface{} `bson:"data"`
+	}{id, 100, map[string]map[string]interface{}{"en": {"title": "test"}}}
+
+	sortData := []string{"-created", "data.en.title", "data.en.missing", "_id"}
+
+	cursor, err := NewCursor(item, sortData)
+	if err != nil {
+		t.Fatal("Expected nil error, got", err)
+	}
+
+	// empty cursor
+	emptyReq := httptest.NewRequest("GET", "http://localhost:8080?after=", nil)
+	if result, err := GetCursorConditions(routing.NewContext(httptest.NewRecorder(), emptyReq), sortData); result != nil || err != nil {
+		t.Errorf("Expected nil result and error, got %v, %v", result, err)
+	}
+
+	// invalid cursor
+	invalidReq := httptest.NewRequest("GET", "http://localhost:8080?after=invalid", nil)
+	if _, err := GetCursorConditions(routing.NewContext(httptest.NewRecorder(), invalidReq), sortData); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// different sort order
+	req := httptest.NewRequest("GET", "http://localhost:8080?after="+cursor, nil)
+	if _, err := GetCursorConditions(routing.NewContext(httptest.NewRecorder(), req), []string{"created", "_id"}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// cursor with query operator value
+	injected := item
+	injected.Data = map[string]map[string]interface{}{"en": {"title": bson.M{"$ne": nil}}}
+
+	injectedCursor, _ := NewCursor(injected, sortData)
+	injectedReq := httptest.NewRequest("GET", "http://localhost:8080?after="+injectedCursor, nil)
+	if _, err := GetCursorConditions(routing.NewContext(httptest.NewRecorder(), injectedReq), sortData); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// valid cursor
+	result, err := GetCursorConditions(routing.NewContext(httptest.NewRecorder(), req), sortData)
+	if err != nil {
+		t.Fatal("Expected nil error, got", err)
+	}
+
+	expected := bson.M{"$or": []bson.M{
+		{"$or": []bson.M{{"created": bson.M{"$lt": int64(100)}}, {"created": nil}}},
+		{"created": int64(100), "data.en.title": bson.M{"$gt": "test"}},
+		{"created": int64(100), "data.en.title": "test", "data.en.missing": bson.M{"$ne": nil}},
+		{"created": int64(100), "data.en.title": "test", "data.en.missing": nil, "_id": bson.M{"$gt": id}},
+	}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestSetCursorHeaders(t *testing.T) {
+	// without total and next cursor
+	req1 := httptest.NewRequest("GET", "http://localhost:8080/media?after=", nil)
+	c1 := routing.NewContext(httptest.NewRecorder(), req1)
+
+	SetCursorHeaders(c1, 20, -1, "")
+
+	headers1 := c1.Response.Header()
+	if len(headers1) != 1 || headers1.Get("X-Pagination-Per-Page") != "20" {
+		t.Error("Expected only the per page header to be set, got", headers1)
+	}
+
+	// with total and next cursor
+	req2 := httptest.NewRequest("GET", "http://localhost:8080/media?after=old&limit=20", nil)
+	c2 := routing.NewContext(httptest.NewRecorder(), req2)
+
+	SetCursorHeaders(c2, 20, 100, "new")
+
+	headers2 := c2.Response.Header()
+
+	expected := map[string]string{
+		"X-Pagination-Per-Page":    "20",
+		"X-Pagination-Total-Count": "100",
+		"X-Pagination-Next-Cursor": "new",
+		"Link":                     `<http://localhost:8080/media?after=new&limit=20>; rel="next"`,
+	}
+
+	if len(headers2) != len(expected) {
+		t.Errorf("Expected %d headers, got %d", len(expected), len(headers2))
+	}
+
+	for key, val := range expected {
+		if headers2.Get(key) != val {
+			t.Errorf("Expected %s header to be %q, got %q", key, val, headers2.Get(key))
+		}
+	}
+}
+
+func TestAppendAndCondition(t *testing.T) {
+	conditions := bson.M{"status": "active"}
+
+	AppendAndCondition(conditions, bson.M{"a": 1})
+	AppendAndCondition(conditions, bson.M{"b": 2})
+
+	expected := bson.M{
+		"status": "active",
+		"$and":   []bson.M{{"a": 1}, {"b": 2}},
+	}
+
+	if !reflect.DeepEqual(conditions, expected) {
+		t.Errorf("Expected %v, got %v", expected, conditions)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 )
@@ -39,9 +41,10 @@ func NewDataError(data interface{}) *DataError {
 
 // ApiError defines the properties for a basic api error response.
 type ApiError struct {
-	Status  int         `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	Data    interface{}       `json:"data"`
+	Headers map[string]string `json:"-"`
 }
 
 // Error returns the error message.
@@ -54,6 +57,11 @@ func (e *ApiError) StatusCode() int {
 	return e.Status
 }
 
+// ResponseHeaders returns the additional error response headers (eg. `Retry-After`).
+func (e *ApiError) ResponseHeaders() map[string]string {
+	return e.Headers
+}
+
 // NewApiError creates and returns new normalized ApiError instance.
 func NewApiError(status int, message string, data interface{}) *ApiError {
 	message = Sentenize(message)
@@ -70,7 +78,7 @@ func NewApiError(status int, message string, data interface{}) *ApiError {
 		data = nil
 	}
 
-	return &ApiError{status, strings.TrimSpace(message), data}
+	return &ApiError{Status: status, Message: strings.TrimSpace(message), Data: data}
 }
 
 // NewNotFoundError creates and returns 404 ApiError.
@@ -90,3 +98,22 @@ func NewBadRequestError(message string, data interface{}) *ApiError {
 
 	return NewApiError(http.StatusBadRequest, message, data)
 }
+
+// NewTooManyRequestsError creates and returns 429 ApiError
+// with `Retry-After` header (in seconds) set to the provided duration.
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *ApiError {
+	if message == "" {
+		message = "Too many requests, please try again later."
+	}
+
+	err := NewApiError(http.StatusTooManyRequests, message, nil)
+
+	seconds := int64(retryAfter / time.Second)
+	if retryAfter%time.Second > 0 {
+		seconds++
+	}
+
+	err.Headers = map[string]string{"Retry-After": strconv.FormatInt(seconds, 10)}
+
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestDataErrorError(t *testing.T) {
@@ -41,7 +42,7 @@ func TestNewData_MarshalJSON(t *testing.T) {
 }
 
 func TestApiError_Error(t *testing.T) {
-	err := &ApiError{400, "Test error message", "test_data"}
+	err := &ApiError{400, "Test error message", "test_data", nil}
 
 	result := err.Error()
 
@@ -53,7 +54,7 @@ func TestApiError_Error(t *testing.T) {
 }
 
 func TestApiError_StatusCode(t *testing.T) {
-	err := &ApiError{400, "Test error message", "test_data"}
+	err := &ApiError{400, "Test error message", "test_data", nil}
 
 	result := err.StatusCode()
 
@@ -149,6 +150,36 @@ func TestNewBadRequestError(t *testing.T) {
 	}
 }
 
+func TestNewTooManyRequestsError(t *testing.T) {
+	testScenarios := []struct {
+		Message            string
+		RetryAfter         time.Duration
+		ExpectedMessage    string
+		ExpectedRetryAfter string
+	}{
+		{"", 0, "Too many requests, please try again later.", "0"},
+		{"custom error message", 90 * time.Second, "Custom error message.", "90"},
+		// rounded up to a second
+		{"", 1500 * time.Millisecond, "Too many requests, please try again later.", "2"},
+	}
+
+	for _, scenario := range testScenarios {
+		err := NewTooManyRequestsError(scenario.Message, scenario.RetryAfter)
+
+		if err.Status != http.StatusTooManyRequests {
+			t.Errorf("Expected %d status, got %d", http.StatusTooManyRequests, err.Status)
+		}
+
+		if err.Message != scenario.ExpectedMessage {
+			t.Errorf("Expected %s message, got %s", scenario.ExpectedMessage, err.Message)
+		}
+
+		if retryAfter := err.ResponseHeaders()["Retry-After"]; retryAfter != scenario.ExpectedRetryAfter {
+			t.Errorf("Expected %s Retry-After header, got %s", scenario.ExpectedRetryAfter, retryAfter)
+		}
+	}
+}
+
 // -------------------------------------------------------------------
 // • Hepers
 // -------------------------------------------------------------------
@@ -0,0 +1,413 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// MaxFilterDepth defines the max allowed nesting level of a filter expression.
+const MaxFilterDepth = 10
+
+// filter expression token types
+const (
+	filterTokenEOF = iota
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenComma
+	filterTokenAnd
+	filterTokenOr
+	filterTokenNot
+	filterTokenOperator
+	filterTokenIdent
+	filterTokenString
+	filterTokenNumber
+)
+
+// filterToken defines single filter expression token.
+type filterToken struct {
+	Type  int
+	Value string
+	Pos   int
+}
+
+// filterOperators maps the supported filter comparison operators to their mongo equivalents.
+var filterOperators = map[string]string{
+	"=":  "",
+	"!=": "$ne",
+	">":  "$gt",
+	">=": "$gte",
+	"<":  "$lt",
+	"<=": "$lte",
+	"~":  "$regex",
+}
+
+// ParseFilter compiles the provided filter expression into bson find conditions.
+// Only fields matching one of the `validFields` patterns are allowed.
+//
+// Supported syntax:
+// - comparisons: `field = "value"`, `field != 1`, `>`, `>=`, `<`, `<=` and `~` (case insensitive contains)
+// - `field in ("a", "b", 1)`
+// - `field exists`
+// - logical `&&`, `||`, `!` and grouping with parentheses
+// - values: quoted strings, numbers, `true`, `false` and `null`
+//
+// Example: `status = "active" || (data.en.category in ("a", "b") && !data.en.hidden exists)`
+func ParseFilter(expression string, validFields []string) (bson.M, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, validFields: validFields}
+
+	result, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := p.peek(); token.Type != filterTokenEOF {
+		return nil, fmt.Errorf("Unexpected %q at position %d.", token.Value, token.Pos)
+	}
+
+	return result, nil
+}
+
+// tokenizeFilter splits the filter expression into tokens.
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	tokens := []filterToken{}
+
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{filterTokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{filterTokenRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{filterTokenComma, ",", i})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("Unexpected %q at position %d.", string(r), i)
+			}
+
+			tokenType := filterTokenAnd
+			if r == '|' {
+				tokenType = filterTokenOr
+			}
+
+			tokens = append(tokens, filterToken{tokenType, string(runes[i : i+2]), i})
+			i += 2
+		case r == '!' || r == '=' || r == '>' || r == '<' || r == '~':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != '~' {
+				op += "="
+			}
+
+			if op == "!" {
+				tokens = append(tokens, filterToken{filterTokenNot, op, i})
+			} else {
+				tokens = append(tokens, filterToken{filterTokenOperator, op, i})
+			}
+
+			i += len(op)
+		case r == '"' || r == '\'':
+			start := i
+			value := []rune{}
+			closed := false
+
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					value = append(value, runes[i])
+				} else if runes[i] == r {
+					closed = true
+					i++
+					break
+				} else {
+					value = append(value, runes[i])
+				}
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("Unterminated string at position %d.", start)
+			}
+
+			tokens = append(tokens, filterToken{filterTokenString, string(value), start})
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			start := i
+			for i++; i < len(runes) && (runes[i] == '.' || unicode.IsDigit(runes[i])); i++ {
+			}
+
+			tokens = append(tokens, filterToken{filterTokenNumber, string(runes[start:i]), start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i++; i < len(runes) && (runes[i] == '_' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])); i++ {
+			}
+
+			tokens = append(tokens, filterToken{filterTokenIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("Unexpected %q at position %d.", string(r), i)
+		}
+	}
+
+	tokens = append(tokens, filterToken{filterTokenEOF, "end of expression", len(runes)})
+
+	return tokens, nil
+}
+
+// filterParser defines a recursive descent filter expression parser.
+type filterParser struct {
+	tokens      []filterToken
+	pos         int
+	validFields []string
+}
+
+// peek returns the current token without consuming it.
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.pos]
+
+	if token.Type != filterTokenEOF {
+		p.pos++
+	}
+
+	return token
+}
+
+// expect consumes the current token and checks whether it is from the specified type.
+func (p *filterParser) expect(tokenType int, name string) (filterToken, error) {
+	token := p.next()
+
+	if token.Type != tokenType {
+		return token, fmt.Errorf("Expected %s, got %q at position %d.", name, token.Value, token.Pos)
+	}
+
+	return token, nil
+}
+
+// parseOr parses `and ("||" and)*` expressions.
+func (p *filterParser) parseOr(depth int) (bson.M, error) {
+	items := []bson.M{}
+
+	for {
+		item, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+
+		if p.peek().Type != filterTokenOr {
+			break
+		}
+
+		p.next()
+	}
+
+	if len(items) == 1 {
+		return items[0], nil
+	}
+
+	return bson.M{"$or": items}, nil
+}
+
+// parseAnd parses `unary ("&&" unary)*` expressions.
+func (p *filterParser) parseAnd(depth int) (bson.M, error) {
+	items := []bson.M{}
+
+	for {
+		item, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+
+		if p.peek().Type != filterTokenAnd {
+			break
+		}
+
+		p.next()
+	}
+
+	if len(items) == 1 {
+		return items[0], nil
+	}
+
+	return bson.M{"$and": items}, nil
+}
+
+// parseUnary parses `"!" unary | "(" or ")" | condition` expressions.
+func (p *filterParser) parseUnary(depth int) (bson.M, error) {
+	if depth > MaxFilterDepth {
+		return nil, fmt.Errorf("The filter expression exceeds the max allowed nesting level of %d.", MaxFilterDepth)
+	}
+
+	switch p.peek().Type {
+	case filterTokenNot:
+		p.next()
+
+		item, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.M{"$nor": []bson.M{item}}, nil
+	case filterTokenLParen:
+		p.next()
+
+		item, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(filterTokenRParen, "\")\""); err != nil {
+			return nil, err
+		}
+
+		return item, nil
+	}
+
+	return p.parseCondition()
+}
+
+// parseCondition parses `field operator value`, `field in (values)` and `field exists` expressions.
+func (p *filterParser) parseCondition() (bson.M, error) {
+	fieldToken, err := p.expect(filterTokenIdent, "field name")
+	if err != nil {
+		return nil, err
+	}
+
+	field := fieldToken.Value
+
+	if !p.isValidField(field) {
+		return nil, fmt.Errorf("Filtering by %q is not allowed.", field)
+	}
+
+	token := p.next()
+
+	switch {
+	case token.Type == filterTokenIdent && token.Value == "exists":
+		return bson.M{field: bson.M{"$exists": true}}, nil
+	case token.Type == filterTokenIdent && token.Value == "in":
+		if _, err := p.expect(filterTokenLParen, "\"(\""); err != nil {
+			return nil, err
+		}
+
+		values := []interface{}{}
+
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+
+			if p.peek().Type != filterTokenComma {
+				break
+			}
+
+			p.next()
+		}
+
+		if _, err := p.expect(filterTokenRParen, "\")\""); err != nil {
+			return nil, err
+		}
+
+		return bson.M{field: bson.M{"$in": values}}, nil
+	case token.Type == filterTokenOperator:
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		operator := filterOperators[token.Value]
+
+		if operator == "" {
+			return bson.M{field: value}, nil
+		}
+
+		if operator == "$regex" {
+			str, isString := value.(string)
+			if !isString {
+				return nil, fmt.Errorf("The \"~\" operator requires a string value (position %d).", token.Pos)
+			}
+
+			return bson.M{field: bson.RegEx{Pattern: regexp.QuoteMeta(str), Options: "i"}}, nil
+		}
+
+		return bson.M{field: bson.M{operator: value}}, nil
+	}
+
+	return nil, fmt.Errorf("Expected operator, \"in\" or \"exists\", got %q at position %d.", token.Value, token.Pos)
+}
+
+// parseValue parses a single literal value.
+func (p *filterParser) parseValue() (interface{}, error) {
+	token := p.next()
+
+	switch token.Type {
+	case filterTokenString:
+		if bson.IsObjectIdHex(token.Value) {
+			return bson.ObjectIdHex(token.Value), nil
+		}
+
+		return token.Value, nil
+	case filterTokenNumber:
+		number, err := strconv.ParseFloat(token.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q at position %d.", token.Value, token.Pos)
+		}
+
+		return number, nil
+	case filterTokenIdent:
+		switch token.Value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Expected value, got %q at position %d.", token.Value, token.Pos)
+}
+
+// isValidField checks whether the field name matches one of the parser valid field patterns.
+func (p *filterParser) isValidField(field string) bool {
+	for _, validField := range p.validFields {
+		pattern, patternErr := regexp.Compile("^" + validField + "$")
+		if patternErr != nil {
+			continue
+		}
+
+		if pattern.MatchString(field) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestParseFilter(t *testing.T) {
+	validFields := []string{"_id", "status", "title", "count", `data\.\w+\.\w+`}
+
+	testScenarios := []struct {
+		Expression  string
+		Expected    bson.M
+		ExpectError bool
+	}{
+		// empty
+		{"", nil, false},
+		{"   ", nil, false},
+		// invalid expressions
+		{"status", nil, true},
+		{"status =", nil, true},
+		{"status = active", nil, true},
+		{`status = "active`, nil, true},
+		{`status = "active" &`, nil, true},
+		{`status = "active" &&`, nil, true},
+		{`(status = "active"`, nil, true},
+		{`status = "active")`, nil, true},
+		{`status in "active"`, nil, true},
+		{`status in ()`, nil, true},
+		{`title ~ 123`, nil, true},
+		{`count = 1.2.3`, nil, true},
+		{`status # "active"`, nil, true},
+		// not allowed fields
+		{`missing = 1`, nil, true},
+		{`data.en = 1`, nil, true},
+		{`where = "1" || missing exists`, nil, true},
+		// nesting limit
+		{"((((((((((((status = 1))))))))))))", nil, true},
+		{"!!!!!!!!!!!!status = 1", nil, true},
+		// comparisons
+		{`status = "active"`, bson.M{"status": "active"}, false},
+		{`status = 'active'`, bson.M{"status": "active"}, false},
+		{`title = "say \"hi\""`, bson.M{"title": `say "hi"`}, false},
+		{`_id = "5a8bea3ae1382310bec8076b"`, bson.M{"_id": bson.ObjectIdHex("5a8bea3ae1382310bec8076b")}, false},
+		{`count != 1`, bson.M{"count": bson.M{"$ne": 1.0}}, false},
+		{`count > -1.5`, bson.M{"count": bson.M{"$gt": -1.5}}, false},
+		{`count >= 1`, bson.M{"count": bson.M{"$gte": 1.0}}, false},
+		{`count < 1`, bson.M{"count": bson.M{"$lt": 1.0}}, false},
+		{`count <= 1`, bson.M{"count": bson.M{"$lte": 1.0}}, false},
+		{`count = null`, bson.M{"count": nil}, false},
+		{`data.en.active = true`, bson.M{"data.en.active": true}, false},
+		{`data.en.active=false`, bson.M{"data.en.active": false}, false},
+		{`title ~ "a.b"`, bson.M{"title": bson.RegEx{Pattern: `a\.b`, Options: "i"}}, false},
+		// in and exists
+		{`status in ("a", 'b', 1)`, bson.M{"status": bson.M{"$in": []interface{}{"a", "b", 1.0}}}, false},
+		{`data.en.image exists`, bson.M{"data.en.image": bson.M{"$exists": true}}, false},
+		// logical operators
+		{`!status = "a"`, bson.M{"$nor": []bson.M{{"status": "a"}}}, false},
+		{
+			`status = "a" && count > 1 && title exists`,
+			bson.M{"$and": []bson.M{{"status": "a"}, {"count": bson.M{"$gt": 1.0}}, {"title": bson.M{"$exists": true}}}},
+			false,
+		},
+		{
+			`status = "a" || status = "b" && count > 1`,
+			bson.M{"$or": []bson.M{{"status": "a"}, {"$and": []bson.M{{"status": "b"}, {"count": bson.M{"$gt": 1.0}}}}}},
+			false,
+		},
+		{
+			`(status = "a" || status = "b") && !(data.en.tag in ("x"))`,
+			bson.M{"$and": []bson.M{
+				{"$or": []bson.M{{"status": "a"}, {"status": "b"}}},
+				{"$nor": []bson.M{{"data.en.tag": bson.M{"$in": []interface{}{"x"}}}}},
+			}},
+			false,
+		},
+	}
+
+	for i, scenario := range testScenarios {
+		result, err := ParseFilter(scenario.Expression, validFields)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("(%d) Expected error, got nil (%s)", i, scenario.Expression)
+			continue
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("(%d) Expected nil, got error %v (%s)", i, err, scenario.Expression)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, scenario.Expected) {
+			t.Errorf("(%d) Expected %v, got %v", i, scenario.Expected, result)
+		}
+	}
+}
@@ -1,7 +1,15 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 )
 
 const (
@@ -11,6 +19,9 @@ const (
 	// HookTypeEntity specify the `entity` type hook.
 	HookTypeEntity = "entity"
 
+	// HookTypeEntityBulk specify the `entity_bulk` type hook (the hook data is a list of entities).
+	HookTypeEntityBulk = "entity_bulk"
+
 	// HookActionCreate specify the `create` action hook.
 	HookActionCreate = "create"
 
@@ -19,6 +30,16 @@ const (
 
 	// HookActionDelete specify the `delete` action hook.
 	HookActionDelete = "delete"
+
+	// HookSignatureHeader specify the hook payload signature request header.
+	HookSignatureHeader = "X-Gofreta-Signature"
+
+	// HookDeliveryHeader specify the hook delivery id request header.
+	HookDeliveryHeader = "X-Gofreta-Delivery"
+
+	// HookTimestampHeader specify the hook delivery attempt unix timestamp request header
+	// (it is part of the signed content in order to prevent replaying captured deliveries).
+	HookTimestampHeader = "X-Gofreta-Timestamp"
 )
 
 // Hook defines the general hooks format structure.
@@ -52,3 +73,41 @@ func SendHook(url string, hookType string, hookAction string, hookData interface
 
 	return SendJsonPostData(url, bytes)
 }
+
+// SignHookPayload generates and returns hex encoded HMAC-SHA256 signature
+// of the provided hook timestamp and payload (aka. "{timestamp}.{payload}").
+func SignHookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendHookPayload sends a POST request with json hook payload to the specified url
+// and returns the response status code.
+// Returns an error if the request fails, exceeds `timeout` or the response status is not 2xx.
+func SendHookPayload(url string, payload []byte, headers map[string]string, timeout time.Duration) (int, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("Unexpected response status code %d.", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewHook(t *testing.T) {
@@ -58,3 +59,73 @@ func TestSendHook(t *testing.T) {
 
 	SendHook(ts.URL, HookTypeCollection, HookActionCreate, data)
 }
+
+func TestSignHookPayload(t *testing.T) {
+	testScenarios := []struct {
+		Secret    string
+		Timestamp int64
+		Payload   string
+		Expected  string
+	}{
+		{"", 1500000000, "", "sha256=56ceae557b59ddf92ef9f1a34cce0214918060b511746c3a823ed344d62f3fc7"},
+		{"secret", 1500000000, `{"type":"entity"}`, "sha256=e9e7157b6966eb5ca5c23da6db1d785ea663f79fe7d48b62fe899add13f8b7a1"},
+	}
+
+	for _, scenario := range testScenarios {
+		result := SignHookPayload(scenario.Secret, scenario.Timestamp, []byte(scenario.Payload))
+
+		if result != scenario.Expected {
+			t.Errorf("Expected %s, got %s (scenario %v)", scenario.Expected, result, scenario)
+		}
+	}
+
+	if SignHookPayload("secret1", 1500000000, []byte("test")) == SignHookPayload("secret2", 1500000000, []byte("test")) {
+		t.Error("Expected different signatures for different secrets")
+	}
+
+	if SignHookPayload("secret", 1500000000, []byte("test")) == SignHookPayload("secret", 1500000001, []byte("test")) {
+		t.Error("Expected different signatures for different timestamps")
+	}
+}
+
+func TestSendHookPayload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HookSignatureHeader) != "test_signature" {
+			t.Error("Expected signature header to be set, got", r.Header.Get(HookSignatureHeader))
+		}
+
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	headers := map[string]string{HookSignatureHeader: "test_signature"}
+
+	testScenarios := []struct {
+		Url          string
+		ExpectedCode int
+		ExpectError  bool
+	}{
+		{ts.URL + "/success", 200, false},
+		{ts.URL + "/error", 500, true},
+		{ts.URL + "/slow", 0, true},
+	}
+
+	for _, scenario := range testScenarios {
+		code, err := SendHookPayload(scenario.Url, []byte(`{}`), headers, 100*time.Millisecond)
+
+		if scenario.ExpectError && err == nil {
+			t.Errorf("Expected error, got nil (scenario %v)", scenario)
+		} else if !scenario.ExpectError && err != nil {
+			t.Errorf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if code != scenario.ExpectedCode {
+			t.Errorf("Expected %d code, got %d (scenario %v)", scenario.ExpectedCode, code, scenario)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter defines the interface of a failed attempts limiter
+// that temporary locks out a key (eg. client ip or username)
+// after too many attempts within a single time window.
+type RateLimiter interface {
+	// Check returns the remaining lockout duration of the provided key (0 if the key is not locked).
+	Check(key string) (time.Duration, error)
+
+	// Hit registers a new attempt for the provided key and
+	// returns the lockout duration if the attempts limit is reached.
+	Hit(key string) (time.Duration, error)
+
+	// Reset clears the registered attempts and lockout of the provided key.
+	Reset(key string) error
+}
+
+// RateLimitOptions defines the common rate limiter settings.
+type RateLimitOptions struct {
+	// MaxAttempts is the number of allowed attempts within a single window.
+	MaxAttempts int
+
+	// Window is the attempts counting time window.
+	Window time.Duration
+
+	// Lockout is the lockout duration after reaching the attempts limit.
+	Lockout time.Duration
+}
+
+// -------------------------------------------------------------------
+// • MemoryRateLimiter
+// -------------------------------------------------------------------
+
+// memoryRateLimitEntry defines the attempts state of a single MemoryRateLimiter key.
+type memoryRateLimitEntry struct {
+	count         int
+	windowExpires time.Time
+	lockedUntil   time.Time
+}
+
+// MemoryRateLimiter is an in-memory RateLimiter implementation
+// (suitable only for single instance deployments).
+type MemoryRateLimiter struct {
+	options     RateLimitOptions
+	mux         sync.Mutex
+	entries     map[string]*memoryRateLimitEntry
+	lastCleanup time.Time
+}
+
+// NewMemoryRateLimiter creates and returns new MemoryRateLimiter.
+func NewMemoryRateLimiter(options RateLimitOptions) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		options:     options,
+		entries:     map[string]*memoryRateLimitEntry{},
+		lastCleanup: time.Now(),
+	}
+}
+
+// Check implements RateLimiter.Check.
+func (l *MemoryRateLimiter) Check(key string) (time.Duration, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return 0, nil
+	}
+
+	return lockoutRemaining(entry.lockedUntil, time.Now()), nil
+}
+
+// Hit implements RateLimiter.Hit.
+func (l *MemoryRateLimiter) Hit(key string) (time.Duration, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+
+	l.cleanup(now)
+
+	entry, ok := l.entries[key]
+	if !ok || (entry.windowExpires.Before(now) && entry.lockedUntil.Before(now)) {
+		entry = &memoryRateLimitEntry{windowExpires: now.Add(l.options.Window)}
+		l.entries[key] = entry
+	}
+
+	entry.count++
+
+	if entry.count >= l.options.MaxAttempts && entry.lockedUntil.Before(now) {
+		entry.lockedUntil = now.Add(l.options.Lockout)
+
+		// start counting again after the lockout
+		entry.count = 0
+		entry.windowExpires = entry.lockedUntil
+	}
+
+	return lockoutRemaining(entry.lockedUntil, now), nil
+}
+
+// Reset implements RateLimiter.Reset.
+func (l *MemoryRateLimiter) Reset(key string) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	delete(l.entries, key)
+
+	return nil
+}
+
+// cleanup removes the expired entries (at most once per window).
+// NB! Need to be called while holding the lock.
+func (l *MemoryRateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.options.Window {
+		return
+	}
+
+	for key, entry := range l.entries {
+		if entry.windowExpires.Before(now) && entry.lockedUntil.Before(now) {
+			delete(l.entries, key)
+		}
+	}
+
+	l.lastCleanup = now
+}
+
+// lockoutRemaining returns the remaining duration until `lockedUntil` (rounded up to a second).
+func lockoutRemaining(lockedUntil time.Time, now time.Time) time.Duration {
+	if !lockedUntil.After(now) {
+		return 0
+	}
+
+	remaining := lockedUntil.Sub(now)
+
+	if rounded := remaining.Truncate(time.Second); rounded < remaining {
+		return rounded + time.Second
+	}
+
+	return remaining
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimiter(t *testing.T) {
+	limiter := NewMemoryRateLimiter(RateLimitOptions{
+		MaxAttempts: 3,
+		Window:      time.Minute,
+		Lockout:     time.Hour,
+	})
+
+	for i := 1; i <= 2; i++ {
+		if lockout, err := limiter.Hit("test"); lockout != 0 || err != nil {
+			t.Fatalf("Expected no lockout after %d attempts, got %v (error %v)", i, lockout, err)
+		}
+	}
+
+	if lockout, _ := limiter.Check("test"); lockout != 0 {
+		t.Fatalf("Expected the key to not be locked, got %v", lockout)
+	}
+
+	// limit reached
+	if lockout, _ := limiter.Hit("test"); lockout <= 59*time.Minute || lockout > time.Hour {
+		t.Fatalf("Expected ~1h lockout, got %v", lockout)
+	}
+
+	if lockout, _ := limiter.Check("test"); lockout <= 59*time.Minute || lockout > time.Hour {
+		t.Fatalf("Expected the key to be locked, got %v", lockout)
+	}
+
+	// other keys shouldn't be affected
+	if lockout, _ := limiter.Check("other"); lockout != 0 {
+		t.Fatalf("Expected the other key to not be locked, got %v", lockout)
+	}
+
+	if err := limiter.Reset("test"); err != nil {
+		t.Fatal("Expected nil, got error ", err)
+	}
+
+	if lockout, _ := limiter.Check("test"); lockout != 0 {
+		t.Fatalf("Expected the key lockout to be reset, got %v", lockout)
+	}
+}
+
+func TestMemoryRateLimiter_Window(t *testing.T) {
+	limiter := NewMemoryRateLimiter(RateLimitOptions{
+		MaxAttempts: 2,
+		Window:      50 * time.Millisecond,
+		Lockout:     50 * time.Millisecond,
+	})
+
+	limiter.Hit("test")
+
+	// the previous attempt is outside of the window
+	time.Sleep(60 * time.Millisecond)
+
+	if lockout, _ := limiter.Hit("test"); lockout != 0 {
+		t.Fatalf("Expected no lockout, got %v", lockout)
+	}
+
+	if lockout, _ := limiter.Hit("test"); lockout == 0 {
+		t.Fatal("Expected lockout, got 0")
+	}
+
+	// the lockout has expired
+	time.Sleep(60 * time.Millisecond)
+
+	if lockout, _ := limiter.Check("test"); lockout != 0 {
+		t.Fatalf("Expected the lockout to be expired, got %v", lockout)
+	}
+
+	// the expired entries are removed on the next hit
+	limiter.Hit("other")
+
+	if _, ok := limiter.entries["test"]; ok {
+		t.Error("Expected the expired entry to be removed")
+	}
+}
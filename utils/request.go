@@ -118,6 +118,119 @@ func GetSearchConditions(c *routing.Context, validFields []string) bson.M {
 	return result
 }
 
+// AppendFilterConditions parses the request `filter` expression (see `ParseFilter()`)
+// and appends its conditions to the provided search conditions.
+func AppendFilterConditions(c *routing.Context, conditions bson.M, validFields []string) error {
+	filter, err := ParseFilter(c.Query("filter"), validFields)
+	if err != nil {
+		return err
+	}
+
+	if filter != nil {
+		AppendAndCondition(conditions, filter)
+	}
+
+	return nil
+}
+
+// RenameConditionKeys returns a copy of the provided find conditions with all field keys
+// replaced by the `rename` result (including the nested `$and`, `$or` and `$nor` conditions).
+func RenameConditionKeys(conditions bson.M, rename func(key string) string) bson.M {
+	result := bson.M{}
+
+	for key, val := range conditions {
+		if !strings.HasPrefix(key, "$") {
+			result[rename(key)] = val
+
+			continue
+		}
+
+		switch v := val.(type) {
+		case bson.M:
+			result[key] = RenameConditionKeys(v, rename)
+		case []bson.M:
+			list := make([]bson.M, len(v))
+			for i, item := range v {
+				list[i] = RenameConditionKeys(item, rename)
+			}
+			result[key] = list
+		default:
+			result[key] = val
+		}
+	}
+
+	return result
+}
+
+// EarthRadius defines the mean Earth radius in meters (used to convert distances to radians).
+const EarthRadius = 6378100.0
+
+// GetGeoConditions builds and returns bson geo spatial find conditions from a request.
+// Supported query parameters:
+// - `near[field]=lat,lng,radius` - matches all points within `radius` meters from the center point
+// - `within[field]=swLat,swLng,neLat,neLng` - matches all points within the specified box
+func GetGeoConditions(c *routing.Context, validFields []string) bson.M {
+	result := bson.M{}
+
+	queryParams := c.Request.URL.Query()
+
+	for _, field := range validFields {
+		for _, operator := range []string{"near", "within"} {
+			pattern, patternErr := regexp.Compile(`^` + operator + `\[` + field + `\]$`)
+			if patternErr != nil {
+				continue
+			}
+
+			for qKey, qVal := range queryParams {
+				if len(qVal) == 0 || !pattern.MatchString(qKey) {
+					continue
+				}
+
+				// remove "operator[" and "]" parts from the matched key
+				param := qKey[len(operator)+1 : len(qKey)-1]
+
+				values := []float64{}
+				for _, part := range strings.Split(qVal[0], ",") {
+					v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+					if err != nil {
+						break
+					}
+
+					values = append(values, v)
+				}
+
+				var geometry bson.M
+
+				if operator == "near" && len(values) == 3 && values[2] > 0 {
+					geometry = bson.M{"$centerSphere": []interface{}{
+						[]float64{values[1], values[0]},
+						values[2] / EarthRadius,
+					}}
+				} else if operator == "within" && len(values) == 4 {
+					swLat, swLng, neLat, neLng := values[0], values[1], values[2], values[3]
+
+					geometry = bson.M{"$geometry": bson.M{
+						"type": "Polygon",
+						"coordinates": [][][]float64{{
+							{swLng, swLat},
+							{neLng, swLat},
+							{neLng, neLat},
+							{swLng, neLat},
+							{swLng, swLat},
+						}},
+					}}
+				}
+
+				if geometry != nil {
+					result[param] = bson.M{"$geoWithin": geometry}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
 // GetSortFields formats and returns sort fields from a request.
 func GetSortFields(c *routing.Context, validFields []string) []string {
 	sortData := strings.Split(c.Query("sort"), ",")
@@ -149,6 +262,21 @@ func GetSortFields(c *routing.Context, validFields []string) []string {
 	return result
 }
 
+// GetListParam returns the trimmed non-empty values of a comma separated query parameter.
+func GetListParam(c *routing.Context, name string) []string {
+	result := []string{}
+
+	for _, val := range strings.Split(c.Query(name), ",") {
+		val = strings.TrimSpace(val)
+
+		if val != "" && !StringInSlice(val, result) {
+			result = append(result, val)
+		}
+	}
+
+	return result
+}
+
 // GetPaginationSettings extracts and returns common pagination settings from a request.
 func GetPaginationSettings(c *routing.Context, total int) (int, int) {
 	settings := &struct {
@@ -3,6 +3,7 @@ package utils
 import (
 	"math"
 	"net/http/httptest"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -100,6 +101,109 @@ func TestGetSearchConditions(t *testing.T) {
 	}
 }
 
+func TestAppendFilterConditions(t *testing.T) {
+	validFields := []string{"status"}
+
+	// invalid filter
+	invalidReq := httptest.NewRequest("GET", "http://localhost:8080/?filter=missing%3D1", nil)
+	if err := AppendFilterConditions(routing.NewContext(httptest.NewRecorder(), invalidReq), bson.M{}, validFields); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	// missing filter
+	conditions := bson.M{"status": "active"}
+	emptyReq := httptest.NewRequest("GET", "http://localhost:8080/", nil)
+	if err := AppendFilterConditions(routing.NewContext(httptest.NewRecorder(), emptyReq), conditions, validFields); err != nil {
+		t.Error("Expected nil, got error", err)
+	}
+	if len(conditions) != 1 {
+		t.Error("Expected the conditions to be unchanged, got", conditions)
+	}
+
+	// valid filter
+	req := httptest.NewRequest("GET", "http://localhost:8080/?filter=status%3D%22a%22%20%7C%7C%20status%3D%22b%22", nil)
+	if err := AppendFilterConditions(routing.NewContext(httptest.NewRecorder(), req), conditions, validFields); err != nil {
+		t.Error("Expected nil, got error", err)
+	}
+
+	expected := bson.M{
+		"status": "active",
+		"$and":   []bson.M{{"$or": []bson.M{{"status": "a"}, {"status": "b"}}}},
+	}
+
+	if !reflect.DeepEqual(conditions, expected) {
+		t.Errorf("Expected %v, got %v", expected, conditions)
+	}
+}
+
+func TestRenameConditionKeys(t *testing.T) {
+	conditions := bson.M{
+		"status": "active",
+		"price":  bson.M{"$gt": 1},
+		"$and": []bson.M{
+			{"$or": []bson.M{{"price": 2}, {"title": "a"}}},
+			{"$nor": []bson.M{{"price": nil}}},
+		},
+	}
+
+	result := RenameConditionKeys(conditions, func(key string) string {
+		if key == "price" {
+			return "shared.price"
+		}
+
+		return key
+	})
+
+	expected := bson.M{
+		"status":       "active",
+		"shared.price": bson.M{"$gt": 1},
+		"$and": []bson.M{
+			{"$or": []bson.M{{"shared.price": 2}, {"title": "a"}}},
+			{"$nor": []bson.M{{"shared.price": nil}}},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// the original conditions should be unchanged
+	if _, ok := conditions["price"]; !ok {
+		t.Error("Expected the original conditions to be unchanged, got", conditions)
+	}
+}
+
+func TestGetGeoConditions(t *testing.T) {
+	url := ("http://localhost:8080/" +
+		"?near[missing]=1,2,3" +
+		"&near[data.en.loc1]=42.5,23.3,1000" +
+		"&near[data.en.loc2]=42.5,23.3" + // missing radius
+		"&within[data.en.loc3]=40,20,45,25" +
+		"&within[data.en.loc4]=40,20,a,25" + // invalid coordinate
+		"&q[data.en.loc5]=42.5,23.3,1000")
+
+	req := httptest.NewRequest("GET", url, nil)
+	w := httptest.NewRecorder()
+	c := routing.NewContext(w, req)
+
+	result := GetGeoConditions(c, []string{`data\.\w+\.\w+`})
+
+	expected := bson.M{
+		"data.en.loc1": bson.M{"$geoWithin": bson.M{"$centerSphere": []interface{}{
+			[]float64{23.3, 42.5},
+			1000 / EarthRadius,
+		}}},
+		"data.en.loc3": bson.M{"$geoWithin": bson.M{"$geometry": bson.M{
+			"type":        "Polygon",
+			"coordinates": [][][]float64{{{20, 40}, {25, 40}, {25, 45}, {20, 45}, {20, 40}}},
+		}}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
 func TestGetSortFields(t *testing.T) {
 	// decoded sort: "missing,missing.sub,+firstname,lastname,data,data..sub,data..,-data.child.sub1,+data.child.sub2, data.child.sub3"
 	url := ("http://localhost:8080/?sort=missing%2Cmissing.sub%2C%2Bfirstname%2Clastname%2Cdata%2Cdata..sub%2Cdata..%2C-data.child.sub1%2C%2Bdata.child.sub2%2C%20data.child.sub3")
@@ -136,6 +240,27 @@ func TestGetSortFields(t *testing.T) {
 	}
 }
 
+func TestGetListParam(t *testing.T) {
+	scenarios := []struct {
+		Url      string
+		Expected []string
+	}{
+		{"http://localhost:8080", []string{}},
+		{"http://localhost:8080?fields=", []string{}},
+		{"http://localhost:8080?fields=title", []string{"title"}},
+		{"http://localhost:8080?fields=%20title%20,,cover,title", []string{"title", "cover"}},
+	}
+
+	for _, s := range scenarios {
+		req := httptest.NewRequest("GET", s.Url, nil)
+		c := routing.NewContext(httptest.NewRecorder(), req)
+
+		if result := GetListParam(c, "fields"); !reflect.DeepEqual(result, s.Expected) {
+			t.Errorf("Expected %v, got %v (%s)", s.Expected, result, s.Url)
+		}
+	}
+}
+
 func TestGetPaginationSettings(t *testing.T) {
 	app.InitConfig("")
 
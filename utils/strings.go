@@ -2,11 +2,14 @@ package utils
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"math/rand"
 	"strings"
 	"time"
 	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func init() {
@@ -20,6 +23,13 @@ func MD5(str string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// SHA256 hashes using sha256 algorithm
+func SHA256(str string) string {
+	hash := sha256.Sum256([]byte(str))
+
+	return hex.EncodeToString(hash[:])
+}
+
 // UcFirst converts the first character of a string into uppercase.
 func UcFirst(str string) string {
 	if str == "" {
@@ -74,3 +84,51 @@ func StringInSlice(str string, list []string) bool {
 
 	return false
 }
+
+// slugTransliterations defines the latin replacements of the letters
+// that can't be decomposed to a base latin letter (cyrillic, greek, ligatures, etc.).
+var slugTransliterations = map[rune]string{
+	// latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+	// cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sht",
+	'ъ': "a", 'ы': "y", 'ь': "y", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+	// greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Slugify transliterates and converts a string into a lowercase
+// url friendly slug (eg. "Здравей, Свят!" -> "zdravey-svyat").
+func Slugify(str string) string {
+	var builder strings.Builder
+
+	for _, r := range norm.NFC.String(strings.ToLower(str)) {
+		if replacement, ok := slugTransliterations[r]; ok {
+			builder.WriteString(replacement)
+
+			continue
+		}
+
+		// the decomposed form splits the accented letters into a base letter and combining mark(s)
+		for _, d := range norm.NFD.String(string(r)) {
+			if replacement, ok := slugTransliterations[d]; ok {
+				builder.WriteString(replacement)
+			} else if d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)) {
+				builder.WriteRune(d)
+			} else if !unicode.Is(unicode.Mn, d) {
+				builder.WriteRune('-')
+			}
+		}
+	}
+
+	// collapse the repeating separators
+	parts := strings.FieldsFunc(builder.String(), func(r rune) bool {
+		return r == '-'
+	})
+
+	return strings.Join(parts, "-")
+}
@@ -18,6 +18,19 @@ func TestMD5(t *testing.T) {
 	}
 }
 
+func TestSHA256(t *testing.T) {
+	result1 := SHA256("test")
+	result2 := SHA256("test")
+
+	if len(result1) != 64 {
+		t.Error("SHA256 strings should be 64 characters, got ", len(result1))
+	}
+
+	if result1 != result2 {
+		t.Error("Expected the hashes of the same string to be the equal")
+	}
+}
+
 func TestUcFirst(t *testing.T) {
 	// input -> output test pairs
 	pairs := map[string]string{
@@ -104,3 +117,24 @@ func TestStringInSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestSlugify(t *testing.T) {
+	// input -> output test pairs
+	pairs := map[string]string{
+		"":                      "",
+		"  --  ":                "",
+		"Hello World":           "hello-world",
+		"hello_world 123":       "hello-world-123",
+		"  Lorem -- ipsum!?  ":  "lorem-ipsum",
+		"Crème Brûlée à la ßar": "creme-brulee-a-la-ssar",
+		"Здравей, Свят!":        "zdravey-svyat",
+		"Καλημέρα":              "kalimera",
+		"already-a-slug":        "already-a-slug",
+	}
+
+	for input, output := range pairs {
+		if result := Slugify(input); result != output {
+			t.Errorf("Expected %s, got %s (%s)", output, result, input)
+		}
+	}
+}
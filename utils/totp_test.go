@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// base32 encoded RFC 6238 test secret ("12345678901234567890")
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret1, err1 := NewTOTPSecret()
+	secret2, err2 := NewTOTPSecret()
+
+	if err1 != nil || err2 != nil {
+		t.Fatalf("Expected nil, got errors %v, %v", err1, err2)
+	}
+
+	if len(secret1) != 32 {
+		t.Errorf("Expected 32 characters secret, got %q", secret1)
+	}
+
+	if secret1 == secret2 {
+		t.Error("Expected the secrets to be different")
+	}
+
+	if _, err := TOTPCode(secret1, 1); err != nil {
+		t.Error("Expected the secret to be valid base32 string, got error ", err)
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 SHA1 test vectors (truncated to 6 digits)
+	testScenarios := []struct {
+		Time     int64
+		Expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, scenario := range testScenarios {
+		code, err := TOTPCode(testTOTPSecret, TOTPCounter(time.Unix(scenario.Time, 0)))
+		if err != nil {
+			t.Fatalf("Expected nil, got error %v (scenario %v)", err, scenario)
+		}
+
+		if code != scenario.Expected {
+			t.Errorf("Expected %s code, got %s (scenario %v)", scenario.Expected, code, scenario)
+		}
+	}
+
+	// lowercase secret with spaces
+	if code, _ := TOTPCode(strings.ToLower(testTOTPSecret[:16])+" "+testTOTPSecret[16:], 1); code != "287082" {
+		t.Errorf("Expected the secret to be normalized, got %s code", code)
+	}
+
+	if _, err := TOTPCode("invalid!", 1); err == nil {
+		t.Error("Expected error for invalid secret, got nil")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	testScenarios := []struct {
+		Code            string
+		Skew            int64
+		ExpectValid     bool
+		ExpectedCounter int64
+	}{
+		{"", 1, false, 0},
+		{"12345", 1, false, 0},
+		{"000000", 1, false, 0},
+		{"081804", 0, true, 37037036},
+		{"081 804", 0, true, 37037036},
+		// next time step code
+		{"050471", 0, false, 0},
+		{"050471", 1, true, 37037037},
+	}
+
+	for _, scenario := range testScenarios {
+		counter, valid := ValidateTOTP(testTOTPSecret, scenario.Code, now, scenario.Skew)
+
+		if valid != scenario.ExpectValid {
+			t.Errorf("Expected %v, got %v (scenario %v)", scenario.ExpectValid, valid, scenario)
+		}
+
+		if counter != scenario.ExpectedCounter {
+			t.Errorf("Expected %d counter, got %d (scenario %v)", scenario.ExpectedCounter, counter, scenario)
+		}
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	result := TOTPProvisioningURI("ABC", "Gofreta", "test user")
+
+	expected := "otpauth://totp/Gofreta:test%20user?algorithm=SHA1&digits=6&issuer=Gofreta&period=30&secret=ABC"
+
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}